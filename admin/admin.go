@@ -0,0 +1,387 @@
+// Package admin provides an optional embedded web UI for inspecting and
+// managing leaderboards.
+//
+// Handler is meant to be mounted under a dedicated prefix, e.g.
+//
+//	http.Handle("/leaderboard-admin/", http.StripPrefix("/leaderboard-admin", admin.NewHandler(boards, cfg)))
+package admin
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	leaderboard "github.com/croatiangrn/go-redis-leaderboard"
+	"hash/fnv"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Config configures admin Handler.
+type Config struct {
+	// Authorize is called before every destructive action (e.g. removing a member).
+	// Destructive actions are disabled when Authorize is nil.
+	Authorize func(r *http.Request) bool
+	// MinPageSize and MaxPageSize bound page sizes clients may request with
+	// page_size, DefaultMinPageSize and DefaultMaxPageSize when zero.
+	MinPageSize int
+	MaxPageSize int
+}
+
+const (
+	DefaultMinPageSize = 10
+	DefaultMaxPageSize = 100
+)
+
+// pageSizes are page sizes accepted by leaderboard, ascending
+var pageSizes = []int{10, 25, 50, 100}
+
+// fields are names accepted by fields query param
+var fields = map[string]bool{
+	"user_id":         true,
+	"score":           true,
+	"rank":            true,
+	"formatted_score": true,
+	"additional_info": true,
+}
+
+// Handler serves the admin UI and the JSON API used by it.
+type Handler struct {
+	boards map[string]*leaderboard.Leaderboard
+	names  []string
+	config Config
+	static http.Handler
+}
+
+type boardStats struct {
+	Name         string `json:"name"`
+	PageSize     int    `json:"page_size"`
+	TotalMembers int    `json:"total_members"`
+	TotalPages   int    `json:"total_pages"`
+}
+
+// userView is User with score rendered in board's score unit
+type userView struct {
+	leaderboard.User
+	FormattedScore string `json:"formatted_score"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler creates admin Handler for given boards, keyed by the name shown in UI.
+func NewHandler(boards map[string]*leaderboard.Leaderboard, config Config) *Handler {
+	names := make([]string, 0, len(boards))
+	for name := range boards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if config.MinPageSize <= 0 {
+		config.MinPageSize = DefaultMinPageSize
+	}
+	if config.MaxPageSize <= 0 {
+		config.MaxPageSize = DefaultMaxPageSize
+	}
+
+	root, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	return &Handler{
+		boards: boards,
+		names:  names,
+		config: config,
+		static: http.FileServer(http.FS(root)),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		h.static.ServeHTTP(w, r)
+		return
+	}
+
+	// Routes:
+	//   GET    /api/boards
+	//   GET    /api/boards/<board>?page=<n>&page_size=<n>&fields=<f1,f2>   (honors If-None-Match)
+	//   GET    /api/boards/<board>/members/<userID>?fields=<f1,f2>
+	//   DELETE /api/boards/<board>/members/<userID>
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/"), "/")
+	if parts[0] != "boards" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.listBoards(w, r)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		h.withBoard(w, parts[1], func(l *leaderboard.Leaderboard) {
+			h.getPage(w, r, l)
+		})
+	case len(parts) == 4 && parts[2] == "members" && r.Method == http.MethodGet:
+		h.withBoard(w, parts[1], func(l *leaderboard.Leaderboard) {
+			h.getMember(w, r, l, parts[3])
+		})
+	case len(parts) == 4 && parts[2] == "members" && r.Method == http.MethodDelete:
+		if h.config.Authorize == nil || !h.config.Authorize(r) {
+			writeError(w, http.StatusForbidden, errors.New("forbidden"))
+			return
+		}
+
+		h.withBoard(w, parts[1], func(l *leaderboard.Leaderboard) {
+			h.removeMember(w, r, l, parts[3])
+		})
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (h *Handler) withBoard(w http.ResponseWriter, name string, fn func(l *leaderboard.Leaderboard)) {
+	l, ok := h.boards[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown leaderboard"))
+		return
+	}
+
+	fn(l)
+}
+
+func (h *Handler) listBoards(w http.ResponseWriter, r *http.Request) {
+	stats := make([]boardStats, 0, len(h.names))
+	for _, name := range h.names {
+		l := h.boards[name]
+
+		total, err := l.TotalMembers(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		stats = append(stats, boardStats{
+			Name:         name,
+			PageSize:     l.PageSize,
+			TotalMembers: total,
+			TotalPages:   l.TotalPages(r.Context()),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) getPage(w http.ResponseWriter, r *http.Request, l *leaderboard.Leaderboard) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+
+	pageSize, err := h.pageSize(r, l)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	selected, err := selectedFields(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	etag, err := pageETag(r, l)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// Info isn't read at all when it isn't selected
+	opts := []leaderboard.ReadOption{leaderboard.WithPageSize(pageSize)}
+	if selected != nil && !selected["additional_info"] {
+		opts = append(opts, leaderboard.WithoutInfo())
+	}
+	users, err := l.GetLeaders(r.Context(), page, opts...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	unit, err := l.ScoreUnit(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	views := make([]interface{}, len(users))
+	for i := range users {
+		views[i] = view(userView{User: users[i], FormattedScore: leaderboard.FormatScore(unit, users[i].Score)}, selected)
+	}
+
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (h *Handler) getMember(w http.ResponseWriter, r *http.Request, l *leaderboard.Leaderboard, userID string) {
+	selected, err := selectedFields(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := l.GetMember(r.Context(), userID, selected == nil || selected["additional_info"])
+	if err != nil && !errors.Is(err, leaderboard.ErrMemberNotFound) {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err != nil || user.Rank == leaderboard.UnrankedMember || (user.Ranked != nil && !*user.Ranked) {
+		writeError(w, http.StatusNotFound, errors.New("member not found"))
+		return
+	}
+
+	formatted, err := l.FormatScore(r.Context(), user.Score)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view(userView{User: user, FormattedScore: formatted}, selected))
+}
+
+// pageSize returns page size requested with page_size, clamped to configured
+// bounds and rounded down to a size accepted by leaderboard, or board's
+// PageSize when none is requested.
+func (h *Handler) pageSize(r *http.Request, l *leaderboard.Leaderboard) (int, error) {
+	value := r.URL.Query().Get("page_size")
+	if value == "" {
+		return l.PageSize, nil
+	}
+
+	requested, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("invalid page_size")
+	}
+
+	if requested < h.config.MinPageSize {
+		requested = h.config.MinPageSize
+	}
+	if requested > h.config.MaxPageSize {
+		requested = h.config.MaxPageSize
+	}
+
+	size := pageSizes[0]
+	for _, s := range pageSizes {
+		if s <= requested {
+			size = s
+		}
+	}
+
+	return size, nil
+}
+
+// pageETag returns ETag of page requested by r, derived from board's version
+// (see leaderboard.Leaderboard.Version) and the query, so it changes with
+// every write.
+func pageETag(r *http.Request, l *leaderboard.Leaderboard) (string, error) {
+	version, err := l.Version(r.Context())
+	if err != nil {
+		return "", err
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatInt(version, 10) + "?" + r.URL.RawQuery))
+
+	return `W/"` + strconv.FormatUint(h.Sum64(), 36) + `"`, nil
+}
+
+// etagMatches reports whether If-None-Match header lists etag.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectedFields parses comma separated fields query param, nil meaning all fields.
+func selectedFields(r *http.Request) (map[string]bool, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	selected := map[string]bool{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if !fields[field] {
+			return nil, errors.New("unknown field " + strconv.Quote(field))
+		}
+		selected[field] = true
+	}
+
+	return selected, nil
+}
+
+// view renders only selected fields of v, the whole v when selected is nil.
+func view(v userView, selected map[string]bool) interface{} {
+	if selected == nil {
+		return v
+	}
+
+	m := make(map[string]interface{}, len(selected))
+	for field := range selected {
+		switch field {
+		case "user_id":
+			m[field] = v.UserID
+		case "score":
+			m[field] = v.Score
+		case "rank":
+			m[field] = v.Rank
+		case "formatted_score":
+			m[field] = v.FormattedScore
+		case "additional_info":
+			m[field] = v.AdditionalInfo
+		}
+	}
+
+	return m
+}
+
+func (h *Handler) removeMember(w http.ResponseWriter, r *http.Request, l *leaderboard.Leaderboard, userID string) {
+	existed, err := l.RemoveMember(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !existed {
+		writeError(w, http.StatusNotFound, errors.New("member not found"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
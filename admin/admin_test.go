@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alicebob/miniredis/v2"
+	leaderboard "github.com/croatiangrn/go-redis-leaderboard"
+	"github.com/go-redis/redis/v8"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestHandler(t *testing.T, config Config) (*Handler, *leaderboard.Leaderboard) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = cli.Close()
+	})
+
+	l, err := leaderboard.NewLeaderboardWithClient(cli, leaderboard.StagingMode, "test", "test_info", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i, userID := range []string{"a", "b", "c"} {
+		if _, err := l.IncrementMemberScore(ctx, userID, 10*(i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewHandler(map[string]*leaderboard.Leaderboard{"test": l}, config), l
+}
+
+func serve(h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestListBoards(t *testing.T) {
+	h, _ := newTestHandler(t, Config{})
+
+	rec := serve(h, http.MethodGet, "/api/boards", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rec.Code)
+	}
+
+	var stats []boardStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].Name != "test" || stats[0].TotalMembers != 3 {
+		t.Fatalf("got %+v, want one board with 3 members", stats)
+	}
+}
+
+func TestGetPage(t *testing.T) {
+	h, _ := newTestHandler(t, Config{})
+
+	rec := serve(h, http.MethodGet, "/api/boards/test?page=1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", rec.Code)
+	}
+
+	var users []leaderboard.User
+	if err := json.Unmarshal(rec.Body.Bytes(), &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 || users[0].UserID != "c" || users[0].Rank != 1 {
+		t.Fatalf("got %+v, want c ranked first of 3", users)
+	}
+
+	if rec := serve(h, http.MethodGet, "/api/boards/unknown", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for unknown board, want 404", rec.Code)
+	}
+}
+
+func TestRemoveMemberRequiresAuthorization(t *testing.T) {
+	h, l := newTestHandler(t, Config{})
+
+	if rec := serve(h, http.MethodDelete, "/api/boards/test/members/a", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d without Authorize, want 403", rec.Code)
+	}
+
+	h.config.Authorize = func(r *http.Request) bool { return true }
+	if rec := serve(h, http.MethodDelete, "/api/boards/test/members/a", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want 204", rec.Code)
+	}
+	if rec := serve(h, http.MethodDelete, "/api/boards/test/members/a", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for removed member, want 404", rec.Code)
+	}
+
+	if total, _ := l.TotalMembers(context.Background()); total != 2 {
+		t.Fatalf("got %d members, want 2", total)
+	}
+}
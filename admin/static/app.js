@@ -0,0 +1,133 @@
+(function () {
+    "use strict";
+
+    var state = {board: null, page: 1, totalPages: 0};
+
+    function api(method, path) {
+        return fetch("api/" + path, {method: method}).then(function (res) {
+            if (res.status === 204) {
+                return null;
+            }
+
+            return res.json().then(function (body) {
+                if (!res.ok) {
+                    throw new Error(body.error || res.statusText);
+                }
+
+                return body;
+            });
+        });
+    }
+
+    function showError(err) {
+        var el = document.getElementById("error");
+        el.textContent = err ? err.message : "";
+        el.hidden = !err;
+    }
+
+    function cell(row, text) {
+        var td = document.createElement("td");
+        td.textContent = text;
+        row.appendChild(td);
+        return td;
+    }
+
+    function loadBoards() {
+        api("GET", "boards").then(function (boards) {
+            var body = document.querySelector("#boards tbody");
+            body.innerHTML = "";
+
+            boards.forEach(function (b) {
+                var row = document.createElement("tr");
+                row.className = "board";
+                cell(row, b.name);
+                cell(row, b.total_members);
+                cell(row, b.total_pages);
+                cell(row, b.page_size);
+                row.addEventListener("click", function () {
+                    openBoard(b);
+                });
+                body.appendChild(row);
+            });
+            showError(null);
+        }).catch(showError);
+    }
+
+    function openBoard(board) {
+        state.board = board.name;
+        state.page = 1;
+        state.totalPages = board.total_pages;
+        document.getElementById("board-name").textContent = board.name;
+        document.getElementById("member").textContent = "";
+        document.getElementById("board").hidden = false;
+        loadPage();
+    }
+
+    function loadPage() {
+        var path = "boards/" + encodeURIComponent(state.board) + "?page=" + state.page;
+        api("GET", path).then(function (users) {
+            var body = document.querySelector("#board tbody");
+            body.innerHTML = "";
+
+            users.forEach(function (u) {
+                var row = document.createElement("tr");
+                cell(row, u.rank);
+                cell(row, u.user_id);
+                cell(row, u.formatted_score);
+                cell(row, u.additional_info ? JSON.stringify(u.additional_info) : "");
+
+                var remove = document.createElement("button");
+                remove.className = "danger";
+                remove.textContent = "Remove";
+                remove.addEventListener("click", function () {
+                    removeMember(u.user_id);
+                });
+                cell(row, "").appendChild(remove);
+
+                body.appendChild(row);
+            });
+
+            document.getElementById("page").textContent = "Page " + state.page + " of " + state.totalPages;
+            showError(null);
+        }).catch(showError);
+    }
+
+    function removeMember(userID) {
+        if (!confirm("Remove " + userID + " from " + state.board + "?")) {
+            return;
+        }
+
+        var path = "boards/" + encodeURIComponent(state.board) + "/members/" + encodeURIComponent(userID);
+        api("DELETE", path).then(function () {
+            loadBoards();
+            loadPage();
+        }).catch(showError);
+    }
+
+    document.getElementById("lookup").addEventListener("submit", function (e) {
+        e.preventDefault();
+        var userID = e.target.elements.user_id.value;
+        var path = "boards/" + encodeURIComponent(state.board) + "/members/" + encodeURIComponent(userID);
+
+        api("GET", path).then(function (user) {
+            document.getElementById("member").textContent = JSON.stringify(user, null, 2);
+            showError(null);
+        }).catch(showError);
+    });
+
+    document.getElementById("prev").addEventListener("click", function () {
+        if (state.page > 1) {
+            state.page--;
+            loadPage();
+        }
+    });
+
+    document.getElementById("next").addEventListener("click", function () {
+        if (state.page < state.totalPages) {
+            state.page++;
+            loadPage();
+        }
+    });
+
+    loadBoards();
+})();
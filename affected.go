@@ -0,0 +1,245 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// metaBaselineAt holds time rank baseline of bulk changes was started, see NotifyAffected
+const metaBaselineAt = "baseline_at"
+
+// DefaultRankBaselineTTL is how long rank baseline is kept when WithRankBaseline is given no TTL.
+const DefaultRankBaselineTTL = 24 * time.Hour
+
+// Flags in meta KEYS[1] that baseline is started at ARGV[1] unless one started
+// less than ARGV[2] seconds ago is kept already. Returns 1 when flagged.
+var startBaselineScript = redis.NewScript(`
+local at = redis.call("HGET", KEYS[1], "` + metaBaselineAt + `")
+if at and tonumber(ARGV[1]) - tonumber(at) < tonumber(ARGV[2]) then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], "` + metaBaselineAt + `", ARGV[1])
+
+return 1
+`)
+
+// Moves baseline KEYS[1] to KEYS[2] (expiring after ARGV[1] seconds) and clears
+// the flag in meta KEYS[3], so bulk changes made from now on start a new
+// baseline. Returns -1 when no baseline is kept, 0 when baseline is empty.
+var takeBaselineScript = redis.NewScript(`
+if redis.call("HDEL", KEYS[3], "` + metaBaselineAt + `") == 0 then
+	return -1
+end
+
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+
+redis.call("RENAME", KEYS[1], KEYS[2])
+redis.call("EXPIRE", KEYS[2], ARGV[1])
+
+return 1
+`)
+
+// Puts baseline KEYS[1] taken into KEYS[2] back, expiring after ARGV[2]
+// seconds, unless a new one was started meanwhile.
+var restoreBaselineScript = redis.NewScript(`
+if redis.call("HSETNX", KEYS[3], "` + metaBaselineAt + `", ARGV[1]) == 0 then
+	return redis.call("DEL", KEYS[2])
+end
+
+redis.call("RENAME", KEYS[2], KEYS[1])
+
+return redis.call("EXPIRE", KEYS[1], ARGV[2])
+`)
+
+// baselineKey is copy of the board from before the first bulk change not yet notified.
+func (l *Leaderboard) baselineKey() string {
+	return l.leaderboardName + ":baseline"
+}
+
+// WithRankBaseline makes bulk operations keep rank baseline for NotifyAffected
+// while events are enabled. Baseline is a copy of the board taken before the
+// first bulk change after the previous NotifyAffected call and expires after
+// ttl (DefaultRankBaselineTTL when 0), after which the next bulk change starts
+// a new one.
+func WithRankBaseline(ttl time.Duration) Option {
+	return func(l *Leaderboard) {
+		if ttl <= 0 {
+			ttl = DefaultRankBaselineTTL
+		}
+		l.baselineTTL = ttl
+	}
+}
+
+// keepBaseline copies the board before bulk change unless changes since the
+// last NotifyAffected already have a baseline. The board is copied batch by
+// batch into a temporary key which then replaces the baseline, so writes made
+// while it's copied may already be in the baseline.
+func (l *Leaderboard) keepBaseline(ctx context.Context) error {
+	if l.baselineTTL <= 0 || !l.eventsEnabled() {
+		return nil
+	}
+
+	cli := l.client()
+	ttl := int(l.baselineTTL / time.Second)
+	started, err := startBaselineScript.Run(ctx, cli, []string{l.metaKey()}, time.Now().Unix(), ttl).Int()
+	if err != nil || started == 0 {
+		return err
+	}
+
+	if err := l.copyBaseline(ctx, cli); err != nil {
+		_ = cli.HDel(ctx, l.metaKey(), metaBaselineAt).Err()
+		return err
+	}
+
+	return nil
+}
+
+// copyBaseline copies the board into baseline using batches of rebuildBatchSize.
+func (l *Leaderboard) copyBaseline(ctx context.Context, cli redis.UniversalClient) error {
+	tmpKey, err := temporaryKey(l.baselineKey())
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for offset := 0; ; offset += rebuildBatchSize {
+		values, err := cli.ZRangeWithScores(ctx, l.leaderboardName, int64(offset), int64(offset+rebuildBatchSize-1)).Result()
+		if err != nil {
+			_ = cli.Del(ctx, tmpKey).Err()
+			return err
+		}
+		if len(values) == 0 {
+			break
+		}
+
+		pipe := cli.Pipeline()
+		pipe.ZAdd(ctx, tmpKey, zPointers(values)...)
+		pipe.Expire(ctx, tmpKey, rebuildKeyTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			_ = cli.Del(ctx, tmpKey).Err()
+			return err
+		}
+		total += len(values)
+
+		if len(values) < rebuildBatchSize {
+			break
+		}
+	}
+
+	// Empty board has empty baseline, which NotifyAffected treats as nothing to notify
+	pipe := cli.TxPipeline()
+	if total == 0 {
+		pipe.Del(ctx, l.baselineKey())
+	} else {
+		pipe.Rename(ctx, tmpKey, l.baselineKey())
+		pipe.Expire(ctx, l.baselineKey(), l.baselineTTL)
+	}
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// NotifyAffected emits rank_changed events for members whose rank shifted by
+// more than threshold places since the first bulk change (BulkLoad, Rebuild,
+// Reweigh, trims) after the previous NotifyAffected call, so backfills don't
+// flood consumers with an event per imported member. Bulk operations keep the
+// baseline only when board is created WithRankBaseline and events are enabled.
+// Members added or removed by bulk changes aren't notified. Returns number of
+// emitted events.
+func (l *Leaderboard) NotifyAffected(ctx context.Context, threshold int) (notified int, err error) {
+	done, err := l.trackOp("NotifyAffected")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	if !l.eventsEnabled() {
+		return 0, nil
+	}
+
+	cli := l.client()
+	tmpKey, err := temporaryKey(l.baselineKey())
+	if err != nil {
+		return 0, err
+	}
+
+	keys := []string{l.baselineKey(), tmpKey, l.metaKey()}
+	taken, err := takeBaselineScript.Run(ctx, cli, keys, int(rebuildKeyTTL/time.Second)).Int()
+	if err != nil || taken <= 0 {
+		return 0, err
+	}
+
+	notified, err = l.notifyShifted(ctx, tmpKey, threshold)
+	if err != nil {
+		_ = restoreBaselineScript.Run(ctx, cli, keys, time.Now().Unix(), int(l.baselineTTL/time.Second)).Err()
+		return notified, err
+	}
+
+	return notified, cli.Del(ctx, tmpKey).Err()
+}
+
+// notifyShifted compares ranks in baseline with ones on the board batch by batch.
+func (l *Leaderboard) notifyShifted(ctx context.Context, baseline string, threshold int) (int, error) {
+	cli := l.client()
+	notified := 0
+
+	for offset := 0; ; offset += rebuildBatchSize {
+		values, err := cli.ZRevRangeWithScores(ctx, baseline, int64(offset), int64(offset+rebuildBatchSize-1)).Result()
+		if err != nil {
+			return notified, err
+		}
+		if len(values) == 0 {
+			break
+		}
+
+		pipe := cli.Pipeline()
+		rankCmds := make([]*redis.IntCmd, len(values))
+		scoreCmds := make([]*redis.FloatCmd, len(values))
+		for i, z := range values {
+			rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, z.Member.(string))
+			scoreCmds[i] = pipe.ZScore(ctx, l.leaderboardName, z.Member.(string))
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return notified, err
+		}
+
+		var events []Event
+		for i, z := range values {
+			newRank, err := rankCmds[i].Result()
+			if err != nil {
+				continue
+			}
+
+			oldRank := offset + i + 1
+			shift := int(newRank) + 1 - oldRank
+			if shift < 0 {
+				shift = -shift
+			}
+			if shift <= threshold {
+				continue
+			}
+
+			events = append(events, Event{
+				Type:     EventRankChanged,
+				UserID:   z.Member.(string),
+				OldRank:  oldRank,
+				NewRank:  int(newRank) + 1,
+				OldScore: l.precision.whole(z.Score),
+				NewScore: l.precision.whole(scoreCmds[i].Val()),
+			})
+		}
+		l.emitAll(ctx, events)
+		notified += len(events)
+
+		if len(values) < rebuildBatchSize {
+			break
+		}
+	}
+
+	return notified, nil
+}
@@ -0,0 +1,110 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNotifyAffectedReportsLargeShiftsSinceFirstBulkChange(t *testing.T) {
+	l, _ := newTestBoard(t, WithEvents(0), WithRankBaseline(0))
+	ctx := context.Background()
+	seed(t, l, "a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+
+	// "a" jumps from 5th to 1st, the others shift by one place
+	err := l.Rebuild(ctx, func(yield func(userID string, score int) bool) {
+		for _, m := range []MemberScore{{UserID: "a", Score: 10}, {UserID: "b", Score: 2}, {UserID: "c", Score: 3}, {UserID: "d", Score: 4}, {UserID: "e", Score: 5}} {
+			yield(m.UserID, m.Score)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Removed members aren't notified
+	if _, err := l.TrimBelowScore(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	last := l.client().XRevRangeN(ctx, l.eventsStream(), "+", "-", 1).Val()[0].ID
+
+	notified, err := l.NotifyAffected(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notified != 1 {
+		t.Fatalf("got %d notified, want 1", notified)
+	}
+
+	events, err := l.Events(ctx, "("+last, "+", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].UserID != "a" || events[0].OldRank != 5 || events[0].NewRank != 1 {
+		t.Fatalf("got %+v, want a moving from 5 to 1", events)
+	}
+
+	// Baseline was consumed
+	if notified, err := l.NotifyAffected(ctx, 1); err != nil || notified != 0 {
+		t.Fatalf("got %d notified and %v, want 0 and nil", notified, err)
+	}
+}
+
+func TestRankBaseline(t *testing.T) {
+	ctx := context.Background()
+	trim := func(t *testing.T, l *Leaderboard) {
+		t.Helper()
+		if _, err := l.TrimBelowScore(ctx, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("not kept by default", func(t *testing.T) {
+		l, mr := newTestBoard(t, WithEvents(0))
+		seed(t, l, "a", 1)
+
+		trim(t, l)
+		if mr.Exists(l.baselineKey()) || mr.HGet(l.metaKey(), metaBaselineAt) != "" {
+			t.Fatal("baseline was kept without WithRankBaseline")
+		}
+	})
+
+	t.Run("copied in batches and expiring", func(t *testing.T) {
+		l, mr := newTestBoard(t, WithEvents(0), WithRankBaseline(time.Hour))
+		members := rebuildBatchSize*2 + 1
+		for i := 0; i < members; i++ {
+			mr.ZAdd(l.leaderboardName, float64(i), "m"+strconv.Itoa(i))
+		}
+
+		trim(t, l)
+		card, err := l.client().ZCard(ctx, l.baselineKey()).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if card != int64(members) {
+			t.Fatalf("got %d members in baseline, want %d", card, members)
+		}
+		if ttl := mr.TTL(l.baselineKey()); ttl != time.Hour {
+			t.Fatalf("got baseline TTL %v, want %v", ttl, time.Hour)
+		}
+	})
+
+	t.Run("started again once expired", func(t *testing.T) {
+		l, mr := newTestBoard(t, WithEvents(0), WithRankBaseline(time.Minute))
+		seed(t, l, "a", 1)
+
+		trim(t, l)
+		seed(t, l, "b", 2)
+		trim(t, l)
+		if card := l.client().ZCard(ctx, l.baselineKey()).Val(); card != 1 {
+			t.Fatalf("got %d members in baseline, want 1 as it's kept already", card)
+		}
+
+		// Flag is older than TTL once baseline expired
+		mr.HSet(l.metaKey(), metaBaselineAt, strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10))
+		mr.Del(l.baselineKey())
+		trim(t, l)
+		if card := l.client().ZCard(ctx, l.baselineKey()).Val(); card != 2 {
+			t.Fatalf("got %d members in baseline, want 2", card)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EventAnomaly is emitted when member's update looks statistically suspicious, see WithAnomalyDetection
+	EventAnomaly = "anomaly"
+
+	// Reasons of EventAnomaly
+	AnomalyFrequency = "frequency"
+	AnomalyDelta     = "delta"
+
+	DefaultAnomalyMinSamples = 10
+	DefaultAnomalyStatsTTL   = 7 * 24 * time.Hour
+)
+
+// AnomalyOptions configures WithAnomalyDetection.
+type AnomalyOptions struct {
+	// MaxUpdates is number of updates of one member allowed within Window,
+	// member exceeding it is flagged once per window. 0 disables the check.
+	MaxUpdates int
+	Window     time.Duration
+	// DeltaThreshold flags score change deviating from member's mean change by
+	// more than DeltaThreshold standard deviations. 0 disables the check.
+	DeltaThreshold float64
+	// MinSamples is number of changes member must have before the delta check
+	// applies, DefaultAnomalyMinSamples by default
+	MinSamples int
+	// StatsTTL is how long stats of inactive member are kept, DefaultAnomalyStatsTTL by default
+	StatsTTL time.Duration
+}
+
+// Updates stats of member in hash KEYS[1] and its update counter KEYS[2] with
+// new score ARGV[1]. Flagged deltas aren't folded into mean and variance, so a
+// cheater keeps being flagged instead of shifting their own baseline. Returns
+// {reasons, previousScore}.
+var anomalyScript = redis.NewScript(`
+local score = tonumber(ARGV[1])
+local maxUpdates, window = tonumber(ARGV[2]), ARGV[3]
+local threshold, minSamples, ttl = tonumber(ARGV[4]), tonumber(ARGV[5]), ARGV[6]
+local reasons = {}
+
+if maxUpdates > 0 then
+	local updates = redis.call("INCR", KEYS[2])
+	if updates == 1 then
+		redis.call("PEXPIRE", KEYS[2], window)
+	end
+	if updates == maxUpdates + 1 then
+		reasons[#reasons + 1] = "frequency"
+	end
+end
+
+local last = redis.call("HGET", KEYS[1], "last")
+if last then
+	local delta = score - tonumber(last)
+	local stats = redis.call("HMGET", KEYS[1], "n", "mean", "m2")
+	local n, mean, m2 = tonumber(stats[1] or "0"), tonumber(stats[2] or "0"), tonumber(stats[3] or "0")
+
+	local outlier = false
+	if threshold > 0 and n >= minSamples then
+		local std = math.sqrt(m2 / (n - 1))
+		outlier = (std == 0 and delta ~= mean) or (std > 0 and math.abs(delta - mean) / std > threshold)
+	end
+
+	if outlier then
+		reasons[#reasons + 1] = "delta"
+	else
+		n = n + 1
+		local d = delta - mean
+		mean = mean + d / n
+		m2 = m2 + d * (delta - mean)
+		redis.call("HSET", KEYS[1], "n", n, "mean", tostring(mean), "m2", tostring(m2))
+	end
+end
+
+redis.call("HSET", KEYS[1], "last", ARGV[1])
+redis.call("PEXPIRE", KEYS[1], ttl)
+
+return {table.concat(reasons, ","), last or ""}
+`)
+
+// WithAnomalyDetection tracks update frequency and score change distribution
+// of every member in Redis and emits EventAnomaly (with reason) for outliers,
+// as early warning before a cheater tops the board. Events must be enabled
+// with WithEvents. It costs one extra script call per write.
+func WithAnomalyDetection(opts AnomalyOptions) Option {
+	return func(l *Leaderboard) {
+		if opts.MinSamples < 2 {
+			opts.MinSamples = DefaultAnomalyMinSamples
+		}
+
+		if opts.StatsTTL <= 0 {
+			opts.StatsTTL = DefaultAnomalyStatsTTL
+		}
+
+		if opts.Window <= 0 {
+			opts.MaxUpdates = 0
+		}
+
+		l.anomaly = &opts
+	}
+}
+
+func (l *Leaderboard) anomalyStatsKey(userID string) string {
+	return l.leaderboardName + ":anomaly:" + userID
+}
+
+func (l *Leaderboard) anomalyUpdatesKey(userID string) string {
+	return l.leaderboardName + ":anomaly:updates:" + userID
+}
+
+// detectAnomaly updates member's stats with new score and emits EventAnomaly
+// when update is an outlier. Errors are ignored, detection must never fail the write.
+func (l *Leaderboard) detectAnomaly(ctx context.Context, userID string, score int) {
+	l.detectAnomalies(ctx, []MemberScore{{UserID: userID, Score: score}})
+}
+
+// detectAnomalies is detectAnomaly for many members in one round trip.
+func (l *Leaderboard) detectAnomalies(ctx context.Context, members []MemberScore) {
+	o := l.anomaly
+	if o == nil || !l.eventsEnabled() {
+		return
+	}
+
+	calls := make([]scriptCall, len(members))
+	for i, m := range members {
+		calls[i] = scriptCall{
+			keys: []string{l.anomalyStatsKey(m.UserID), l.anomalyUpdatesKey(m.UserID)},
+			args: []interface{}{m.Score, o.MaxUpdates, o.Window.Milliseconds(), o.DeltaThreshold, o.MinSamples, o.StatsTTL.Milliseconds()},
+		}
+	}
+
+	// Failed calls are skipped, the others still count
+	cmds, _ := runPipelined(ctx, l.client(), anomalyScript, calls)
+
+	var events []Event
+	for i, cmd := range cmds {
+		res, ok := cmd.Val().([]interface{})
+		if !ok || len(res) != 2 {
+			continue
+		}
+
+		reasons, _ := res[0].(string)
+		if reasons == "" {
+			continue
+		}
+
+		previous, _ := res[1].(string)
+		oldScore, _ := strconv.Atoi(previous)
+
+		for _, reason := range strings.Split(reasons, ",") {
+			events = append(events, Event{Type: EventAnomaly, UserID: members[i].UserID, OldScore: oldScore, NewScore: members[i].Score, Reason: reason})
+		}
+	}
+	l.emitAll(ctx, events)
+}
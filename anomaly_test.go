@@ -0,0 +1,57 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// anomalies returns reasons of anomaly events of the board, oldest first.
+func anomalies(t *testing.T, l *Leaderboard) []string {
+	t.Helper()
+
+	events, err := l.Events(context.Background(), "-", "+", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons := []string{}
+	for _, event := range events {
+		if event.Type == EventAnomaly {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+
+	return reasons
+}
+
+func TestAnomalyDetectionFlagsFrequentUpdatesOncePerWindow(t *testing.T) {
+	l, _ := newTestBoard(t, WithEvents(0), WithAnomalyDetection(AnomalyOptions{MaxUpdates: 3, Window: time.Minute}))
+	ctx := context.Background()
+
+	for i := 0; i < 6; i++ {
+		if _, err := l.IncrementMemberScore(ctx, "a", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if reasons := anomalies(t, l); !equalStrings(reasons, []string{AnomalyFrequency}) {
+		t.Fatalf("got anomalies %v, want one frequency anomaly", reasons)
+	}
+}
+
+func TestAnomalyDetectionFlagsOutlyingDelta(t *testing.T) {
+	l, _ := newTestBoard(t, WithEvents(0), WithAnomalyDetection(AnomalyOptions{DeltaThreshold: 3, MinSamples: 5}))
+	ctx := context.Background()
+
+	// The first write only sets the baseline, outlier doesn't shift it
+	for _, points := range []int{10, 10, 11, 9, 10, 11, 9, 500, 10} {
+		if _, err := l.IncrementMemberScore(ctx, "a", points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if reasons := anomalies(t, l); !equalStrings(reasons, []string{AnomalyDelta}) {
+		t.Fatalf("got anomalies %v, want one delta anomaly", reasons)
+	}
+}
@@ -0,0 +1,245 @@
+package go_redis_leaderboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+const (
+	DefaultArchiveBatchSize = 1000
+	// DefaultArchivePartSize is above 5 MiB minimum part size of S3 multipart uploads
+	DefaultArchivePartSize = 8 << 20
+)
+
+// PartUploader uploads archive in parts, e.g. S3 (or compatible) multipart upload.
+type PartUploader interface {
+	// UploadPart uploads part with given number, starting at 1. Re-uploading
+	// the same part number must replace it.
+	UploadPart(ctx context.Context, partNumber int, data []byte) error
+	// Complete finishes upload consisting of parts 1 to parts.
+	Complete(ctx context.Context, parts int) error
+}
+
+// ArchiveCheckpoint tells where interrupted archiving continues from.
+type ArchiveCheckpoint struct {
+	// NextRank is rank of the first member not yet archived
+	NextRank int `json:"next_rank"`
+	// Parts is number of parts uploaded so far (ArchiveToUploader only)
+	Parts int `json:"parts"`
+}
+
+// ArchiveOptions configures Archive and ArchiveToUploader.
+type ArchiveOptions struct {
+	// BatchSize is number of members read per round trip, DefaultArchiveBatchSize by default
+	BatchSize int
+	// PartSize is minimum size of uploaded parts, DefaultArchivePartSize by default
+	PartSize int
+	// Resume continues archiving from checkpoint previously reported to OnCheckpoint
+	Resume *ArchiveCheckpoint
+	// OnCheckpoint is called whenever archiving can be resumed from checkpoint,
+	// after every batch (Archive) or uploaded part (ArchiveToUploader)
+	OnCheckpoint func(ArchiveCheckpoint)
+	// KeepKeys leaves board in Redis after it's archived
+	KeepKeys bool
+}
+
+func (o *ArchiveOptions) normalize() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultArchiveBatchSize
+	}
+
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultArchivePartSize
+	}
+}
+
+// Archive writes finished board to w as JSON lines, one User (with info) per
+// line in rank order, and then deletes board's keys unless opts.KeepKeys is set.
+// Board must not change while it's archived.
+//
+// To resume interrupted archiving, reopen w positioned right after data written
+// before the last checkpoint and pass that checkpoint in opts.Resume.
+func (l *Leaderboard) Archive(ctx context.Context, w io.Writer, opts ArchiveOptions) (archived int, err error) {
+	done, err := l.trackOp("Archive")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	opts.normalize()
+
+	start := 1
+	if opts.Resume != nil {
+		start = opts.Resume.NextRank
+	}
+
+	var buf bytes.Buffer
+	err = l.archiveBatches(ctx, start, opts.BatchSize, func(users []User) error {
+		buf.Reset()
+		if err := writeJSONLines(&buf, users); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+
+		archived += len(users)
+		if opts.OnCheckpoint != nil {
+			opts.OnCheckpoint(ArchiveCheckpoint{NextRank: users[len(users)-1].Rank + 1})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return archived, err
+	}
+
+	if opts.KeepKeys {
+		return archived, nil
+	}
+
+	return archived, l.deleteBoard(ctx)
+}
+
+// ArchiveToUploader is Archive writing to multipart upload, e.g. to S3. Upload
+// of multi-GB boards can be resumed from the last uploaded part.
+func (l *Leaderboard) ArchiveToUploader(ctx context.Context, uploader PartUploader, opts ArchiveOptions) (archived int, err error) {
+	done, err := l.trackOp("ArchiveToUploader")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	opts.normalize()
+
+	checkpoint := ArchiveCheckpoint{NextRank: 1}
+	if opts.Resume != nil {
+		checkpoint = *opts.Resume
+	}
+
+	var part bytes.Buffer
+	upload := func(nextRank int) error {
+		if err := uploader.UploadPart(ctx, checkpoint.Parts+1, part.Bytes()); err != nil {
+			return err
+		}
+		part.Reset()
+
+		checkpoint = ArchiveCheckpoint{NextRank: nextRank, Parts: checkpoint.Parts + 1}
+		if opts.OnCheckpoint != nil {
+			opts.OnCheckpoint(checkpoint)
+		}
+
+		return nil
+	}
+
+	nextRank := checkpoint.NextRank
+	err = l.archiveBatches(ctx, checkpoint.NextRank, opts.BatchSize, func(users []User) error {
+		if err := writeJSONLines(&part, users); err != nil {
+			return err
+		}
+
+		archived += len(users)
+		nextRank = users[len(users)-1].Rank + 1
+
+		if part.Len() < opts.PartSize {
+			return nil
+		}
+
+		return upload(nextRank)
+	})
+	if err != nil {
+		return archived, err
+	}
+
+	if part.Len() > 0 || checkpoint.Parts == 0 {
+		if err := upload(nextRank); err != nil {
+			return archived, err
+		}
+	}
+
+	if err := uploader.Complete(ctx, checkpoint.Parts); err != nil {
+		return archived, err
+	}
+
+	if opts.KeepKeys {
+		return archived, nil
+	}
+
+	return archived, l.deleteBoard(ctx)
+}
+
+// archiveBatches calls fn with consecutive batches of members (with info) starting at rank start.
+func (l *Leaderboard) archiveBatches(ctx context.Context, start, batchSize int, fn func(users []User) error) error {
+	if start < 1 {
+		start = 1
+	}
+
+	for offset := start - 1; ; offset += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		members, err := l.client().ZRevRangeWithScores(ctx, l.leaderboardName, int64(offset), int64(offset+batchSize-1)).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(members) == 0 {
+			return nil
+		}
+
+		users := appendUsers(make([]User, 0, len(members)), members, offset, l.precision)
+
+		if err := l.profiles.load(ctx, l.client(), users); err != nil {
+			return err
+		}
+
+		if err := fn(users); err != nil {
+			return err
+		}
+	}
+}
+
+func writeJSONLines(buf *bytes.Buffer, users []User) error {
+	encoder := json.NewEncoder(buf)
+	for _, u := range users {
+		if err := encoder.Encode(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBoard removes sorted set of the board and all its companion keys. User
+// info is removed too unless profile store is shared with other boards.
+func (l *Leaderboard) deleteBoard(ctx context.Context) error {
+	companions, err := l.companionKeys(ctx)
+	if err != nil {
+		return err
+	}
+	keys := append([]string{l.leaderboardName}, companions...)
+
+	if !l.profiles.shared {
+		locales, err := l.profiles.Locales(ctx)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, l.profiles.hashName, l.profiles.localesKey())
+		for _, locale := range locales {
+			keys = append(keys, l.profiles.localeHash(locale))
+		}
+	}
+
+	pipe := l.client().Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
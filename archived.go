@@ -0,0 +1,125 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrPeriodNotArchived = errors.New("leaderboard: period is not archived")
+)
+
+// periodsKey is sorted set of keys of periods boards were opened for, scored
+// by start of the period (Unix seconds).
+func (p *PeriodicLeaderboard) periodsKey() string {
+	return p.name + ":periods"
+}
+
+// index records period in periods index once per process. Failed writes are
+// retried when the board is opened again.
+func (p *PeriodicLeaderboard) index(ctx context.Context, l *Leaderboard, period Period) error {
+	key := period.String()
+
+	p.indexMu.Lock()
+	defer p.indexMu.Unlock()
+
+	if p.indexed[key] {
+		return nil
+	}
+
+	z := &redis.Z{Score: float64(period.Start.Unix()), Member: key}
+	if err := l.client().ZAddNX(ctx, p.periodsKey(), z).Err(); err != nil {
+		return err
+	}
+
+	if p.indexed == nil {
+		p.indexed = map[string]bool{}
+	}
+	p.indexed[key] = true
+
+	return nil
+}
+
+// oldestKept returns start of the oldest period whose keys didn't expire yet,
+// zero time when periods are kept forever.
+func (p *PeriodicLeaderboard) oldestKept(current Period) time.Time {
+	if p.opts.Retention <= 0 {
+		return time.Time{}
+	}
+
+	return current.Add(-p.opts.Retention).Start
+}
+
+// ArchivedPeriods returns page of finished periods, newest first, whose boards
+// are kept (see PeriodicOptions.Retention), so clients can browse past
+// standings with GetArchivedLeaders. Periods are listed once their board was
+// opened with Board, At or Current; custom periods aren't listed.
+func (p *PeriodicLeaderboard) ArchivedPeriods(ctx context.Context, page int) (periods []Period, err error) {
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := p.pageSize
+	if !allowedPageSizes[pageSize] {
+		pageSize = DefaultPageSize
+	}
+
+	cli := p.manager.client()
+	current := p.PeriodAt(time.Now())
+
+	min := "-inf"
+	if oldest := p.oldestKept(current); !oldest.IsZero() {
+		min = strconv.FormatInt(oldest.Unix(), 10)
+
+		// Periods whose keys expired are dropped from the index
+		if err := cli.ZRemRangeByScore(ctx, p.periodsKey(), "-inf", "("+min).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := cli.ZRevRangeByScore(ctx, p.periodsKey(), &redis.ZRangeBy{
+		Min:    min,
+		Max:    "(" + strconv.FormatInt(current.Start.Unix(), 10),
+		Offset: int64((page - 1) * pageSize),
+		Count:  int64(pageSize),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	periods = make([]Period, 0, len(keys))
+	for _, key := range keys {
+		period, err := p.ParsePeriod(key)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, nil
+}
+
+// GetArchivedLeaders is GetLeaders of finished period given by its key (e.g.
+// "2024-W07"), so archived standings are read like the live board. Periods
+// which didn't end yet or whose keys expired fail with ErrPeriodNotArchived.
+func (p *PeriodicLeaderboard) GetArchivedLeaders(ctx context.Context, period string, page int, opts ...ReadOption) ([]User, error) {
+	parsed, err := p.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	current := p.PeriodAt(time.Now())
+	if parsed.Kind != p.opts.Period || !parsed.Start.Before(current.Start) || parsed.Start.Before(p.oldestKept(current)) {
+		return nil, ErrPeriodNotArchived
+	}
+
+	l, err := p.board(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.GetLeaders(ctx, page, opts...)
+}
@@ -0,0 +1,203 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sort"
+)
+
+// rankRange is inclusive range of 0-based ranks.
+type rankRange struct {
+	start, end int64
+}
+
+// Returns {start, members} of window of ARGV[1] members centered on member
+// ARGV[2] on board KEYS[1], shifted to keep its size at the edges of the board,
+// start being 0-based rank of the first member and members being flat
+// member/score list. Returns nil when member isn't ranked.
+var aroundMemberScript = redis.NewScript(`
+local rank = redis.call("ZREVRANK", KEYS[1], ARGV[2])
+if not rank then
+	return false
+end
+
+local size = tonumber(ARGV[1])
+local start = rank - math.floor((size - 1) / 2)
+local total = redis.call("ZCARD", KEYS[1])
+if start + size > total then
+	start = total - size
+end
+if start < 0 then
+	start = 0
+end
+
+return {start, redis.call("ZREVRANGE", KEYS[1], start, start + size - 1, "WITHSCORES")}
+`)
+
+// GetMembersAroundMember returns window of windowSize members centered on the
+// user (e.g. the user and 5 members above and below with windowSize 11), for
+// "you and players around you" screens. Near the top or the bottom of the
+// board the window is shifted to keep its size. Rank is resolved and the
+// window read in one round trip, ghosts in the window are marked like on
+// pages. ErrMemberNotFound is returned when user isn't ranked.
+func (l *Leaderboard) GetMembersAroundMember(ctx context.Context, userID string, windowSize int, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMembersAroundMember", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if windowSize < 1 {
+		windowSize = 1
+	}
+
+	cli := l.readClient(newReadOptions(opts))
+	reply, err := aroundMemberScript.Run(ctx, cli, []string{l.leaderboardName}, windowSize, userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, errUnexpectedScriptReply
+	}
+
+	start, ok := values[0].(int64)
+	members, ok2 := values[1].([]interface{})
+	if !ok || !ok2 || len(members)%2 != 0 {
+		return nil, errUnexpectedScriptReply
+	}
+
+	users = make([]User, 0, len(members)/2)
+	for i := 0; i < len(members); i += 2 {
+		member, _ := members[i].(string)
+		scoreText, _ := members[i+1].(string)
+		score, err := parseScore(scoreText)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, l.scoredUser(member, score, int(start)+i/2+1))
+	}
+
+	if err := l.markGhosts(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	return l.hideBanned(ctx, users)
+}
+
+// GetMembersAroundMany returns neighbourhood of every ranked user: up to count
+// members ranked above and below the user, together with the user. Ranks are
+// resolved in one pipelined round trip and overlapping windows (e.g. of
+// teammates ranked close to each other) are merged and read in another.
+// Users who aren't on the board are left out of the result.
+func (l *Leaderboard) GetMembersAroundMany(ctx context.Context, userIDs []string, count int, opts ...ReadOption) (around map[string][]User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetMembersAroundMany")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if count < 0 {
+		count = 0
+	}
+
+	cli := l.readClient(newReadOptions(opts))
+
+	pipe := cli.Pipeline()
+	rankCmds := make([]*redis.IntCmd, len(userIDs))
+	for i, userID := range userIDs {
+		rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	windows := map[string]rankRange{}
+	for i, cmd := range rankCmds {
+		rank, err := cmd.Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return nil, err
+		default:
+			start := rank - int64(count)
+			if start < 0 {
+				start = 0
+			}
+			windows[userIDs[i]] = rankRange{start: start, end: rank + int64(count)}
+		}
+	}
+
+	ranges := mergeRankRanges(windows)
+
+	pipe = cli.Pipeline()
+	rangeCmds := make([]*redis.ZSliceCmd, len(ranges))
+	for i, r := range ranges {
+		rangeCmds[i] = pipe.ZRevRangeWithScores(ctx, l.leaderboardName, r.start, r.end)
+	}
+
+	if len(ranges) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ghostRanks, err := l.ghostRanks(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	around = make(map[string][]User, len(windows))
+	for userID, w := range windows {
+		// Merged ranges are sorted and disjoint, the one containing window starts at or before it
+		i := sort.Search(len(ranges), func(i int) bool { return ranges[i].start > w.start }) - 1
+		members := rangeCmds[i].Val()
+
+		users := make([]User, 0, w.end-w.start+1)
+		for rank := w.start; rank <= w.end; rank++ {
+			offset := rank - ranges[i].start
+			if offset >= int64(len(members)) {
+				break
+			}
+
+			z := members[offset]
+			users = append(users, l.scoredUser(z.Member.(string), z.Score, int(rank)+1))
+		}
+		markGhostRanks(ghostRanks, users)
+		around[userID] = users
+	}
+
+	return l.hideBannedAround(ctx, around)
+}
+
+// mergeRankRanges returns sorted union of windows with overlapping and adjacent ranges merged.
+func mergeRankRanges(windows map[string]rankRange) []rankRange {
+	ranges := make([]rankRange, 0, len(windows))
+	for _, w := range windows {
+		ranges = append(ranges, w)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.start <= merged[n-1].end+1 {
+			if r.end > merged[n-1].end {
+				merged[n-1].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
@@ -0,0 +1,48 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetMembersAroundMemberMarksGhosts(t *testing.T) {
+	l, _ := newTestBoard(t, WithGhosts())
+	ctx := context.Background()
+	seed(t, l, "a", 10, "b", 8, "c", 6, "d", 4)
+
+	if err := l.AddGhost(ctx, "ghost", 9, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := l.GetMembersAroundMember(ctx, "c", 3, WithConsistency(Strong))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !equalStrings(userIDs(users), []string{"b", "c", "d"}) {
+		t.Fatalf("got %v, want [b c d]", userIDs(users))
+	}
+	// Ghost ranked above doesn't count towards ranks
+	if users[1].Rank != 3 || users[1].Ghost {
+		t.Fatalf("got %+v, want c ranked 3rd", users[1])
+	}
+}
+
+func TestGetMembersAroundMemberShiftsAtEdges(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 10, "b", 8, "c", 6, "d", 4)
+
+	users, err := l.GetMembersAroundMember(ctx, "a", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"a", "b", "c"}) || users[0].Rank != 1 {
+		t.Fatalf("got %+v, want a, b and c from rank 1", users)
+	}
+
+	if _, err := l.GetMembersAroundMember(ctx, "missing", 3); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("got %v, want ErrMemberNotFound", err)
+	}
+}
@@ -0,0 +1,124 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrMemberBanned = errors.New("leaderboard: member is banned")
+)
+
+// BanChecker tells which users are banned by an external ban service, see WithBanChecker.
+type BanChecker interface {
+	// Banned returns banned users among userIDs, users left out aren't banned
+	Banned(ctx context.Context, userIDs []string) (map[string]bool, error)
+}
+
+// WithBanChecker consults checker on every score write and listing, so bans
+// take effect immediately without the library keeping its own moderation
+// store: increments, submissions, inserts and recorded results of banned
+// members fail with ErrMemberBanned, and GetLeaders, GetLeadersInto,
+// GetLeadersPage, GetLeadersExcluding, queries and GetMembersAroundMany leave
+// them out. Banned members keep their scores, so other members keep their
+// ranks and pages may be shorter than page size. Checker errors fail the call
+// unless onError is set, then they're passed to it and nobody is treated as
+// banned.
+func WithBanChecker(checker BanChecker, onError func(error)) Option {
+	return func(l *Leaderboard) {
+		l.banChecker = checker
+		l.onBanCheckError = onError
+	}
+}
+
+// banned returns banned users among userIDs.
+func (l *Leaderboard) banned(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	if l.banChecker == nil || len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	banned, err := l.banChecker.Banned(ctx, userIDs)
+	if err != nil {
+		if l.onBanCheckError != nil {
+			l.onBanCheckError(err)
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return banned, nil
+}
+
+// checkBanned fails write of banned member.
+func (l *Leaderboard) checkBanned(ctx context.Context, userIDs ...string) error {
+	banned, err := l.banned(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if banned[userID] {
+			return ErrMemberBanned
+		}
+	}
+
+	return nil
+}
+
+// hideBanned leaves banned members out of a listing, in place.
+func (l *Leaderboard) hideBanned(ctx context.Context, users []User) ([]User, error) {
+	if l.banChecker == nil || len(users) == 0 {
+		return users, nil
+	}
+
+	userIDs := make([]string, len(users))
+	for i, u := range users {
+		userIDs[i] = u.UserID
+	}
+
+	banned, err := l.banned(ctx, userIDs)
+	if err != nil || len(banned) == 0 {
+		return users, err
+	}
+
+	visible := users[:0]
+	for _, u := range users {
+		if !banned[u.UserID] {
+			visible = append(visible, u)
+		}
+	}
+
+	return visible, nil
+}
+
+// hideBannedAround leaves banned members out of windows, checking all of them at once.
+func (l *Leaderboard) hideBannedAround(ctx context.Context, around map[string][]User) (map[string][]User, error) {
+	if l.banChecker == nil {
+		return around, nil
+	}
+
+	var userIDs []string
+	for _, users := range around {
+		for _, u := range users {
+			userIDs = append(userIDs, u.UserID)
+		}
+	}
+
+	banned, err := l.banned(ctx, userIDs)
+	if err != nil || len(banned) == 0 {
+		return around, err
+	}
+
+	for userID, users := range around {
+		visible := users[:0]
+		for _, u := range users {
+			if !banned[u.UserID] {
+				visible = append(visible, u)
+			}
+		}
+		around[userID] = visible
+	}
+
+	return around, nil
+}
@@ -0,0 +1,217 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// FirstOrInsertMembers is batch version of FirstOrInsertMember: members which
+// don't exist yet are inserted with given score (ZADD NX), existing ones keep
+// their score. Insert and lookups of resulting ranks and scores happen in one
+// MULTI/EXEC round trip. Returned users are in the same order as members.
+func (l *Leaderboard) FirstOrInsertMembers(ctx context.Context, members []MemberScore) (users []User, err error) {
+	defer l.wrapError("FirstOrInsertMembers", "", &err)
+
+	if len(members) == 0 {
+		return []User{}, nil
+	}
+
+	ctx, done, err := l.startOp(ctx, "FirstOrInsertMembers")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	userIDs := make([]string, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	if err := l.checkBanned(ctx, userIDs...); err != nil {
+		return nil, err
+	}
+
+	z := make([]*redis.Z, len(members))
+	for i, m := range members {
+		z[i] = &redis.Z{Score: float64(m.Score), Member: m.UserID}
+	}
+
+	pipe := l.client().TxPipeline()
+	existed := make([]*redis.FloatCmd, len(members))
+	for i, m := range members {
+		existed[i] = pipe.ZScore(ctx, l.leaderboardName, m.UserID)
+	}
+
+	pipe.ZAddNX(ctx, l.leaderboardName, z...)
+	l.logOps(ctx, pipe, ScoreOpInsert, members)
+	l.bumpVersion(ctx, pipe)
+
+	ranks := make([]*redis.IntCmd, len(members))
+	scores := make([]*redis.FloatCmd, len(members))
+	for i, m := range members {
+		ranks[i] = pipe.ZRevRank(ctx, l.leaderboardName, m.UserID)
+		scores[i] = pipe.ZScore(ctx, l.leaderboardName, m.UserID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	users = make([]User, len(members))
+	inserted := make([]int, 0, len(members))
+	written := make([]redis.Z, 0, len(members))
+	for i, m := range members {
+		rank, err := ranks[i].Result()
+		if err != nil {
+			return nil, err
+		}
+
+		users[i] = l.scoredUser(m.UserID, scores[i].Val(), int(rank)+1)
+		if errors.Is(existed[i].Err(), redis.Nil) {
+			inserted = append(inserted, i)
+			written = append(written, redis.Z{Score: scores[i].Val(), Member: m.UserID})
+		}
+	}
+
+	l.scoresWritten(ctx, written)
+
+	if err := l.markGhosts(ctx, l.client(), users); err != nil {
+		return nil, err
+	}
+	for _, i := range inserted {
+		l.emitScoreChange(ctx, User{UserID: users[i].UserID, Rank: UnrankedMember}, users[i])
+	}
+
+	return users, nil
+}
+
+// MemberInfo is userID with its info used by UpsertMemberInfoBatch.
+type MemberInfo struct {
+	UserID string
+	Info   AdditionalUserInfo
+}
+
+// Sets scores of members given by ARGV pairs of userID and score unless any of
+// them is locked (KEYS[2]), submissions are closed or member cap (KEYS[4])
+// doesn't admit new ones, then returns number of members. Scores are logged to
+// KEYS[3].
+var upsertMembersScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + versionLua + `
+local members = {}
+for i = 1, #ARGV - 1, 2 do
+	if redis.call("HEXISTS", KEYS[2], ARGV[i]) == 1 then
+		return redis.error_reply("LOCKED member's score is locked")
+	end
+	members[#members + 1] = ARGV[i]
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admitAll(KEYS[1], KEYS[4], KEYS[3], members) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+for i = 1, #ARGV - 1, 2 do
+	redis.call("ZADD", KEYS[1], ARGV[i + 1], ARGV[i])
+	logOp(KEYS[3], "set", ARGV[i], ARGV[i + 1])
+end
+bumpVersion(KEYS[4])
+
+return #members
+`)
+
+// UpsertMembers sets scores of members (inserting missing ones) in chunks of
+// batchSize (DefaultBulkLoadPipelineSize when <= 0), each chunk written by one
+// script in one round trip, for backfill jobs seeding large boards. Chunks are
+// checked like SubmitScore: banned members, locked scores, closed submissions
+// and new members not fitting member cap fail the whole chunk. Unlike BulkLoad
+// it works with event sourcing and during migration, but no rank events are
+// emitted. Chunks written before an error stay written.
+func (l *Leaderboard) UpsertMembers(ctx context.Context, members []MemberScore, batchSize int) (err error) {
+	done, err := l.trackOp("UpsertMembers")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if batchSize <= 0 {
+		batchSize = DefaultBulkLoadPipelineSize
+	}
+
+	for start := 0; start < len(members); start += batchSize {
+		end := start + batchSize
+		if end > len(members) {
+			end = len(members)
+		}
+		chunk := members[start:end]
+
+		userIDs := make([]string, len(chunk))
+		args := make([]interface{}, 0, 2*len(chunk)+1)
+		for i, m := range chunk {
+			userIDs[i] = m.UserID
+			args = append(args, m.UserID, m.Score)
+		}
+
+		if err := l.checkBanned(ctx, userIDs...); err != nil {
+			return err
+		}
+
+		keys := []string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey()}
+		if err := upsertMembersScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Err(); err != nil {
+			return scriptError(err)
+		}
+		l.scoresWritten(ctx, storedScores(chunk))
+	}
+
+	return nil
+}
+
+// UpsertMemberInfoBatch is UpsertMemberInfo for many members, writing chunks
+// of batchSize (DefaultBulkLoadPipelineSize when <= 0) with one HSET each.
+// All infos are validated before anything is written.
+func (l *Leaderboard) UpsertMemberInfoBatch(ctx context.Context, infos []MemberInfo, batchSize int) (err error) {
+	done, err := l.trackOp("UpsertMemberInfoBatch")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if batchSize <= 0 {
+		batchSize = DefaultBulkLoadPipelineSize
+	}
+
+	values := make([]interface{}, 0, 2*len(infos))
+	for _, m := range infos {
+		value, err := l.profiles.encode(m.Info)
+		if err != nil {
+			return err
+		}
+		values = append(values, m.UserID, value)
+	}
+
+	for start := 0; start < len(values); start += 2 * batchSize {
+		end := start + 2*batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		chunk := values[start:end]
+
+		pipe := l.client().TxPipeline()
+		pipe.HSet(ctx, l.profiles.hashName, chunk...)
+		l.bumpVersion(ctx, pipe)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+
+		l.mirrorWrite(func(cli redis.UniversalClient) error {
+			return cli.HSet(ctx, l.profiles.hashName, chunk...).Err()
+		})
+		l.mirrorVersion(ctx)
+		for _, m := range infos[start/2 : end/2] {
+			l.embeddedInfoUpserted(ctx, m.UserID, m.Info)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type bannedSet map[string]bool
+
+func (b bannedSet) Banned(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	return b, nil
+}
+
+func TestUpsertMembersHonorsGuards(t *testing.T) {
+	ctx := context.Background()
+	members := []MemberScore{{UserID: "a", Score: 5}, {UserID: "b", Score: 3}}
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		setup func(l *Leaderboard) error
+		want  error
+	}{
+		{"locked", nil, func(l *Leaderboard) error {
+			return l.LockMemberScore(ctx, "b", "investigated")
+		}, ErrMemberScoreLocked},
+		{"banned", []Option{WithBanChecker(bannedSet{"a": true}, nil)}, nil, ErrMemberBanned},
+		{"full", nil, func(l *Leaderboard) error {
+			seed(t, l, "x", 1)
+			return l.SetMemberCap(ctx, 2, CapReject)
+		}, ErrBoardFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t, tt.opts...)
+			if tt.setup != nil {
+				if err := tt.setup(l); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := l.UpsertMembers(ctx, members, 0); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if n := l.client().ZCard(ctx, l.leaderboardName).Val(); n > 1 {
+				t.Fatalf("got %d members, want nothing written", n)
+			}
+		})
+	}
+}
+
+func TestUpsertMembersEvictsOnlyOtherMembers(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "x", 1, "y", 2, "a", 0)
+
+	if err := l.SetMemberCap(ctx, 3, CapEvictLowest); err != nil {
+		t.Fatal(err)
+	}
+
+	// "a" is the lowest member but is upserted itself, so "x" and "y" make room
+	members := []MemberScore{{UserID: "a", Score: 0}, {UserID: "b", Score: 3}, {UserID: "c", Score: 4}}
+	if err := l.UpsertMembers(ctx, members, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"c", "b", "a"}) {
+		t.Fatalf("got %v, want [c b a]", userIDs(users))
+	}
+}
+
+func TestUpsertMembersSyncsEmbeddedInfo(t *testing.T) {
+	l, _ := newTestBoard(t, WithEmbeddedInfo("name", 10))
+	ctx := context.Background()
+
+	if err := l.UpsertMembers(ctx, []MemberScore{{UserID: "a", Score: 1}, {UserID: "b", Score: 2}}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := l.client().ZCard(ctx, l.displayKey()).Val(); n != 2 {
+		t.Fatalf("got %d display members, want 2", n)
+	}
+}
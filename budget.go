@@ -0,0 +1,189 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// DefaultBudgetReserve is the least time left before deadline for which
+// optional steps of composite calls are still attempted.
+const DefaultBudgetReserve = 5 * time.Millisecond
+
+const (
+	// Optional parts of composite calls, reported in LeadersPage.Degraded when skipped
+	PartInfo  = "info"
+	PartCount = "count"
+)
+
+// latencyBudget divides time left until deadline of composite call's context
+// between its steps.
+type latencyBudget struct {
+	parent   context.Context
+	deadline time.Time
+	bounded  bool
+	reserve  time.Duration
+}
+
+// WithBudgetReserve sets the least time left before caller's deadline for
+// which composite calls still attempt their optional steps,
+// DefaultBudgetReserve by default.
+func WithBudgetReserve(reserve time.Duration) Option {
+	return func(l *Leaderboard) {
+		l.budgetReserve = reserve
+	}
+}
+
+func (l *Leaderboard) newLatencyBudget(ctx context.Context) latencyBudget {
+	reserve := l.budgetReserve
+	if reserve <= 0 {
+		reserve = DefaultBudgetReserve
+	}
+
+	deadline, bounded := ctx.Deadline()
+
+	return latencyBudget{parent: ctx, deadline: deadline, bounded: bounded, reserve: reserve}
+}
+
+// step derives context of a step entitled to share of time left, later steps
+// get the rest.
+func (b latencyBudget) step(share float64) (context.Context, context.CancelFunc) {
+	if !b.bounded {
+		return context.WithCancel(b.parent)
+	}
+
+	return context.WithTimeout(b.parent, time.Duration(float64(time.Until(b.deadline))*share))
+}
+
+// affords reports whether there's enough time left to attempt an optional step.
+func (b latencyBudget) affords() bool {
+	return !b.bounded || time.Until(b.deadline) >= b.reserve
+}
+
+// exhausted reports whether step failed because it ran out of its share while
+// the call as a whole still has time.
+func (b latencyBudget) exhausted(stepCtx context.Context) bool {
+	return stepCtx.Err() != nil && b.parent.Err() == nil
+}
+
+// LeadersPage is page of the board with counts, see GetLeadersPage.
+type LeadersPage struct {
+	Users        []User `json:"users"`
+	Page         int    `json:"page"`
+	TotalMembers int    `json:"total_members"`
+	TotalPages   int    `json:"total_pages"`
+	// Degraded lists optional parts (PartInfo, PartCount) left out to meet
+	// caller's deadline
+	Degraded []string `json:"degraded,omitempty"`
+}
+
+// GetLeadersPage returns page of the board with info of its members and
+// number of members and pages. Time left until ctx deadline is divided between
+// reading the page, its info and the counts; when info or counts don't fit
+// their share (or less than the reserve is left, see WithBudgetReserve) they're
+// left out and listed in Degraded instead of failing the whole call. Only
+// failure to read the page itself fails the call.
+func (l *Leaderboard) GetLeadersPage(ctx context.Context, page int, opts ...ReadOption) (result LeadersPage, err error) {
+	ctx, done, err := l.startOp(ctx, "GetLeadersPage")
+	if err != nil {
+		return LeadersPage{}, err
+	}
+	defer done(&err)
+
+	if page < 1 {
+		page = 1
+	}
+
+	o := newReadOptions(opts)
+	pageSize := l.pageSize(o)
+	startOffset := (page - 1) * pageSize
+	endOffset := startOffset + pageSize - 1
+
+	budget := l.newLatencyBudget(ctx)
+	cli := l.readClient(o)
+	result = LeadersPage{Page: page}
+
+	key := l.rangeKey(o, endOffset)
+	if l.embeddedReads(o) {
+		key = l.displayKey()
+	}
+
+	rangeCtx, cancel := budget.step(0.5)
+	values, err := cli.ZRevRangeWithScores(rangeCtx, key, int64(startOffset), int64(endOffset)).Result()
+	cancel()
+	if err != nil {
+		return LeadersPage{}, err
+	}
+
+	if l.embeddedReads(o) {
+		result.Users = l.appendEmbeddedUsers(make([]User, 0, len(values)), values, startOffset)
+	} else {
+		result.Users = appendUsers(make([]User, 0, len(values)), values, startOffset, l.precision)
+	}
+
+	if !l.embeddedReads(o) {
+		if err := l.markGhosts(ctx, cli, result.Users); err != nil {
+			return LeadersPage{}, err
+		}
+	}
+
+	if result.Users, err = l.hideBanned(ctx, result.Users); err != nil {
+		return LeadersPage{}, err
+	}
+
+	if budget.affords() {
+		infoCtx, cancel := budget.step(0.7)
+		err := l.loadPageInfo(infoCtx, cli, result.Users, !l.embeddedReads(o))
+		cancel()
+
+		if err != nil && !budget.exhausted(infoCtx) {
+			return LeadersPage{}, err
+		}
+		if err != nil {
+			for i := range result.Users {
+				result.Users[i].AdditionalInfo, result.Users[i].Record = nil, nil
+			}
+			result.Degraded = append(result.Degraded, PartInfo)
+		}
+	} else {
+		result.Degraded = append(result.Degraded, PartInfo)
+	}
+
+	if budget.affords() {
+		countCtx, cancel := budget.step(1)
+		total, err := l.countMembers(countCtx)
+		ghosts := 0
+		if err == nil {
+			ghosts, err = l.countGhosts(countCtx)
+		}
+		cancel()
+
+		if err != nil && !budget.exhausted(countCtx) {
+			return LeadersPage{}, err
+		}
+		if err != nil {
+			result.Degraded = append(result.Degraded, PartCount)
+		} else {
+			result.TotalMembers, result.TotalPages = total-ghosts, l.pagesOf(total)
+		}
+	} else {
+		result.Degraded = append(result.Degraded, PartCount)
+	}
+
+	return result, nil
+}
+
+// loadPageInfo fills info (unless already decoded from embedded members) and records of users.
+func (l *Leaderboard) loadPageInfo(ctx context.Context, cli redis.UniversalClient, users []User, withInfo bool) error {
+	if withInfo {
+		if err := l.profiles.load(ctx, cli, users); err != nil {
+			return err
+		}
+
+		if err := l.hydrateInfo(ctx, users); err != nil {
+			return err
+		}
+	}
+
+	return l.loadRecords(ctx, cli, users)
+}
@@ -0,0 +1,195 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBulkLoadConcurrency  = 4
+	DefaultBulkLoadPipelineSize = 500
+)
+
+// MemberScore is userID with its score used by bulk operations
+type MemberScore struct {
+	UserID string
+	Score  int
+}
+
+// BulkLoadOptions configures BulkLoad.
+type BulkLoadOptions struct {
+	// Concurrency is number of workers writing batches in parallel.
+	Concurrency int
+	// PipelineSize is number of members written in one round trip.
+	PipelineSize int
+	// OpsPerSecond limits number of members written per second, 0 means unlimited.
+	OpsPerSecond int
+	// OnProgress, when set, is called with total number of loaded members after every batch.
+	// Calls are serialized.
+	OnProgress func(loaded int)
+}
+
+// BulkLoad writes members yielded by source to the board (overwriting existing
+// scores) using several concurrent workers, each writing whole batches in one
+// round trip. With OpsPerSecond set, writes are throttled so large imports don't
+// saturate production Redis.
+//
+// source must call yield for every member and stop when yield returns false.
+func (l *Leaderboard) BulkLoad(ctx context.Context, source func(yield func(member MemberScore) bool), opts BulkLoadOptions) (loaded int, err error) {
+	done, err := l.trackOp("BulkLoad")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	if l.migrationTarget() != nil {
+		return 0, ErrMigrationInProgress
+	}
+
+	if l.eventSourced {
+		return 0, ErrEventSourcingEnabled
+	}
+
+	if err := l.savepoint(ctx, "BulkLoad"); err != nil {
+		return 0, err
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultBulkLoadConcurrency
+	}
+
+	if opts.PipelineSize <= 0 {
+		opts.PipelineSize = DefaultBulkLoadPipelineSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limiter := newRateLimiter(opts.OpsPerSecond)
+	batches := make(chan []*redis.Z, opts.Concurrency)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for batch := range batches {
+				if err := limiter.wait(ctx, len(batch)); err != nil {
+					fail(err)
+					continue
+				}
+
+				pipe := l.client().TxPipeline()
+				pipe.ZAdd(ctx, l.leaderboardName, batch...)
+				l.bumpVersion(ctx, pipe)
+				if _, err := pipe.Exec(ctx); err != nil {
+					fail(err)
+					continue
+				}
+
+				mu.Lock()
+				loaded += len(batch)
+				if opts.OnProgress != nil {
+					opts.OnProgress(loaded)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	batch := make([]*redis.Z, 0, opts.PipelineSize)
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = make([]*redis.Z, 0, opts.PipelineSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	source(func(member MemberScore) bool {
+		batch = append(batch, &redis.Z{Score: float64(member.Score), Member: member.UserID})
+		if len(batch) < opts.PipelineSize {
+			return true
+		}
+
+		return send()
+	})
+
+	if len(batch) > 0 {
+		send()
+	}
+
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return loaded, firstErr
+	}
+
+	return loaded, ctx.Err()
+}
+
+// rateLimiter spaces out operations so no more than opsPerSecond are allowed per second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(opsPerSecond int) *rateLimiter {
+	if opsPerSecond <= 0 {
+		return &rateLimiter{}
+	}
+
+	return &rateLimiter{interval: time.Second / time.Duration(opsPerSecond)}
+}
+
+// wait blocks until n operations are allowed or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context, n int) error {
+	if r.interval == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	at := r.next
+	r.next = r.next.Add(r.interval * time.Duration(n))
+	r.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
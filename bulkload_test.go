@@ -0,0 +1,44 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestBulkLoad(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	progress := []int{}
+	source := func(yield func(member MemberScore) bool) {
+		for i := 0; i < 1234; i++ {
+			if !yield(MemberScore{UserID: "u" + strconv.Itoa(i), Score: i}) {
+				return
+			}
+		}
+	}
+
+	loaded, err := l.BulkLoad(ctx, source, BulkLoadOptions{Concurrency: 3, PipelineSize: 100, OnProgress: func(loaded int) {
+		progress = append(progress, loaded)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 1234 {
+		t.Fatalf("got %d loaded, want 1234", loaded)
+	}
+
+	if len(progress) != 13 || progress[len(progress)-1] != 1234 {
+		t.Fatalf("got progress %v, want 13 batches ending at 1234", progress)
+	}
+	for i := 1; i < len(progress); i++ {
+		if progress[i] <= progress[i-1] {
+			t.Fatalf("got progress %v, want it increasing", progress)
+		}
+	}
+
+	if n := l.client().ZCard(ctx, l.leaderboardName).Val(); n != 1234 {
+		t.Fatalf("got %d members, want 1234", n)
+	}
+}
@@ -0,0 +1,216 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// CapPolicy tells what happens when a new member joins a board at its member cap.
+type CapPolicy string
+
+const (
+	// CapReject rejects new members with ErrBoardFull
+	CapReject CapPolicy = "reject"
+	// CapEvictLowest removes the lowest scoring member to make room for the new one
+	CapEvictLowest CapPolicy = "evict_lowest"
+)
+
+const (
+	metaMemberCap       = "member_cap"
+	metaMemberCapPolicy = "member_cap_policy"
+
+	// fullReply is prefix of error reply of write scripts rejecting new members.
+	fullReply = "FULL"
+)
+
+var (
+	ErrBoardFull        = errors.New("leaderboard: board reached its member cap")
+	ErrInvalidCapPolicy = errors.New("leaderboard: cap policy must be CapReject or CapEvictLowest")
+)
+
+// memberCapLua defines admit and admitAll used by write scripts to enforce
+// member cap stored in board metadata. Members already on the board are always
+// admitted, lowest scoring member evicted to admit a new one is logged to score
+// log. Ghosts, recorded in metadata by AddGhost, aren't counted and never
+// evicted. Must follow scoreLogLua.
+const memberCapLua = `
+local function isGhost(metaKey, member)
+	return redis.call("HEXISTS", metaKey, "ghost:" .. member) == 1
+end
+
+local function admit(board, metaKey, logKey, member)
+	if redis.call("ZSCORE", board, member) then
+		return true
+	end
+
+	local cap = redis.call("HMGET", metaKey, "member_cap", "member_cap_policy", "ghosts")
+	local ghosts = tonumber(cap[3] or 0)
+	if not cap[1] or redis.call("ZCARD", board) - ghosts < tonumber(cap[1]) then
+		return true
+	end
+
+	if cap[2] ~= "evict_lowest" then
+		return false
+	end
+
+	-- Lowest ghosts + 1 entries hold at least one member
+	for _, lowest in ipairs(redis.call("ZRANGE", board, 0, ghosts)) do
+		if not isGhost(metaKey, lowest) then
+			redis.call("ZREM", board, lowest)
+			logOp(logKey, "remove", lowest, 0)
+			break
+		end
+	end
+
+	return true
+end
+
+-- admitAll is admit for several members written together: their new members
+-- are counted at once and none of them is evicted to make room for another.
+local function admitAll(board, metaKey, logKey, members)
+	local admitted, pending, kept = {}, 0, 0
+	for _, member in ipairs(members) do
+		if not admitted[member] then
+			if redis.call("ZSCORE", board, member) then
+				kept = kept + 1
+			else
+				pending = pending + 1
+			end
+		end
+		admitted[member] = true
+	end
+	if pending == 0 then
+		return true
+	end
+
+	local cap = redis.call("HMGET", metaKey, "member_cap", "member_cap_policy", "ghosts")
+	if not cap[1] then
+		return true
+	end
+
+	local ghosts = tonumber(cap[3] or 0)
+	local excess = redis.call("ZCARD", board) - ghosts + pending - tonumber(cap[1])
+	if excess <= 0 then
+		return true
+	end
+
+	-- Members being admitted must fit the cap on their own
+	if cap[2] ~= "evict_lowest" or kept + pending > tonumber(cap[1]) then
+		return false
+	end
+
+	-- Only kept ones of the lowest members and ghosts may be skipped
+	for _, lowest in ipairs(redis.call("ZRANGE", board, 0, excess + kept + ghosts - 1)) do
+		if excess == 0 then
+			break
+		end
+		if not admitted[lowest] and not isGhost(metaKey, lowest) then
+			redis.call("ZREM", board, lowest)
+			logOp(logKey, "remove", lowest, 0)
+			excess = excess - 1
+		end
+	end
+
+	return true
+end
+`
+
+// Inserts member ARGV[1] with score ARGV[2] unless it's on the board already,
+// if member cap (KEYS[4]) admits it. Returns {inserted, score, rank}, rank is
+// 0-based.
+var insertMemberScript = redis.NewScript(scoreLogLua + memberCapLua + versionLua + `
+local existing = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if existing then
+	return {0, existing, redis.call("ZREVRANK", KEYS[1], ARGV[1])}
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], ARGV[1]) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+logOp(KEYS[3], "set", ARGV[1], ARGV[2])
+bumpVersion(KEYS[4])
+
+return {1, ARGV[2], redis.call("ZREVRANK", KEYS[1], ARGV[1])}
+`)
+
+// SetMemberCap limits number of members on the board to max, protecting Redis
+// memory from unbounded signups. Cap is stored in board metadata and enforced
+// atomically by every write adding a new member; policy decides whether such
+// write fails with ErrBoardFull or evicts the lowest scoring member. Evicted
+// members keep their info and companion data. Ghosts don't count towards the
+// cap and aren't evicted. Maintenance operations like
+// BulkLoad or Rebuild aren't capped. max <= 0 removes the cap.
+func (l *Leaderboard) SetMemberCap(ctx context.Context, max int, policy CapPolicy) (err error) {
+	done, err := l.trackOp("SetMemberCap")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if max <= 0 {
+		return l.client().HDel(ctx, l.metaKey(), metaMemberCap, metaMemberCapPolicy).Err()
+	}
+
+	if policy != CapReject && policy != CapEvictLowest {
+		return ErrInvalidCapPolicy
+	}
+
+	return l.client().HSet(ctx, l.metaKey(), metaMemberCap, max, metaMemberCapPolicy, string(policy)).Err()
+}
+
+// MemberCap returns board's member cap and its policy, 0 when board isn't capped.
+func (l *Leaderboard) MemberCap(ctx context.Context) (max int, policy CapPolicy, err error) {
+	defer l.wrapError("MemberCap", "", &err)
+
+	values, err := l.client().HMGet(ctx, l.metaKey(), metaMemberCap, metaMemberCapPolicy).Result()
+	if err != nil {
+		return 0, "", err
+	}
+
+	text, ok := values[0].(string)
+	if !ok {
+		return 0, "", nil
+	}
+
+	if max, err = strconv.Atoi(text); err != nil {
+		return 0, "", err
+	}
+	policyText, _ := values[1].(string)
+
+	return max, CapPolicy(policyText), nil
+}
+
+// insertMember adds member to sorted set board unless it's there already,
+// honouring member cap. Member's score and 1-based rank within board are
+// returned either way, read in the same script as the insert.
+func (l *Leaderboard) insertMember(ctx context.Context, cli redis.UniversalClient, board, userID string, score int, logFlag string) (user User, inserted bool, err error) {
+	keys := []string{board, l.locksKey(), l.scoreLogKey(), l.metaKey()}
+
+	reply, err := insertMemberScript.Run(ctx, cli, keys, userID, score, logFlag).Result()
+	if err != nil {
+		return User{}, false, scriptError(err)
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 3 {
+		return User{}, false, errUnexpectedScriptReply
+	}
+
+	flag, _ := res[0].(int64)
+	scoreText, _ := res[1].(string)
+	rank, ok := res[2].(int64)
+	if !ok {
+		return User{}, false, errUnexpectedScriptReply
+	}
+
+	floatScore, err := parseScore(scoreText)
+	if err != nil {
+		return User{}, false, err
+	}
+
+	return l.scoredUser(userID, floatScore, int(rank)+1), flag == 1, nil
+}
@@ -0,0 +1,94 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMemberCap(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		policy  CapPolicy
+		write   func(l *Leaderboard) error
+		wantErr error
+		wantIDs []string
+	}{
+		{"reject insert", CapReject, func(l *Leaderboard) error {
+			_, err := l.FirstOrInsertMember(ctx, "d", 5)
+			return err
+		}, ErrBoardFull, []string{"c", "b", "a"}},
+		{"reject increment", CapReject, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScore(ctx, "d", 5)
+			return err
+		}, ErrBoardFull, []string{"c", "b", "a"}},
+		{"reject keeps existing members writable", CapReject, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScore(ctx, "a", 5)
+			return err
+		}, nil, []string{"a", "c", "b"}},
+		{"evict lowest on insert", CapEvictLowest, func(l *Leaderboard) error {
+			_, err := l.FirstOrInsertMember(ctx, "d", 5)
+			return err
+		}, nil, []string{"d", "c", "b"}},
+		{"evict lowest on increment", CapEvictLowest, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScore(ctx, "d", 2)
+			return err
+		}, nil, []string{"c", "d", "b"}},
+		{"evict lowest keeps existing member", CapEvictLowest, func(l *Leaderboard) error {
+			_, err := l.FirstOrInsertMember(ctx, "a", 5)
+			return err
+		}, nil, []string{"c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 1, "b", 2, "c", 3)
+
+			if err := l.SetMemberCap(ctx, 3, tt.policy); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.write(l); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			ids := l.client().ZRevRange(ctx, l.leaderboardName, 0, -1).Val()
+			if !equalStrings(ids, tt.wantIDs) {
+				t.Fatalf("got members %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestSetMemberCap(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t)
+
+	if err := l.SetMemberCap(ctx, 3, "oldest"); !errors.Is(err, ErrInvalidCapPolicy) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidCapPolicy)
+	}
+
+	if err := l.SetMemberCap(ctx, 3, CapEvictLowest); err != nil {
+		t.Fatal(err)
+	}
+	max, policy, err := l.MemberCap(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != 3 || policy != CapEvictLowest {
+		t.Fatalf("got cap %d %q, want 3 %q", max, policy, CapEvictLowest)
+	}
+
+	if err := l.SetMemberCap(ctx, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if max, policy, err = l.MemberCap(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if max != 0 || policy != "" {
+		t.Fatalf("got cap %d %q after removal, want 0", max, policy)
+	}
+}
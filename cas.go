@@ -0,0 +1,83 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrMemberNotFound = errors.New("leaderboard: member not found")
+	ErrScoreMismatch  = errors.New("leaderboard: member's score doesn't match expected score")
+
+	errUnexpectedScriptReply = errors.New("leaderboard: unexpected script reply")
+)
+
+// Sets member's score to ARGV[3] only if it's currently ARGV[2], member isn't
+// locked in KEYS[2] and submissions are open. Returns -1 if member doesn't
+// exist, 0 on mismatch and 1 on success. Applied change is logged to KEYS[3]
+// and bumps version in metadata KEYS[4].
+var compareAndSetScoreScript = redis.NewScript(scoreLogLua + submissionWindowLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+local current = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if current == false then
+	return -1
+end
+
+if tonumber(current) ~= tonumber(ARGV[2]) then
+	return 0
+end
+
+redis.call("ZADD", KEYS[1], ARGV[3], ARGV[1])
+logOp(KEYS[3], "set", ARGV[1], ARGV[3])
+bumpVersion(KEYS[4])
+return 1
+`)
+
+// UpdateScoreCAS sets member's score to newScore only if it's currently expected,
+// so read-modify-write cycles (e.g. reconciliation with external systems) don't
+// race with live increments. ErrScoreMismatch is returned when score changed in
+// the meantime, ErrMemberNotFound when member isn't on the board and
+// ErrSubmissionsClosed outside board's submission window.
+func (l *Leaderboard) UpdateScoreCAS(ctx context.Context, userID string, expected, newScore int) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "UpdateScoreCAS", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return err
+	}
+
+	before := l.memberBefore(ctx, userID)
+
+	res, err := compareAndSetScoreScript.Run(ctx, l.client(), []string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey()}, userID, expected, newScore, l.scoreLogFlag()).Int()
+	if err != nil {
+		return scriptError(err)
+	}
+
+	switch res {
+	case -1:
+		return ErrMemberNotFound
+	case 0:
+		return ErrScoreMismatch
+	}
+
+	l.scoreWritten(ctx, userID, float64(newScore))
+
+	if l.eventsEnabled() {
+		if after, err := l.getMember(ctx, userID, false, readOptions{consistency: Strong}); err == nil {
+			l.emitScoreChange(ctx, before, after)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateScoreCAS(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		userID    string
+		expected  int
+		closed    bool
+		want      error
+		wantScore int
+	}{
+		{"matching", "a", 5, false, nil, 9},
+		{"mismatch", "a", 4, false, ErrScoreMismatch, 5},
+		{"missing", "b", 0, false, ErrMemberNotFound, 0},
+		{"submissions closed", "a", 5, true, ErrSubmissionsClosed, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 5)
+			if tt.closed {
+				if err := l.SetSubmissionWindow(ctx, time.Time{}, time.Now().Add(-time.Hour)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := l.UpdateScoreCAS(ctx, tt.userID, tt.expected, 9); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			if score := l.client().ZScore(ctx, l.leaderboardName, tt.userID).Val(); int(score) != tt.wantScore {
+				t.Fatalf("got score %v, want %d", score, tt.wantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strings"
+	"sync"
+	"time"
+)
+
+// invalidationChannel is channel Redis publishes invalidation messages to for
+// RESP2 connections using client tracking with REDIRECT.
+const invalidationChannel = "__redis__:invalidate"
+
+// clientCache keeps hot reads (first pages and number of members) in memory.
+// Entries are dropped when Redis reports that board's keys changed, using
+// server assisted client side caching (CLIENT TRACKING in broadcasting mode),
+// so no TTL has to be guessed.
+//
+// go-redis speaks RESP2, so invalidations are received through a dedicated
+// pub/sub connection which redirects tracking to itself.
+type clientCache struct {
+	maxPages int
+
+	startOnce sync.Once
+	pubSub    *redis.PubSub
+
+	mu sync.Mutex
+	// active is set while invalidations are being received, cache isn't used otherwise
+	active     bool
+	generation uint64
+	pages      map[int][]User
+	total      int
+	hasTotal   bool
+}
+
+// WithClientSideCache caches first maxPages pages of GetLeaders and number of
+// members in memory until Redis reports that board's sorted set or info hash
+// changed. Strong reads bypass the cache. Values are loaded the same way as
+// uncached reads, so with WithReadReplica they may lag like replica reads. Caching requires a single Redis
+// server with client tracking (Redis 6+) and is disabled for RingAddrs,
+// ClusterAddrs and SentinelAddrs.
+func WithClientSideCache(maxPages int) Option {
+	return func(l *Leaderboard) {
+		if maxPages < 1 {
+			maxPages = 1
+		}
+		l.clientCache = &clientCache{maxPages: maxPages}
+	}
+}
+
+// cacheReady starts receiving invalidations on first use and reports whether
+// cache may be used for read with given options.
+func (l *Leaderboard) cacheReady(o readOptions) bool {
+	c := l.clientCache
+	if c == nil || o.consistency == Strong {
+		return false
+	}
+
+	c.startOnce.Do(func() {
+		if l.RedisSettings.singleNode() {
+			c.start(l.RedisSettings, trackingPrefixes(l.leaderboardName, l.profiles.hashName))
+		}
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.active
+}
+
+func (c *clientCache) start(settings RedisSettings, prefixes []string) {
+	invalidations := redis.NewClient(&redis.Options{
+		Addr:     settings.Host,
+		Password: settings.Password,
+		DB:       settings.DB,
+		// Called for every (re)connection of the pub/sub connection before it subscribes
+		OnConnect: func(ctx context.Context, cn *redis.Conn) error {
+			id, err := cn.ClientID(ctx).Result()
+			if err != nil {
+				return err
+			}
+
+			args := []interface{}{"client", "tracking", "on", "redirect", id, "bcast"}
+			for _, prefix := range prefixes {
+				args = append(args, "prefix", prefix)
+			}
+
+			cmd := redis.NewStatusCmd(ctx, args...)
+			if err := cn.Process(ctx, cmd); err != nil {
+				return err
+			}
+
+			// Invalidations may have been missed while disconnected, cache is
+			// enabled again once subscription is confirmed
+			c.reset(false)
+
+			return nil
+		},
+	})
+
+	pubSub := invalidations.Subscribe(context.Background(), invalidationChannel)
+	c.mu.Lock()
+	c.pubSub = pubSub
+	c.mu.Unlock()
+
+	go c.listen(invalidations, pubSub)
+}
+
+func (c *clientCache) listen(invalidations *redis.Client, pubSub *redis.PubSub) {
+	defer invalidations.Close()
+
+	ctx := context.Background()
+	for {
+		msg, err := pubSub.Receive(ctx)
+		if err != nil {
+			if errors.Is(err, redis.ErrClosed) {
+				c.reset(false)
+				return
+			}
+
+			// Redis flushed its tracking table (nil payload) or connection was
+			// lost. Connection is re-established by next Receive, if it wasn't
+			// lost cache is reset and used again.
+			if pubSub.Ping(ctx) != nil {
+				c.reset(false)
+				time.Sleep(time.Second)
+				continue
+			}
+			c.reset(true)
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			c.reset(true)
+		case *redis.Message:
+			if msg.Channel == invalidationChannel {
+				c.reset(true)
+			}
+		}
+	}
+}
+
+// reset drops all cached values. Board keys share common prefixes and are
+// always changed together, so there's no point in per-key invalidation.
+func (c *clientCache) reset(active bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.active = active
+	c.generation++
+	c.pages = nil
+	c.hasTotal = false
+}
+
+// close stops receiving invalidations.
+func (c *clientCache) close() error {
+	c.mu.Lock()
+	pubSub := c.pubSub
+	c.mu.Unlock()
+
+	if pubSub == nil {
+		return nil
+	}
+
+	return pubSub.Close()
+}
+
+func (c *clientCache) page(page int) ([]User, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	users, ok := c.pages[page]
+	if ok {
+		users = append([]User(nil), users...)
+	}
+
+	return users, c.generation, ok
+}
+
+// pageInto is page appending cached users to buf[:0] instead of allocating a copy.
+func (c *clientCache) pageInto(page int, buf []User) ([]User, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	users, ok := c.pages[page]
+	if ok {
+		buf = append(buf[:0], users...)
+	}
+
+	return buf, c.generation, ok
+}
+
+// storePage caches page unless it was invalidated since generation was read.
+func (c *clientCache) storePage(page int, generation uint64, users []User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if page > c.maxPages || generation != c.generation || !c.active {
+		return
+	}
+
+	if c.pages == nil {
+		c.pages = map[int][]User{}
+	}
+	c.pages[page] = append([]User(nil), users...)
+}
+
+func (c *clientCache) totalMembers() (int, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.total, c.generation, c.hasTotal
+}
+
+func (c *clientCache) storeTotal(generation uint64, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation || !c.active {
+		return
+	}
+	c.total, c.hasTotal = total, true
+}
+
+// trackingPrefixes returns key prefixes to track. Redis refuses overlapping
+// prefixes, so only the shorter one is kept when one contains the other.
+func trackingPrefixes(leaderboardName, hashName string) []string {
+	switch {
+	case hashName == "" || strings.HasPrefix(hashName, leaderboardName):
+		return []string{leaderboardName}
+	case strings.HasPrefix(leaderboardName, hashName):
+		return []string{hashName}
+	default:
+		return []string{leaderboardName, hashName}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build integration
+// +build integration
+
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"testing"
+	"time"
+)
+
+func TestClientSideCacheInvalidatedByWrites(t *testing.T) {
+	addr := integrationRedisAddr(t)
+	ctx := context.Background()
+
+	// Cache needs settings of the server to open its own invalidation connection
+	name := "test:" + t.Name()
+	l, err := NewLeaderboard(RedisSettings{Host: addr}, StagingMode, name, name+":info", 2, WithClientSideCache(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanupIntegrationBoard(t, l, addr)
+
+	seed(t, l, "a", 1, "b", 2)
+
+	cached := func() bool {
+		_, _, ok := l.clientCache.page(1)
+		return ok
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cached() {
+		if time.Now().After(deadline) {
+			t.Fatal("first page wasn't cached")
+		}
+		if _, err := l.GetLeaders(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Write bypassing the board, only Redis can tell the cache about it
+	other := redis.NewClient(&redis.Options{Addr: addr})
+	defer other.Close()
+	if err := other.ZAdd(ctx, name, &redis.Z{Score: 10, Member: "x"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for cached() {
+		if time.Now().After(deadline) {
+			t.Fatal("first page wasn't invalidated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	users, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := userIDs(users); !equalStrings(got, []string{"x", "b"}) {
+		t.Fatalf("got leaders %v, want [x b]", got)
+	}
+}
@@ -0,0 +1,105 @@
+package go_redis_leaderboard
+
+import (
+	"strconv"
+	"sync"
+)
+
+// flightGroup coalesces identical concurrent reads into one call.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+	// shared is set when at least one other caller waits for the result
+	shared bool
+}
+
+// WithReadCoalescing makes identical concurrent GetLeaders (same page, page
+// size and read options) and GetMember (same member and read options) calls
+// share one Redis query: the first caller reads, the others wait for its
+// result, so a spike of traffic on the top page costs one query instead of
+// thousands. Waiting callers get the first caller's errors too, including its
+// timeout.
+func WithReadCoalescing() Option {
+	return func(l *Leaderboard) {
+		l.flights = &flightGroup{}
+	}
+}
+
+// do calls fn unless call with the same key is in flight, then it waits for
+// and returns its result. shared reports whether result is returned to more
+// than one caller, so mutable results must be copied.
+func (g *flightGroup) do(key string, fn func() (interface{}, error)) (value interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		f.shared = true
+		g.mu.Unlock()
+		f.wg.Wait()
+
+		return f.value, f.err, true
+	}
+
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f := &flight{}
+	f.wg.Add(1)
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		shared = f.shared
+		g.mu.Unlock()
+		f.wg.Done()
+	}()
+
+	f.value, f.err = fn()
+
+	return f.value, f.err, false
+}
+
+// coalesceUsers runs read through flight group of the board, if enabled, and
+// gives every caller its own copy of shared users.
+func (l *Leaderboard) coalesceUsers(key string, read func() ([]User, error)) ([]User, error) {
+	if l.flights == nil {
+		return read()
+	}
+
+	value, err, shared := l.flights.do(key, func() (interface{}, error) {
+		return read()
+	})
+
+	users, _ := value.([]User)
+	if shared && users != nil {
+		users = append([]User(nil), users...)
+	}
+
+	return users, err
+}
+
+// coalesceMember is coalesceUsers for single member.
+func (l *Leaderboard) coalesceMember(key string, read func() (User, error)) (User, error) {
+	if l.flights == nil {
+		return read()
+	}
+
+	value, err, _ := l.flights.do(key, func() (interface{}, error) {
+		return read()
+	})
+
+	user, _ := value.(User)
+
+	return user, err
+}
+
+// flightKey identifies read of a board by kind, arguments and read options.
+func flightKey(kind, arg string, o readOptions) string {
+	return kind + "|" + arg + "|" + strconv.Itoa(int(o.consistency)) + "|" + strconv.Itoa(o.pageSize) + "|" + o.region + "|" + strconv.FormatBool(o.withoutInfo)
+}
@@ -0,0 +1,120 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrEmptyView = errors.New("leaderboard: combined view needs at least one board")
+)
+
+// ViewSource resolves board of a CombinedView at query time, so e.g. board of
+// the current period is picked up after rollover.
+type ViewSource func(ctx context.Context) (*Leaderboard, error)
+
+// BoardSource is ViewSource always resolving to l.
+func BoardSource(l *Leaderboard) ViewSource {
+	return func(ctx context.Context) (*Leaderboard, error) {
+		return l, nil
+	}
+}
+
+// Source returns ViewSource resolving to board of the current period.
+func (p *PeriodicLeaderboard) Source() ViewSource {
+	return p.Current
+}
+
+// CombinedMember is member found by CombinedView together with board it was found on.
+type CombinedMember struct {
+	User
+	Board string `json:"board"`
+}
+
+// CombinedView answers queries from the first board of a chain which can answer
+// them, e.g. "today's score if any, otherwise lifetime" with chain of daily
+// periodic board followed by all-time board.
+type CombinedView struct {
+	sources []ViewSource
+}
+
+// NewCombinedView is constructor for CombinedView checking sources in given order.
+func NewCombinedView(sources ...ViewSource) (*CombinedView, error) {
+	if len(sources) == 0 {
+		return nil, ErrEmptyView
+	}
+
+	return &CombinedView{sources: sources}, nil
+}
+
+// GetMember returns member from the first board of the chain they're ranked on.
+// When they aren't ranked on any, the last board's UnrankedPolicy applies.
+func (v *CombinedView) GetMember(ctx context.Context, userID string, withInfo bool, opts ...ReadOption) (CombinedMember, error) {
+	var last *Leaderboard
+	for _, source := range v.sources {
+		l, err := source(ctx)
+		if err != nil {
+			return CombinedMember{}, err
+		}
+		last = l
+
+		user, err := l.lookupInView(ctx, userID, withInfo, newReadOptions(opts))
+		if err != nil {
+			return CombinedMember{}, err
+		}
+
+		if user.Rank != UnrankedMember {
+			if user, err = l.presentMember(ctx, user); err != nil {
+				return CombinedMember{}, err
+			}
+
+			return CombinedMember{User: user, Board: l.leaderboardName}, nil
+		}
+	}
+
+	user, err := last.presentMember(ctx, User{UserID: userID, Rank: UnrankedMember})
+	if err != nil {
+		return CombinedMember{}, err
+	}
+
+	return CombinedMember{User: user}, nil
+}
+
+// lookupInView is getMember tracked as operation of l.
+func (l *Leaderboard) lookupInView(ctx context.Context, userID string, withInfo bool, o readOptions) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMember", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	return l.getMember(ctx, userID, withInfo, o)
+}
+
+// GetLeaders returns page of the first board of the chain which has any
+// members, together with that board's name.
+func (v *CombinedView) GetLeaders(ctx context.Context, page int, opts ...ReadOption) (users []User, board string, err error) {
+	for i, source := range v.sources {
+		l, err := source(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if i < len(v.sources)-1 {
+			total, err := l.countMembers(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if total == 0 {
+				continue
+			}
+		}
+
+		users, err := l.GetLeaders(ctx, page, opts...)
+
+		return users, l.leaderboardName, err
+	}
+
+	return []User{}, "", nil
+}
@@ -0,0 +1,106 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// Comparison is result of CompareMembers.
+type Comparison struct {
+	A User `json:"a"`
+	B User `json:"b"`
+	// Gap is A's score minus B's score
+	Gap int `json:"gap"`
+	// HeadToHead is A's record against B recorded by RecordResultAgainst, set
+	// when board tracks records
+	HeadToHead *Record `json:"head_to_head,omitempty"`
+}
+
+// CompareMembers returns ranks and scores of two members, the gap between them
+// and, when board tracks records, their records and A's head-to-head record
+// against B, for rivalry screens. Everything is read in one MULTI/EXEC round
+// trip. Unranked members are returned according to board's UnrankedPolicy, with
+// UnrankedExclude ErrMemberNotFound is returned.
+func (l *Leaderboard) CompareMembers(ctx context.Context, a, b string) (comparison Comparison, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "CompareMembers", a)
+	if err != nil {
+		return Comparison{}, err
+	}
+	defer done(&err)
+
+	pipe := l.client().TxPipeline()
+	rankCmds := []*redis.IntCmd{pipe.ZRevRank(ctx, l.leaderboardName, a), pipe.ZRevRank(ctx, l.leaderboardName, b)}
+	scoreCmds := []*redis.FloatCmd{pipe.ZScore(ctx, l.leaderboardName, a), pipe.ZScore(ctx, l.leaderboardName, b)}
+
+	var recordsCmd, headToHeadCmd *redis.SliceCmd
+	if l.records {
+		recordsCmd = pipe.HMGet(ctx, l.recordsKey(),
+			recordField(a, OutcomeWin), recordField(a, OutcomeLoss), recordField(a, OutcomeDraw),
+			recordField(b, OutcomeWin), recordField(b, OutcomeLoss), recordField(b, OutcomeDraw))
+		headToHeadCmd = pipe.HMGet(ctx, l.headToHeadKey(),
+			headToHeadField(a, b, OutcomeWin), headToHeadField(a, b, OutcomeLoss), headToHeadField(a, b, OutcomeDraw))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return Comparison{}, err
+	}
+
+	users := make([]User, 2)
+	for i, userID := range []string{a, b} {
+		rank, err := rankCmds[i].Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				return Comparison{}, err
+			}
+
+			users[i] = User{UserID: userID, Rank: UnrankedMember}
+			continue
+		}
+
+		score, err := scoreCmds[i].Result()
+		if err != nil {
+			return Comparison{}, err
+		}
+
+		users[i] = l.scoredUser(userID, score, int(rank)+1)
+	}
+
+	if err := l.markGhosts(ctx, l.client(), users); err != nil {
+		return Comparison{}, err
+	}
+
+	if l.records {
+		values := recordsCmd.Val()
+		for i := range users {
+			if users[i].Rank != UnrankedMember {
+				users[i].Record = recordOf(values[i*3 : i*3+3])
+			}
+		}
+		comparison.HeadToHead = recordOf(headToHeadCmd.Val())
+	}
+
+	comparison.Gap = users[0].Score - users[1].Score
+	if comparison.A, err = l.presentMember(ctx, users[0]); err != nil {
+		return Comparison{}, err
+	}
+	if comparison.B, err = l.presentMember(ctx, users[1]); err != nil {
+		return Comparison{}, err
+	}
+
+	return comparison, nil
+}
+
+// recordOf converts HMGET reply of win, loss and draw counters to Record.
+func recordOf(values []interface{}) *Record {
+	counts := make([]int, 3)
+	for i := range counts {
+		if i < len(values) {
+			text, _ := values[i].(string)
+			counts[i], _ = strconv.Atoi(text)
+		}
+	}
+
+	return &Record{Wins: counts[0], Losses: counts[1], Draws: counts[2]}
+}
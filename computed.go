@@ -0,0 +1,207 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoSources = errors.New("leaderboard: computed board needs at least one source board")
+)
+
+// ComputedMember is input of ComputedOptions.Score: member of the first source
+// board with its scores on all source boards.
+type ComputedMember struct {
+	UserID string
+	// Scores holds member's score on each source board, in order of ComputedOptions.Sources
+	Scores []float64
+	// Present tells whether member is on each source board, missing scores are 0
+	Present []bool
+}
+
+// ComputedOptions configures ComputedBoard.
+type ComputedOptions struct {
+	// Sources are boards scores are derived from. Members of the first source
+	// are iterated, the others are looked up for every member.
+	Sources []*Leaderboard
+	// Score returns member's score on the computed board, or false to leave
+	// member out, e.g. ratio of kills to games played
+	Score func(member ComputedMember) (float64, bool)
+	// RefreshInterval makes board refreshed in background until Stop is
+	// called, 0 leaves refreshing to explicit Refresh calls
+	RefreshInterval time.Duration
+	// OnError is called with errors of background refreshes
+	OnError func(error)
+}
+
+// ComputedBoard is a board derived from other boards by a scoring callback and
+// materialized on a schedule, so derived rankings don't need custom batch jobs.
+// Target is a regular Leaderboard, so all read APIs work for it.
+type ComputedBoard struct {
+	target *Leaderboard
+	opts   ComputedOptions
+
+	// mu serializes refreshes
+	mu sync.Mutex
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewComputedBoard is constructor for ComputedBoard materialized into target.
+// When opts.RefreshInterval is set, target is refreshed in background until
+// Stop is called.
+func NewComputedBoard(target *Leaderboard, opts ComputedOptions) (*ComputedBoard, error) {
+	if len(opts.Sources) == 0 {
+		return nil, ErrNoSources
+	}
+
+	c := &ComputedBoard{target: target, opts: opts, stop: make(chan struct{})}
+
+	if opts.RefreshInterval > 0 {
+		c.wg.Add(1)
+		go c.maintain()
+	}
+
+	return c, nil
+}
+
+// Board returns board the results are materialized into.
+func (c *ComputedBoard) Board() *Leaderboard {
+	return c.target
+}
+
+// Refresh recomputes all scores into a shadow key which then atomically
+// replaces the target board, so readers never see a half-computed board.
+// Number of members on the refreshed board is returned.
+func (c *ComputedBoard) Refresh(ctx context.Context) (total int, err error) {
+	l := c.target
+	ctx, done, err := l.startOp(ctx, "Refresh")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l.migrationTarget() != nil {
+		return 0, ErrMigrationInProgress
+	}
+
+	if l.eventSourced {
+		return 0, ErrEventSourcingEnabled
+	}
+
+	tmpKey, err := temporaryKey(l.leaderboardName + ":computed")
+	if err != nil {
+		return 0, err
+	}
+
+	first := c.opts.Sources[0]
+	err = scanSortedSet(ctx, first.client(), first.leaderboardName, rebuildBatchSize, func(members []redis.Z) error {
+		computed, err := c.compute(ctx, members)
+		if err != nil || len(computed) == 0 {
+			return err
+		}
+
+		pipe := l.client().Pipeline()
+		pipe.ZAdd(ctx, tmpKey, zPointers(computed)...)
+		pipe.Expire(ctx, tmpKey, rebuildKeyTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		total += len(computed)
+
+		return nil
+	})
+	if err != nil {
+		_ = l.client().Del(ctx, tmpKey).Err()
+		return 0, err
+	}
+
+	if err := swapIn(ctx, l.client(), tmpKey, l.leaderboardName, total, l.queueBumpVersion(ctx)); err != nil {
+		return 0, err
+	}
+	l.recordWrite(ctx)
+
+	return total, nil
+}
+
+// compute scores one batch of members of the first source.
+func (c *ComputedBoard) compute(ctx context.Context, members []redis.Z) ([]redis.Z, error) {
+	sources := c.opts.Sources
+	inputs := make([]ComputedMember, len(members))
+	for i, z := range members {
+		inputs[i] = ComputedMember{
+			UserID:  z.Member.(string),
+			Scores:  make([]float64, len(sources)),
+			Present: make([]bool, len(sources)),
+		}
+		inputs[i].Scores[0], inputs[i].Present[0] = z.Score, true
+	}
+
+	for s := 1; s < len(sources); s++ {
+		pipe := sources[s].client().Pipeline()
+		cmds := make([]*redis.FloatCmd, len(inputs))
+		for i, in := range inputs {
+			cmds[i] = pipe.ZScore(ctx, sources[s].leaderboardName, in.UserID)
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+
+		for i, cmd := range cmds {
+			score, err := cmd.Result()
+			switch {
+			case errors.Is(err, redis.Nil):
+			case err != nil:
+				return nil, err
+			default:
+				inputs[i].Scores[s], inputs[i].Present[s] = score, true
+			}
+		}
+	}
+
+	computed := make([]redis.Z, 0, len(inputs))
+	for _, in := range inputs {
+		if score, ok := c.opts.Score(in); ok {
+			computed = append(computed, redis.Z{Member: in.UserID, Score: score})
+		}
+	}
+
+	return computed, nil
+}
+
+func (c *ComputedBoard) maintain() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if _, err := c.Refresh(ctx); err != nil && c.opts.OnError != nil {
+				c.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// Stop stops background refreshing, waiting for running refresh to finish.
+func (c *ComputedBoard) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+}
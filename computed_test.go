@@ -0,0 +1,84 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newComputedTestBoards returns kills and games source boards and target board
+// sharing one Redis.
+func newComputedTestBoards(t *testing.T) (kills, games, target *Leaderboard) {
+	t.Helper()
+
+	kills, _ = newTestBoard(t)
+	boards := []*Leaderboard{kills}
+	for _, name := range []string{"games", "ratio"} {
+		l, err := NewLeaderboardWithClient(kills.client(), StagingMode, name, "test_info", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = l.Shutdown(context.Background())
+		})
+		boards = append(boards, l)
+	}
+
+	seed(t, boards[0], "a", 10, "b", 6, "c", 3)
+	seed(t, boards[1], "a", 5, "b", 2)
+
+	return boards[0], boards[1], boards[2]
+}
+
+func killsPerGame(member ComputedMember) (float64, bool) {
+	if !member.Present[1] || member.Scores[1] == 0 {
+		return 0, false
+	}
+
+	return member.Scores[0] / member.Scores[1], true
+}
+
+func TestComputedBoardRefresh(t *testing.T) {
+	kills, games, target := newComputedTestBoards(t)
+	ctx := context.Background()
+
+	c, err := NewComputedBoard(target, ComputedOptions{Sources: []*Leaderboard{kills, games}, Score: killsPerGame})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := c.Refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("got %d members, want 2", total)
+	}
+
+	users, err := target.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"b", "a"}) || users[0].Score != 3 || users[1].Score != 2 {
+		t.Fatalf("got %+v, want b with 3 and a with 2", users)
+	}
+}
+
+func TestComputedBoardRefreshesInBackground(t *testing.T) {
+	kills, games, target := newComputedTestBoards(t)
+	ctx := context.Background()
+
+	c, err := NewComputedBoard(target, ComputedOptions{Sources: []*Leaderboard{kills, games}, Score: killsPerGame, RefreshInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for target.client().ZCard(ctx, target.leaderboardName).Val() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("target wasn't refreshed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
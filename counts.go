@@ -0,0 +1,93 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// countCache keeps number of members for a short time so frequently polled
+// TotalMembers/TotalPages don't hit Redis on every call.
+type countCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	value     int
+	fetchedAt time.Time
+}
+
+// WithCountCache caches number of members (used by TotalMembers, TotalPages,
+// CountPages and GetLeaders) for ttl. Counts may be stale by up to ttl.
+func WithCountCache(ttl time.Duration) Option {
+	return func(l *Leaderboard) {
+		if ttl > 0 {
+			l.countCache = &countCache{ttl: ttl}
+		}
+	}
+}
+
+func (c *countCache) get(fetch func() (int, error)) (int, error) {
+	return c.within(c.ttl, fetch)
+}
+
+// within returns cached value if it isn't older than maxAge, otherwise fetches
+// it. Concurrent callers wait for a single fetch.
+func (c *countCache) within(maxAge time.Duration, fetch func() (int, error)) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < maxAge {
+		return c.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return 0, err
+	}
+
+	c.value, c.fetchedAt = value, time.Now()
+
+	return value, nil
+}
+
+// ExactTotalPages returns number of pages counted with ZCARD on primary,
+// bypassing count caches and client-side cache.
+func (l *Leaderboard) ExactTotalPages(ctx context.Context) (pages int, err error) {
+	ctx, done, err := l.startOp(ctx, "ExactTotalPages")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	total, err := l.client().ZCard(ctx, l.leaderboardName).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return l.pagesOf(int(total)), nil
+}
+
+// CachedTotalPages returns number of pages which may be stale by up to
+// maxStaleness, for dashboards polling it constantly. Bursts of calls on a
+// stale count wait for a single ZCARD. Its cache is independent of
+// WithCountCache, so callers may choose staleness per call.
+func (l *Leaderboard) CachedTotalPages(ctx context.Context, maxStaleness time.Duration) (pages int, err error) {
+	ctx, done, err := l.startOp(ctx, "CachedTotalPages")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	total, err := l.pagesCount.within(maxStaleness, func() (int, error) {
+		return l.countMembers(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return l.pagesOf(total), nil
+}
+
+func (l *Leaderboard) pagesOf(total int) int {
+	return int(math.Ceil(float64(total) / float64(l.PageSize)))
+}
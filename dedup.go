@@ -0,0 +1,31 @@
+package go_redis_leaderboard
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// WithSubmissionDedup makes SubmitScore and SubmitScoreFrom ignore submission
+// identical (same userID, score and source) to one received within window.
+// It absorbs client retries and double-taps without explicit idempotency keys;
+// ignored submissions return member's current state.
+func WithSubmissionDedup(window time.Duration) Option {
+	return func(l *Leaderboard) {
+		l.dedupWindow = window
+	}
+}
+
+// dedupKey is short-lived key marking submission as seen. Submission fields are
+// hashed so arbitrary userIDs and sources can't collide through separators.
+func (l *Leaderboard) dedupKey(userID string, score int, source string) string {
+	h := sha1.New()
+	for _, part := range []string{userID, strconv.Itoa(score), source} {
+		h.Write([]byte(strconv.Itoa(len(part))))
+		h.Write([]byte{':'})
+		h.Write([]byte(part))
+	}
+
+	return l.leaderboardName + ":dedup:" + hex.EncodeToString(h.Sum(nil))
+}
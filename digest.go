@@ -0,0 +1,182 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const digestReadBatch = 1000
+
+// RankDigest summarizes all rank changes of one member within a window, ready
+// to be turned into one push notification ("you dropped 4 places overnight").
+type RankDigest struct {
+	UserID    string    `json:"user_id"`
+	FromRank  int       `json:"from_rank"`
+	ToRank    int       `json:"to_rank"`
+	FromScore int       `json:"from_score"`
+	ToScore   int       `json:"to_score"`
+	Changes   int       `json:"changes"`
+	Since     time.Time `json:"since"`
+	Until     time.Time `json:"until"`
+}
+
+// DigestOptions configures RankDigests.
+type DigestOptions struct {
+	// MinPlacesMoved skips members whose rank moved by fewer places.
+	MinPlacesMoved int
+	// CurrentRanks replaces rank at the end of the window with member's current
+	// rank, so places lost to other members after member's last event are included.
+	CurrentRanks bool
+}
+
+// PlacesMoved returns number of places member climbed (positive) or dropped (negative).
+func (d RankDigest) PlacesMoved() int {
+	if d.FromRank == UnrankedMember || d.ToRank == UnrankedMember {
+		return 0
+	}
+
+	return d.FromRank - d.ToRank
+}
+
+// Summary returns short human readable description of the digest.
+func (d RankDigest) Summary() string {
+	switch moved := d.PlacesMoved(); {
+	case d.FromRank == UnrankedMember && d.ToRank != UnrankedMember:
+		return fmt.Sprintf("you entered the leaderboard at #%d", d.ToRank)
+	case d.ToRank == UnrankedMember:
+		return "you were removed from the leaderboard"
+	case moved > 0:
+		return fmt.Sprintf("you climbed %d %s to #%d", moved, places(moved), d.ToRank)
+	case moved < 0:
+		return fmt.Sprintf("you dropped %d %s to #%d", -moved, places(-moved), d.ToRank)
+	default:
+		return fmt.Sprintf("you held your position at #%d", d.ToRank)
+	}
+}
+
+func places(n int) string {
+	if n == 1 {
+		return "place"
+	}
+
+	return "places"
+}
+
+// RankDigests aggregates events between since and until into one digest per
+// member, ordered by number of places moved (biggest movers first). Events
+// must be enabled with WithEvents.
+func (l *Leaderboard) RankDigests(ctx context.Context, since, until time.Time, opts DigestOptions) (_ []RankDigest, err error) {
+	defer l.wrapError("RankDigests", "", &err)
+
+	digests := map[string]*RankDigest{}
+	start, end := streamID(since), streamID(until)
+
+	for {
+		events, err := l.Events(ctx, start, end, digestReadBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if event.Type == EventAnomaly {
+				continue
+			}
+
+			d, ok := digests[event.UserID]
+			if !ok {
+				d = &RankDigest{UserID: event.UserID, FromRank: event.OldRank, FromScore: event.OldScore, Since: since, Until: until}
+				digests[event.UserID] = d
+			}
+
+			d.ToRank, d.ToScore = event.NewRank, event.NewScore
+			d.Changes++
+		}
+
+		if len(events) < digestReadBatch {
+			break
+		}
+
+		start = nextStreamID(events[len(events)-1].ID)
+	}
+
+	if opts.CurrentRanks && len(digests) > 0 {
+		if err := l.refreshDigestRanks(ctx, digests); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]RankDigest, 0, len(digests))
+	for _, d := range digests {
+		if abs(d.PlacesMoved()) < opts.MinPlacesMoved && d.FromRank != UnrankedMember && d.ToRank != UnrankedMember {
+			continue
+		}
+
+		result = append(result, *d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		mi, mj := abs(result[i].PlacesMoved()), abs(result[j].PlacesMoved())
+		if mi != mj {
+			return mi > mj
+		}
+
+		return result[i].UserID < result[j].UserID
+	})
+
+	return result, nil
+}
+
+func (l *Leaderboard) refreshDigestRanks(ctx context.Context, digests map[string]*RankDigest) error {
+	pipe := l.client().Pipeline()
+	rankCmds := make(map[string]*redis.IntCmd, len(digests))
+	scoreCmds := make(map[string]*redis.FloatCmd, len(digests))
+	for userID := range digests {
+		rankCmds[userID] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+		scoreCmds[userID] = pipe.ZScore(ctx, l.leaderboardName, userID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	for userID, d := range digests {
+		rank, err := rankCmds[userID].Result()
+		if err != nil {
+			d.ToRank, d.ToScore = UnrankedMember, 0
+			continue
+		}
+
+		d.ToRank, d.ToScore = int(rank)+1, l.precision.whole(scoreCmds[userID].Val())
+	}
+
+	return nil
+}
+
+// nextStreamID returns ID directly following id, used to page through XRANGE.
+func nextStreamID(id string) string {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return id
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return id
+	}
+
+	return parts[0] + "-" + strconv.FormatUint(seq+1, 10)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,321 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultDualWriteQueueSize = 10000
+
+// DualWriteOptions configures DualWrite.
+type DualWriteOptions struct {
+	// QueueSize is number of writes waiting to be mirrored, writes arriving
+	// when queue is full are dropped and counted, DefaultDualWriteQueueSize by default
+	QueueSize int
+	// OnError is called with every failed mirrored write
+	OnError func(error)
+}
+
+// DualWriteReport tells how far behind the secondary board is.
+type DualWriteReport struct {
+	Mirrored uint64
+	Failed   uint64
+	Dropped  uint64
+	// Pending is number of writes queued for mirroring
+	Pending int
+	// Lag is time the last mirrored write spent in the queue
+	Lag       time.Duration
+	LastError error
+}
+
+// ConsistencyReport is result of DualWrite.Compare.
+type ConsistencyReport struct {
+	PrimaryMembers   int
+	SecondaryMembers int
+	Sampled          int
+	// Mismatched are sampled users whose score on secondary differs or is missing
+	Mismatched []string
+}
+
+type dualWriteOp struct {
+	enqueuedAt time.Time
+	apply      func(ctx context.Context, secondary *Leaderboard) error
+}
+
+// DualWrite writes to primary board and mirrors every successful write to
+// secondary board (different key or different Redis) in background, for
+// zero-downtime key name and schema migrations: backfill secondary, switch
+// writes to DualWrite, verify with Compare and move reads over. Failed mirrored
+// writes never fail the primary write, they're counted in Report instead.
+// Mirrored write copies member's state from primary when it's applied, so
+// replaying a write is harmless and writes queued by concurrent writers in
+// different order than they hit primary still leave secondary matching it.
+type DualWrite struct {
+	primary   *Leaderboard
+	secondary *Leaderboard
+	opts      DualWriteOptions
+
+	queue chan dualWriteOp
+	done  chan struct{}
+
+	mirrored uint64
+	failed   uint64
+	dropped  uint64
+	lag      int64
+
+	mu        sync.Mutex
+	lastError error
+	// closed is set by Close, writes queued afterwards are dropped
+	closed bool
+}
+
+// NewDualWrite is constructor for DualWrite. Close must be called to stop
+// mirroring.
+func NewDualWrite(primary, secondary *Leaderboard, opts DualWriteOptions) *DualWrite {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultDualWriteQueueSize
+	}
+
+	d := &DualWrite{
+		primary:   primary,
+		secondary: secondary,
+		opts:      opts,
+		queue:     make(chan dualWriteOp, opts.QueueSize),
+		done:      make(chan struct{}),
+	}
+	go d.mirror()
+
+	return d
+}
+
+// Primary returns board all reads should use until migration completes.
+func (d *DualWrite) Primary() *Leaderboard {
+	return d.primary
+}
+
+// Secondary returns board writes are mirrored to.
+func (d *DualWrite) Secondary() *Leaderboard {
+	return d.secondary
+}
+
+func (d *DualWrite) enqueue(apply func(ctx context.Context, secondary *Leaderboard) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		atomic.AddUint64(&d.dropped, 1)
+		return
+	}
+
+	select {
+	case d.queue <- dualWriteOp{enqueuedAt: time.Now(), apply: apply}:
+	default:
+		atomic.AddUint64(&d.dropped, 1)
+	}
+}
+
+// mirror applies queued writes one by one. Writes outlive calls which queued
+// them, so they don't inherit their contexts.
+func (d *DualWrite) mirror() {
+	defer close(d.done)
+
+	ctx := context.Background()
+
+	for op := range d.queue {
+		atomic.StoreInt64(&d.lag, int64(time.Since(op.enqueuedAt)))
+
+		if err := op.apply(ctx, d.secondary); err != nil {
+			atomic.AddUint64(&d.failed, 1)
+
+			d.mu.Lock()
+			d.lastError = err
+			d.mu.Unlock()
+
+			if d.opts.OnError != nil {
+				d.opts.OnError(err)
+			}
+			continue
+		}
+		atomic.AddUint64(&d.mirrored, 1)
+	}
+}
+
+// syncMember queues copying member's score from primary to secondary, member
+// is removed from secondary when it's no longer on primary. Score is read when
+// the write is applied, so the last write queued for member copies its latest
+// score whichever write queued it.
+func (d *DualWrite) syncMember(userID string) {
+	d.enqueue(func(ctx context.Context, s *Leaderboard) error {
+		score, err := optionalScore(d.primary.client().ZScore(ctx, d.primary.leaderboardName, userID))
+		if err != nil {
+			return err
+		}
+
+		if score == nil {
+			_, err := s.RemoveMember(ctx, userID)
+			return err
+		}
+
+		pipe := s.client().TxPipeline()
+		pipe.ZAdd(ctx, s.leaderboardName, &redis.Z{Score: *score, Member: userID})
+		s.bumpVersion(ctx, pipe)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		s.scoreWritten(ctx, userID, *score)
+
+		return nil
+	})
+}
+
+// FirstOrInsertMember is Leaderboard.FirstOrInsertMember mirrored to secondary.
+func (d *DualWrite) FirstOrInsertMember(ctx context.Context, userID string, score int) (User, error) {
+	user, err := d.primary.FirstOrInsertMember(ctx, userID, score)
+	if err == nil {
+		d.syncMember(userID)
+	}
+
+	return user, err
+}
+
+// IncrementMemberScore is Leaderboard.IncrementMemberScore mirrored to secondary.
+func (d *DualWrite) IncrementMemberScore(ctx context.Context, userID string, incrementBy int) (User, error) {
+	user, err := d.primary.IncrementMemberScore(ctx, userID, incrementBy)
+	if err == nil {
+		d.syncMember(userID)
+	}
+
+	return user, err
+}
+
+// SubmitScore is Leaderboard.SubmitScore mirrored to secondary.
+func (d *DualWrite) SubmitScore(ctx context.Context, userID string, score int) (User, error) {
+	user, err := d.primary.SubmitScore(ctx, userID, score)
+	if err == nil {
+		d.syncMember(userID)
+	}
+
+	return user, err
+}
+
+// RemoveMember is Leaderboard.RemoveMember mirrored to secondary.
+func (d *DualWrite) RemoveMember(ctx context.Context, userID string) (bool, error) {
+	existed, err := d.primary.RemoveMember(ctx, userID)
+	if err == nil {
+		d.syncMember(userID)
+	}
+
+	return existed, err
+}
+
+// UpsertMemberInfo is Leaderboard.UpsertMemberInfo mirrored to secondary.
+// Boards sharing profile store don't need it mirrored.
+func (d *DualWrite) UpsertMemberInfo(ctx context.Context, userID string, additionalData AdditionalUserInfo) error {
+	err := d.primary.UpsertMemberInfo(ctx, userID, additionalData)
+	if err == nil && d.primary.profiles != d.secondary.profiles {
+		// Like scores, info is copied as primary has it when write is applied
+		d.enqueue(func(ctx context.Context, s *Leaderboard) error {
+			data, err := d.primary.profiles.get(ctx, userID)
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return s.UpsertMemberInfo(ctx, userID, AdditionalUserInfo(data))
+		})
+	}
+
+	return err
+}
+
+// Report returns mirroring counters and lag.
+func (d *DualWrite) Report() DualWriteReport {
+	d.mu.Lock()
+	lastError := d.lastError
+	d.mu.Unlock()
+
+	return DualWriteReport{
+		Mirrored:  atomic.LoadUint64(&d.mirrored),
+		Failed:    atomic.LoadUint64(&d.failed),
+		Dropped:   atomic.LoadUint64(&d.dropped),
+		Pending:   len(d.queue),
+		Lag:       time.Duration(atomic.LoadInt64(&d.lag)),
+		LastError: lastError,
+	}
+}
+
+// Compare checks consistency of secondary with primary: sizes of both boards
+// and scores of top sampleSize members of primary. Boards are read while writes
+// may still be in flight, so small differences are expected while Pending > 0.
+func (d *DualWrite) Compare(ctx context.Context, sampleSize int) (ConsistencyReport, error) {
+	var report ConsistencyReport
+
+	primaryMembers, err := d.primary.countMembers(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	secondaryMembers, err := d.secondary.countMembers(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.PrimaryMembers, report.SecondaryMembers = primaryMembers, secondaryMembers
+
+	if sampleSize <= 0 {
+		return report, nil
+	}
+
+	sample, err := d.primary.client().ZRevRangeWithScores(ctx, d.primary.leaderboardName, 0, int64(sampleSize-1)).Result()
+	if err != nil {
+		return report, err
+	}
+	report.Sampled = len(sample)
+
+	pipe := d.secondary.client().Pipeline()
+	cmds := make([]*redis.FloatCmd, len(sample))
+	for i, z := range sample {
+		cmds[i] = pipe.ZScore(ctx, d.secondary.leaderboardName, z.Member.(string))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return report, err
+	}
+
+	for i, cmd := range cmds {
+		score, err := cmd.Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return report, err
+		}
+
+		if err != nil || score != sample[i].Score {
+			report.Mismatched = append(report.Mismatched, sample[i].Member.(string))
+		}
+	}
+
+	return report, nil
+}
+
+// Close stops mirroring writes and waits until queued writes are mirrored or
+// ctx is done. Writes done afterwards still go to primary, but they aren't
+// mirrored and are counted as dropped.
+func (d *DualWrite) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
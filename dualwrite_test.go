@@ -0,0 +1,168 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newTestDualWrite returns DualWrite from board "test" to board "test_v2" on
+// the same miniredis.
+func newTestDualWrite(t *testing.T, opts ...Option) (d *DualWrite, primary, secondary *Leaderboard) {
+	t.Helper()
+
+	primary, _ = newTestBoard(t, opts...)
+	secondary, err := NewLeaderboardWithClient(primary.client(), StagingMode, "test_v2", "test_info_v2", 10, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = secondary.Shutdown(context.Background())
+	})
+
+	return NewDualWrite(primary, secondary, DualWriteOptions{}), primary, secondary
+}
+
+func TestDualWriteMirrorsWrites(t *testing.T) {
+	d, _, secondary := newTestDualWrite(t)
+	ctx := context.Background()
+
+	if _, err := d.IncrementMemberScore(ctx, "a", 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.FirstOrInsertMember(ctx, "b", 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.IncrementMemberScore(ctx, "b", 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.FirstOrInsertMember(ctx, "c", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.RemoveMember(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close waits for queued writes to be mirrored
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	report := d.Report()
+	if report.Mirrored != 5 || report.Failed != 0 || report.Dropped != 0 || report.Pending != 0 {
+		t.Fatalf("got report %+v, want 5 mirrored writes", report)
+	}
+
+	consistency, err := d.Compare(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consistency.PrimaryMembers != 2 || consistency.SecondaryMembers != 2 || consistency.Sampled != 2 || len(consistency.Mismatched) != 0 {
+		t.Fatalf("got consistency %+v, want both boards with a and b", consistency)
+	}
+
+	if score := secondary.client().ZScore(ctx, "test_v2", "b").Val(); score != 7 {
+		t.Fatalf("got score %v of b on secondary, want 7", score)
+	}
+}
+
+func TestDualWriteAppliesWritesQueuedOutOfOrder(t *testing.T) {
+	d, primary, secondary := newTestDualWrite(t)
+	ctx := context.Background()
+
+	// Member is removed and inserted again by two writers, the removal being
+	// queued last
+	seed(t, primary, "a", 1)
+	if _, err := primary.RemoveMember(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.FirstOrInsertMember(ctx, "a", 4); err != nil {
+		t.Fatal(err)
+	}
+	d.syncMember("a")
+	d.syncMember("a")
+
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if score, err := secondary.client().ZScore(ctx, "test_v2", "a").Result(); err != nil || score != 4 {
+		t.Fatalf("got score %v (%v) of a on secondary, want 4", score, err)
+	}
+}
+
+func TestDualWriteConcurrentWriters(t *testing.T) {
+	d, _, _ := newTestDualWrite(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				userID := "m" + strconv.Itoa(i%5)
+				if i%7 == 0 {
+					_, _ = d.RemoveMember(ctx, userID)
+					continue
+				}
+				_, _ = d.IncrementMemberScore(ctx, userID, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	consistency, err := d.Compare(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consistency.PrimaryMembers != consistency.SecondaryMembers || len(consistency.Mismatched) != 0 {
+		t.Fatalf("got consistency %+v, want secondary matching primary", consistency)
+	}
+}
+
+func TestDualWriteMirrorsDecimalScores(t *testing.T) {
+	d, primary, secondary := newTestDualWrite(t, WithPrecision(PrecisionOneDecimal))
+	ctx := context.Background()
+
+	if err := primary.client().ZAdd(ctx, "test", &redis.Z{Score: 2.5, Member: "a"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.IncrementMemberScore(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if score := secondary.client().ZScore(ctx, "test_v2", "a").Val(); score != 3.5 {
+		t.Fatalf("got score %v of a on secondary, want 3.5", score)
+	}
+}
+
+func TestDualWriteAfterClose(t *testing.T) {
+	d, _, _ := newTestDualWrite(t)
+	ctx := context.Background()
+
+	if err := d.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := d.IncrementMemberScore(ctx, "a", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Score != 5 {
+		t.Fatalf("got score %d on primary, want 5", user.Score)
+	}
+
+	if report := d.Report(); report.Dropped != 1 || report.Mirrored != 0 {
+		t.Fatalf("got report %+v, want 1 dropped write", report)
+	}
+}
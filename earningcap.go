@@ -0,0 +1,166 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidEarningCap = errors.New("leaderboard: earning cap must not be negative and its period must be daily, weekly or monthly")
+)
+
+// earningCap limits points a member may earn within one period, see WithEarningCap.
+type earningCap struct {
+	max    int
+	period string
+	loc    *time.Location
+}
+
+// earningCapLua defines credit used by increment scripts to credit only what's
+// left of member's earning cap. Cap is given by the last args before the log
+// flag: ARGV[#ARGV - 2] is the cap ("" for none) and ARGV[#ARGV - 1] expiry
+// (ms) of per-period hash of earned points, which is the last of KEYS. Returns
+// credited points, negative points aren't capped. Must follow scoreLogLua.
+const earningCapLua = `
+local function credit(member, points)
+	local max = ARGV[#ARGV - 2]
+	if max == "" or points <= 0 then
+		return points
+	end
+
+	local earnedKey = KEYS[#KEYS]
+	local earned = tonumber(redis.call("HGET", earnedKey, member) or "0")
+	local credited = math.max(math.min(points, tonumber(max) - earned), 0)
+	if math.floor(points) == points then
+		-- Whole increments stay whole even after decimal ones
+		credited = math.floor(credited)
+	end
+	if credited > 0 then
+		redis.call("HINCRBYFLOAT", earnedKey, member, credited)
+		redis.call("PEXPIREAT", earnedKey, ARGV[#ARGV - 1])
+	end
+
+	return credited
+end
+`
+
+// WithEarningCap limits points a member may earn per period (PeriodDaily,
+// PeriodWeekly or PeriodMonthly aligned to loc, UTC when nil), e.g. 1000 points
+// a day. Every increment (IncrementMemberScore and its variants, cumulative
+// SubmitScore, RecordResult and SubmitMatchResults) credits only what's left of
+// the cap, checked atomically with the increment; points earned in the period
+// are kept in "<leaderboardName>:earned:<period>" hash which expires one period
+// after the period ends. Scores set by other writes aren't capped. Negative max
+// or unknown period make the constructor fail with ErrInvalidEarningCap.
+func WithEarningCap(max int, period string, loc *time.Location) Option {
+	return func(l *Leaderboard) {
+		if loc == nil {
+			loc = time.UTC
+		}
+
+		if _, err := PeriodOf(period, time.Time{}, loc); err != nil || max < 0 {
+			l.optionErr = ErrInvalidEarningCap
+			return
+		}
+
+		l.earningCap = &earningCap{max: max, period: period, loc: loc}
+	}
+}
+
+// earnedKey returns hash of points earned in period of t and when it expires.
+func (l *Leaderboard) earnedKey(t time.Time) (string, time.Time) {
+	// Period is validated by WithEarningCap
+	period, _ := PeriodOf(l.earningCap.period, t, l.earningCap.loc)
+
+	return l.leaderboardName + ":earned:" + period.String(), period.Add(2).Start
+}
+
+// appendEarningCap appends hash of earned points to keys and cap to args of a
+// script using earningCapLua. Log flag must be appended afterwards. Returned
+// key is empty without a cap.
+func (l *Leaderboard) appendEarningCap(keys []string, args []interface{}) ([]string, []interface{}, string) {
+	if l.earningCap == nil {
+		return keys, append(args, "", ""), ""
+	}
+
+	key, expireAt := l.earnedKey(time.Now())
+
+	return append(keys, key), append(args, l.earningCap.max, expireAt.UnixNano()/int64(time.Millisecond)), key
+}
+
+// mirrorEarned mirrors members' points earned in hash returned by appendEarningCap.
+func (l *Leaderboard) mirrorEarned(ctx context.Context, earnedKey string, userIDs ...string) {
+	if earnedKey == "" {
+		return
+	}
+
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		values, err := l.client().HMGet(ctx, earnedKey, userIDs...).Result()
+		if err != nil {
+			return err
+		}
+
+		pipe := cli.TxPipeline()
+		for i, value := range values {
+			if value != nil {
+				pipe.HSet(ctx, earnedKey, userIDs[i], value)
+			}
+		}
+		_, expireAt := l.earnedKey(time.Now())
+		pipe.ExpireAt(ctx, earnedKey, expireAt)
+		_, err = pipe.Exec(ctx)
+
+		return err
+	})
+}
+
+// IncrementMemberScoreCapped is IncrementMemberScore returning how many of
+// incrementBy points were actually credited under the earning cap, see
+// WithEarningCap. Without a cap the whole increment is credited.
+func (l *Leaderboard) IncrementMemberScoreCapped(ctx context.Context, userID string, incrementBy int) (user User, credited int, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "IncrementMemberScoreCapped", userID)
+	if err != nil {
+		return User{}, 0, err
+	}
+	defer done(&err)
+
+	return l.incrementMemberScore(ctx, userID, incrementBy)
+}
+
+// EarnedInPeriod returns points member earned in the current period of the
+// earning cap and how many are left. Without a cap both are 0.
+func (l *Leaderboard) EarnedInPeriod(ctx context.Context, userID string) (earned, left int, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "EarnedInPeriod", userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer done(&err)
+
+	if l.earningCap == nil {
+		return 0, 0, nil
+	}
+
+	earnedKey, _ := l.earnedKey(time.Now())
+	value, err := l.client().HGet(ctx, earnedKey, userID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+	if value != "" {
+		points, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		// Decimal increments may leave a fraction, the part left is rounded down
+		earned = int(math.Ceil(points))
+	}
+
+	if left = l.earningCap.max - earned; left < 0 {
+		left = 0
+	}
+
+	return earned, left, nil
+}
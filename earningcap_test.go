@@ -0,0 +1,103 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"testing"
+)
+
+func TestEarningCapAppliesToEveryIncrement(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		increment func(l *Leaderboard, userID string, points int) error
+	}{
+		{"IncrementMemberScore", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.IncrementMemberScore(ctx, userID, points)
+			return err
+		}},
+		{"IncrementMemberScoreDecimal", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.IncrementMemberScoreDecimal(ctx, userID, float64(points))
+			return err
+		}},
+		{"IncrementMemberScoreFrom", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.IncrementMemberScoreFrom(ctx, userID, points, "quests")
+			return err
+		}},
+		{"IncrementMemberScoreWithOvertaken", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.IncrementMemberScoreWithOvertaken(ctx, userID, points, 1)
+			return err
+		}},
+		{"SubmitScore", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.SubmitScore(ctx, userID, points)
+			return err
+		}},
+		{"RecordResult", []Option{WithRecords()}, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.RecordResult(ctx, userID, OutcomeWin, points)
+			return err
+		}},
+		{"SubmitMatchResults", nil, func(l *Leaderboard, userID string, points int) error {
+			_, err := l.SubmitMatchResults(ctx, []MatchResult{{UserID: userID, Points: points}})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t, append(tt.opts, WithEarningCap(10, PeriodDaily, nil))...)
+
+			for _, points := range []int{6, 6, 6} {
+				if err := tt.increment(l, "a", points); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			user, err := l.GetMember(ctx, "a", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Score != 10 {
+				t.Fatalf("got score %d, want 10", user.Score)
+			}
+
+			earned, left, err := l.EarnedInPeriod(ctx, "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if earned != 10 || left != 0 {
+				t.Fatalf("got earned %d left %d, want 10 and 0", earned, left)
+			}
+		})
+	}
+}
+
+func TestIncrementMemberScoreCappedReturnsCredited(t *testing.T) {
+	l, _ := newTestBoard(t, WithEarningCap(10, PeriodWeekly, nil))
+	ctx := context.Background()
+
+	for _, want := range []int{7, 3, 0} {
+		_, credited, err := l.IncrementMemberScoreCapped(ctx, "a", 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if credited != want {
+			t.Fatalf("got %d credited, want %d", credited, want)
+		}
+	}
+}
+
+func TestWithEarningCapRejectsInvalidPeriod(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer cli.Close()
+
+	for _, opt := range []Option{WithEarningCap(10, "hourly", nil), WithEarningCap(-1, PeriodDaily, nil)} {
+		if _, err := NewLeaderboardWithClient(cli, StagingMode, "test", "test_info", 10, opt); !errors.Is(err, ErrInvalidEarningCap) {
+			t.Fatalf("got %v, want ErrInvalidEarningCap", err)
+		}
+	}
+}
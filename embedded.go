@@ -0,0 +1,223 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strings"
+)
+
+// embeddedSeparator separates userID from embedded info in members of display
+// set. It's stripped from embedded values, so members are split at its last
+// occurrence and userIDs may contain it.
+const embeddedSeparator = "\x1f"
+
+var ErrEncryptedEmbeddedInfo = errors.New("leaderboard: encrypted member info can't be embedded")
+
+type embeddedInfo struct {
+	field  string
+	maxLen int
+}
+
+// Syncs member ARGV[1] of display set KEYS[2] with its score on board KEYS[1].
+// ARGV[2] is member's new encoded display member or "" to keep the current one,
+// encoded members are indexed by userID in KEYS[3] and kept there after
+// removal, so display survives member rejoining the board.
+var syncEmbeddedScript = redis.NewScript(`
+local old = redis.call("HGET", KEYS[3], ARGV[1])
+local member = ARGV[2]
+if member == "" then
+	member = old or (ARGV[1] .. "\31")
+end
+
+if old and old ~= member then
+	redis.call("ZREM", KEYS[2], old)
+end
+
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if score then
+	redis.call("ZADD", KEYS[2], score, member)
+else
+	redis.call("ZREM", KEYS[2], member)
+end
+redis.call("HSET", KEYS[3], ARGV[1], member)
+
+return 1
+`)
+
+// WithEmbeddedInfo keeps a display copy of the board whose members embed
+// string field of member's info (e.g. 20 char display name, truncated to maxLen
+// characters) next to userID, so GetLeaders and GetLeadersInto decode pages
+// straight from it without any info hash lookup, for simple leaderboards.
+//
+// Display copy is synced after every score write, removal and info upsert,
+// reads with Strong consistency use the board itself. Bulk operations (BulkLoad,
+// Rebuild, Reweigh, trims, ...) aren't synced, call RebuildEmbeddedInfo after them.
+//
+// Embedded values are stored in plaintext, so boards with WithEncryption can't
+// embed info, board constructors fail with ErrEncryptedEmbeddedInfo.
+func WithEmbeddedInfo(field string, maxLen int) Option {
+	return func(l *Leaderboard) {
+		if field != "" && maxLen > 0 {
+			l.embedded = &embeddedInfo{field: field, maxLen: maxLen}
+		}
+	}
+}
+
+// checkEmbeddedInfo rejects embedding info which is encrypted in info hash.
+func (l *Leaderboard) checkEmbeddedInfo() error {
+	if l.embedded != nil && l.profiles.keyring != nil {
+		return ErrEncryptedEmbeddedInfo
+	}
+
+	return nil
+}
+
+// displayKey is sorted set of members with embedded info and their scores.
+func (l *Leaderboard) displayKey() string {
+	return l.leaderboardName + ":display"
+}
+
+// displayIndexKey is hash of display members by userID.
+func (l *Leaderboard) displayIndexKey() string {
+	return l.leaderboardName + ":display:index"
+}
+
+// embeddedReads reports whether read with options o is served from display set.
+func (l *Leaderboard) embeddedReads(o readOptions) bool {
+	return l.embedded != nil && o.consistency != Strong
+}
+
+// encode returns display member of user with given info.
+func (e *embeddedInfo) encode(userID string, info []byte) string {
+	var fields map[string]interface{}
+	_ = json.Unmarshal(info, &fields)
+
+	value, _ := fields[e.field].(string)
+	value = strings.ReplaceAll(value, embeddedSeparator, "")
+	if runes := []rune(value); len(runes) > e.maxLen {
+		value = string(runes[:e.maxLen])
+	}
+
+	return userID + embeddedSeparator + value
+}
+
+// decode splits display member into userID and info holding the embedded field.
+func (e *embeddedInfo) decode(member string) (string, json.RawMessage) {
+	i := strings.LastIndex(member, embeddedSeparator)
+	if i < 0 {
+		return member, nil
+	}
+
+	userID, value := member[:i], member[i+len(embeddedSeparator):]
+	if value == "" {
+		return userID, nil
+	}
+
+	info, _ := json.Marshal(map[string]string{e.field: value})
+
+	return userID, info
+}
+
+// syncEmbedded updates member's entry in display set after a write. Errors are
+// ignored, like with other companion keys; RebuildEmbeddedInfo repairs drift.
+func (l *Leaderboard) syncEmbedded(ctx context.Context, userID, member string) {
+	if l.embedded == nil {
+		return
+	}
+
+	keys := []string{l.leaderboardName, l.displayKey(), l.displayIndexKey()}
+	_ = syncEmbeddedScript.Run(ctx, l.client(), keys, userID, member).Err()
+}
+
+// syncEmbeddedAll is syncEmbedded keeping display members of many members, in one round trip.
+func (l *Leaderboard) syncEmbeddedAll(ctx context.Context, members []MemberScore) {
+	if l.embedded == nil {
+		return
+	}
+
+	keys := []string{l.leaderboardName, l.displayKey(), l.displayIndexKey()}
+	calls := make([]scriptCall, len(members))
+	for i, m := range members {
+		calls[i] = scriptCall{keys: keys, args: []interface{}{m.UserID, ""}}
+	}
+	_, _ = runPipelined(ctx, l.client(), syncEmbeddedScript, calls)
+}
+
+// embeddedInfoUpserted re-encodes member's display entry after info upsert.
+func (l *Leaderboard) embeddedInfoUpserted(ctx context.Context, userID string, info []byte) {
+	if l.embedded == nil {
+		return
+	}
+
+	l.syncEmbedded(ctx, userID, l.embedded.encode(userID, info))
+}
+
+// appendEmbeddedUsers decodes range of display set starting at 0-based offset into buf.
+func (l *Leaderboard) appendEmbeddedUsers(buf []User, values []redis.Z, offset int) []User {
+	for i, z := range values {
+		userID, info := l.embedded.decode(z.Member.(string))
+		user := l.scoredUser(userID, z.Score, offset+i+1)
+		user.AdditionalInfo = info
+		buf = append(buf, user)
+	}
+
+	return buf
+}
+
+// RebuildEmbeddedInfo rebuilds display copy of the board from the board and
+// members' info, see WithEmbeddedInfo. Display set is replaced atomically.
+func (l *Leaderboard) RebuildEmbeddedInfo(ctx context.Context) (err error) {
+	done, err := l.trackOp("RebuildEmbeddedInfo")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if l.embedded == nil {
+		return nil
+	}
+
+	tmpKey, err := temporaryKey(l.displayKey())
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	err = scanSortedSet(ctx, l.client(), l.leaderboardName, rebuildBatchSize, func(members []redis.Z) error {
+		users := make([]User, len(members))
+		for i, z := range members {
+			users[i] = User{UserID: z.Member.(string)}
+		}
+
+		if err := l.profiles.load(ctx, l.client(), users); err != nil {
+			return err
+		}
+
+		display := make([]*redis.Z, len(members))
+		index := make([]interface{}, 0, 2*len(members))
+		for i, z := range members {
+			member := l.embedded.encode(users[i].UserID, users[i].AdditionalInfo)
+			display[i] = &redis.Z{Score: z.Score, Member: member}
+			index = append(index, users[i].UserID, member)
+		}
+
+		pipe := l.client().Pipeline()
+		pipe.ZAdd(ctx, tmpKey, display...)
+		pipe.Expire(ctx, tmpKey, rebuildKeyTTL)
+		pipe.HSet(ctx, l.displayIndexKey(), index...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		total += len(members)
+
+		return nil
+	})
+	if err != nil {
+		_ = l.client().Del(ctx, tmpKey).Err()
+		return err
+	}
+
+	return swapIn(ctx, l.client(), tmpKey, l.displayKey(), total, nil)
+}
@@ -0,0 +1,74 @@
+package go_redis_leaderboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"testing"
+)
+
+func TestEmbeddedInfoServesPages(t *testing.T) {
+	l, _ := newTestBoard(t, WithEmbeddedInfo("name", 5))
+	ctx := context.Background()
+
+	if err := l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"Alexander","level":3}`)); err != nil {
+		t.Fatal(err)
+	}
+	for _, userID := range []string{"a", "b", "b"} {
+		if _, err := l.IncrementMemberScore(ctx, userID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	users, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"b", "a"}) || users[0].AdditionalInfo != nil || string(users[1].AdditionalInfo) != `{"name":"Alexa"}` {
+		t.Fatalf("got %+v, want b without info and a with truncated name", users)
+	}
+
+	// Renamed member's old display entry is replaced, removed member's is dropped
+	if err := l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"Al"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.RemoveMember(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	display := l.client().ZRange(ctx, l.displayKey(), 0, -1).Val()
+	if !equalStrings(display, []string{"a" + embeddedSeparator + "Al"}) {
+		t.Fatalf("got display members %q, want only renamed a", display)
+	}
+
+	strong, err := l.GetLeaders(ctx, 1, WithConsistency(Strong))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strong) != 1 || string(strong[0].AdditionalInfo) != `{"name":"Al"}` {
+		t.Fatalf("got %+v, want a with full info", strong)
+	}
+}
+
+func TestEmbeddedInfoRejectsEncryption(t *testing.T) {
+	keyring, err := NewKeyring("k1", bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer cli.Close()
+
+	// Options are rejected in either order
+	for _, opts := range [][]Option{
+		{WithEncryption(keyring), WithEmbeddedInfo("name", 20)},
+		{WithEmbeddedInfo("name", 20), WithEncryption(keyring)},
+	} {
+		if _, err := NewLeaderboardWithClient(cli, StagingMode, "test", "test_info", 10, opts...); !errors.Is(err, ErrEncryptedEmbeddedInfo) {
+			t.Fatalf("got error %v, want %v", err, ErrEncryptedEmbeddedInfo)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Replaces hash field only if it still holds the expected value, so
+// re-encryption never overwrites info upserted in the meantime.
+var hashCompareAndSetScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// Prefix of every encrypted value stored in the user info hash:
+// "lbenc:<keyID>:<base64(nonce|ciphertext)>"
+const encryptedInfoPrefix = "lbenc:"
+
+var (
+	ErrInvalidKeyID     = errors.New("leaderboard: key id must be non-empty and must not contain ':'")
+	ErrUnknownKeyID     = errors.New("leaderboard: value is encrypted with unknown key")
+	ErrMalformedCipher  = errors.New("leaderboard: malformed encrypted value")
+	ErrKeyringNotLoaded = errors.New("leaderboard: encryption keyring is not configured")
+)
+
+// Keyring holds AES-GCM keys used to encrypt AdditionalInfo at rest.
+//
+// New values are always sealed with the primary key, older keys are kept
+// only for decryption so keys can be rotated without downtime.
+type Keyring struct {
+	mu      sync.RWMutex
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates keyring with primaryKey as the key used for encryption.
+// Key must be 16, 24 or 32 bytes long (AES-128, AES-192 or AES-256).
+func NewKeyring(primaryID string, primaryKey []byte) (*Keyring, error) {
+	k := &Keyring{keys: map[string]cipher.AEAD{}}
+	if err := k.Rotate(primaryID, primaryKey); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// AddKey adds decrypt-only key to keyring.
+func (k *Keyring) AddKey(id string, key []byte) error {
+	aead, err := newAEAD(id, key)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys[id] = aead
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Rotate adds key to keyring and makes it the primary one.
+func (k *Keyring) Rotate(id string, key []byte) error {
+	aead, err := newAEAD(id, key)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys[id] = aead
+	k.primary = id
+	k.mu.Unlock()
+
+	return nil
+}
+
+// PrimaryKeyID returns ID of key used for encryption.
+func (k *Keyring) PrimaryKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.primary
+}
+
+func (k *Keyring) seal(plaintext string) (string, error) {
+	k.mu.RLock()
+	id, aead := k.primary, k.keys[k.primary]
+	k.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(id))
+
+	return encryptedInfoPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts value stored by seal. Values without encryption prefix are
+// returned untouched so plaintext data written before encryption was enabled
+// stays readable.
+func (k *Keyring) open(value string) (string, error) {
+	id, payload, ok := splitEncryptedValue(value)
+	if !ok {
+		return value, nil
+	}
+
+	k.mu.RLock()
+	aead, found := k.keys[id]
+	k.mu.RUnlock()
+	if !found {
+		return "", ErrUnknownKeyID
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedCipher
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// needsReencryption reports whether value isn't sealed with the primary key.
+func (k *Keyring) needsReencryption(value string) bool {
+	id, _, ok := splitEncryptedValue(value)
+
+	return !ok || id != k.PrimaryKeyID()
+}
+
+func splitEncryptedValue(value string) (keyID, payload string, ok bool) {
+	if !strings.HasPrefix(value, encryptedInfoPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedInfoPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+func newAEAD(id string, key []byte) (cipher.AEAD, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, ErrInvalidKeyID
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// WithEncryption enables AES-GCM encryption of AdditionalInfo stored in user info hash.
+// On boards sharing a ProfileStore only info written through this board is encrypted.
+func WithEncryption(keyring *Keyring) Option {
+	return func(l *Leaderboard) {
+		l.profiles = l.profiles.withKeyring(keyring)
+	}
+}
+
+// ReencryptMemberInfo walks through user info hash (and localized info hashes)
+// and seals every value that is stored in plaintext or with a non-primary key
+// using the current primary key. It should be called after Keyring.Rotate,
+// once all instances know about the new key.
+func (l *Leaderboard) ReencryptMemberInfo(ctx context.Context) (updated int, err error) {
+	done, err := l.trackOp("ReencryptMemberInfo")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	return l.profiles.reencrypt(ctx, func(hash, userID, value string) {
+		l.mirrorHashField(ctx, hash, userID, value)
+	})
+}
+
+func (p *ProfileStore) reencrypt(ctx context.Context, onUpdate func(hash, userID, value string)) (updated int, err error) {
+	if p.keyring == nil {
+		return 0, ErrKeyringNotLoaded
+	}
+
+	locales, err := p.Locales(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	hashes := []string{p.hashName}
+	for _, locale := range locales {
+		hashes = append(hashes, p.localeHash(locale))
+	}
+
+	for _, hash := range hashes {
+		n, err := p.reencryptHash(ctx, hash, onUpdate)
+		updated += n
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (p *ProfileStore) reencryptHash(ctx context.Context, hash string, onUpdate func(hash, userID, value string)) (updated int, err error) {
+	var cursor uint64
+	for {
+		var fields []string
+		fields, cursor, err = p.client().HScan(ctx, hash, cursor, "", 100).Result()
+		if err != nil {
+			return updated, err
+		}
+
+		for i := 0; i+1 < len(fields); i += 2 {
+			userID, value := fields[i], fields[i+1]
+			if !p.keyring.needsReencryption(value) {
+				continue
+			}
+
+			plaintext, err := p.keyring.open(value)
+			if err != nil {
+				return updated, err
+			}
+
+			sealed, err := p.keyring.seal(plaintext)
+			if err != nil {
+				return updated, err
+			}
+
+			swapped, err := hashCompareAndSetScript.Run(ctx, p.client(), []string{hash}, userID, value, sealed).Int()
+			if err != nil {
+				return updated, err
+			}
+
+			if swapped == 1 && onUpdate != nil {
+				onUpdate(hash, userID, sealed)
+			}
+
+			updated += swapped
+		}
+
+		if cursor == 0 {
+			return updated, nil
+		}
+	}
+}
+
+func decryptMemberInfo(keyring *Keyring, value string) (string, error) {
+	if keyring == nil {
+		return value, nil
+	}
+
+	return keyring.open(value)
+}
+
+func encryptMemberInfo(keyring *Keyring, value string) (string, error) {
+	if keyring == nil {
+		return value, nil
+	}
+
+	return keyring.seal(value)
+}
@@ -0,0 +1,67 @@
+package go_redis_leaderboard
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReencryptMemberInfoSealsWithPrimaryKey(t *testing.T) {
+	ctx := context.Background()
+
+	keyring, err := NewKeyring("k1", bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := newTestBoard(t)
+	if err := plain.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"plain"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewLeaderboardWithClient(plain.client(), StagingMode, "test", "test_info", 10, WithEncryption(keyring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Shutdown(ctx)
+	})
+	if err := l.UpsertMemberInfo(ctx, "b", AdditionalUserInfo(`{"name":"sealed"}`)); err != nil {
+		t.Fatal(err)
+	}
+	seed(t, l, "a", 1, "b", 2)
+
+	if err := keyring.Rotate("k2", bytes.Repeat([]byte{2}, 32)); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := l.ReencryptMemberInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Fatalf("got %d updated, want 2", updated)
+	}
+
+	want := map[string]string{"a": `{"name":"plain"}`, "b": `{"name":"sealed"}`}
+	for userID, info := range want {
+		stored := l.client().HGet(ctx, "test_info", userID).Val()
+		if !strings.HasPrefix(stored, encryptedInfoPrefix+"k2:") {
+			t.Fatalf("got stored info %q of %s, want it sealed with k2", stored, userID)
+		}
+
+		user, err := l.GetMember(ctx, userID, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(user.AdditionalInfo) != info {
+			t.Fatalf("got info %s of %s, want %s", user.AdditionalInfo, userID, info)
+		}
+	}
+
+	// Nothing is left to re-encrypt
+	if updated, err := l.ReencryptMemberInfo(ctx); err != nil || updated != 0 {
+		t.Fatalf("got %d updated and %v, want 0 and nil", updated, err)
+	}
+}
@@ -0,0 +1,43 @@
+package go_redis_leaderboard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LeaderboardError is returned by board operations, wrapping underlying error
+// with operation, board and user it happened for. Use errors.Is and errors.As
+// to inspect the underlying error.
+type LeaderboardError struct {
+	Op     string
+	Board  string
+	UserID string
+	Err    error
+}
+
+func (e *LeaderboardError) Error() string {
+	if e.UserID == "" {
+		return fmt.Sprintf("%s on %q: %v", e.Op, e.Board, e.Err)
+	}
+
+	return fmt.Sprintf("%s on %q for user %q: %v", e.Op, e.Board, e.UserID, e.Err)
+}
+
+func (e *LeaderboardError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps *err into LeaderboardError unless it's nil or was already
+// wrapped by a nested operation.
+func (l *Leaderboard) wrapError(op, userID string, err *error) {
+	if *err == nil {
+		return
+	}
+
+	var wrapped *LeaderboardError
+	if errors.As(*err, &wrapped) {
+		return
+	}
+
+	*err = &LeaderboardError{Op: op, Board: l.leaderboardName, UserID: userID, Err: *err}
+}
@@ -0,0 +1,178 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+const (
+	// EventRankChanged is emitted when member's score (and possibly rank) changes
+	EventRankChanged = "rank_changed"
+	// EventMemberRemoved is emitted when member is removed from the board
+	EventMemberRemoved = "member_removed"
+
+	DefaultEventStreamMaxLen = 100000
+)
+
+// Event describes change of the board. Events are stored in "<leaderboardName>:events"
+// stream when enabled with WithEvents.
+//
+// OldRank is read right before the change is applied, so under concurrent writes
+// to the same member it's best-effort.
+type Event struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	UserID   string    `json:"user_id"`
+	OldRank  int       `json:"old_rank"`
+	NewRank  int       `json:"new_rank"`
+	OldScore int       `json:"old_score"`
+	NewScore int       `json:"new_score"`
+	At       time.Time `json:"at"`
+	// Reason is set for EventAnomaly only
+	Reason string `json:"reason,omitempty"`
+	// Source is set for writes labelled with source, see IncrementMemberScoreFrom
+	Source string `json:"source,omitempty"`
+}
+
+// WithEvents enables event stream of the board, trimmed to approximately maxLen entries.
+func WithEvents(maxLen int64) Option {
+	return func(l *Leaderboard) {
+		if maxLen <= 0 {
+			maxLen = DefaultEventStreamMaxLen
+		}
+
+		l.eventsMaxLen = maxLen
+	}
+}
+
+func (l *Leaderboard) eventsStream() string {
+	return l.leaderboardName + ":events"
+}
+
+func (l *Leaderboard) eventsEnabled() bool {
+	return l.eventsMaxLen > 0
+}
+
+// Events returns up to count events with IDs between start and end (inclusive),
+// use "-" and "+" for the oldest and the newest event.
+func (l *Leaderboard) Events(ctx context.Context, start, end string, count int64) (_ []Event, err error) {
+	defer l.wrapError("Events", "", &err)
+
+	messages, err := l.client().XRangeN(ctx, l.eventsStream(), start, end, count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(messages))
+	for _, msg := range messages {
+		events = append(events, eventFromMessage(msg))
+	}
+
+	return events, nil
+}
+
+// memberBefore returns member's state before a write, so it can be reported in
+// events. Nothing is fetched when events are disabled.
+func (l *Leaderboard) memberBefore(ctx context.Context, userID string) User {
+	if !l.eventsEnabled() {
+		return User{}
+	}
+
+	user, err := l.getMember(ctx, userID, false, readOptions{consistency: Strong})
+	if err == nil {
+		user, err = l.markGhost(ctx, l.client(), user)
+	}
+	if err != nil {
+		return User{UserID: userID, Rank: UnrankedMember}
+	}
+
+	return user
+}
+
+// emitScoreChange records rank_changed event. Failures are ignored: the write
+// itself already succeeded and events must never fail it.
+func (l *Leaderboard) emitScoreChange(ctx context.Context, before, after User) {
+	l.emit(ctx, Event{
+		Type:     EventRankChanged,
+		UserID:   after.UserID,
+		OldRank:  before.Rank,
+		NewRank:  after.Rank,
+		OldScore: before.Score,
+		NewScore: after.Score,
+	})
+}
+
+func (l *Leaderboard) emit(ctx context.Context, event Event) {
+	l.emitAll(ctx, []Event{event})
+}
+
+// emitAll records events in one pipelined round trip.
+func (l *Leaderboard) emitAll(ctx context.Context, events []Event) {
+	if !l.eventsEnabled() || len(events) == 0 {
+		return
+	}
+
+	now := time.Now()
+	pipe := l.client().Pipeline()
+	for _, event := range events {
+		if event.At.IsZero() {
+			event.At = now
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream:       l.eventsStream(),
+			MaxLenApprox: l.eventsMaxLen,
+			Values: map[string]interface{}{
+				"type":      event.Type,
+				"user_id":   event.UserID,
+				"old_rank":  event.OldRank,
+				"new_rank":  event.NewRank,
+				"old_score": event.OldScore,
+				"new_score": event.NewScore,
+				"at":        event.At.UnixNano() / int64(time.Millisecond),
+				"reason":    event.Reason,
+				"source":    event.Source,
+			},
+		})
+	}
+	_, _ = pipe.Exec(ctx)
+}
+
+func eventFromMessage(msg redis.XMessage) Event {
+	event := Event{ID: msg.ID}
+
+	for k, v := range msg.Values {
+		value, _ := v.(string)
+		number, _ := strconv.Atoi(value)
+
+		switch k {
+		case "type":
+			event.Type = value
+		case "user_id":
+			event.UserID = value
+		case "old_rank":
+			event.OldRank = number
+		case "new_rank":
+			event.NewRank = number
+		case "old_score":
+			event.OldScore = number
+		case "new_score":
+			event.NewScore = number
+		case "reason":
+			event.Reason = value
+		case "source":
+			event.Source = value
+		case "at":
+			event.At = time.Unix(0, int64(number)*int64(time.Millisecond))
+		}
+	}
+
+	return event
+}
+
+// streamID returns smallest stream ID with given time, usable as XRANGE bound.
+func streamID(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
@@ -0,0 +1,235 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// Operations recorded in score log.
+const (
+	ScoreOpIncrement      = "incr"
+	ScoreOpSet            = "set"
+	ScoreOpBest           = "best"
+	ScoreOpInsert         = "insert"
+	ScoreOpRemove         = "remove"
+	ScoreOpTrimBelowScore = "trim_score"
+	ScoreOpTrimBelowRank  = "trim_rank"
+
+	replayBatchSize = 500
+)
+
+var (
+	ErrEventSourcingEnabled  = errors.New("leaderboard: operation isn't supported for event sourced boards")
+	ErrEventSourcingDisabled = errors.New("leaderboard: event sourcing isn't enabled for the board")
+)
+
+// scoreLogLua defines logOp used by write scripts to append applied operation
+// to score log atomically with the write. Last ARGV tells whether log is enabled.
+const scoreLogLua = `
+local function logOp(logKey, op, member, value)
+	if ARGV[#ARGV] == "1" then
+		redis.call("XADD", logKey, "*", "op", op, "user_id", member, "value", value)
+	end
+end
+`
+
+// Applies logged operations (ARGV triplets of op, member and value) to KEYS[1]
+// and sets its TTL to last ARGV.
+var replayScoreLogScript = redis.NewScript(`
+for i = 1, #ARGV - 1, 3 do
+	local op, member, value = ARGV[i], ARGV[i + 1], ARGV[i + 2]
+
+	if op == "incr" then
+		redis.call("ZINCRBY", KEYS[1], value, member)
+	elseif op == "set" then
+		redis.call("ZADD", KEYS[1], value, member)
+	elseif op == "best" then
+		local current = redis.call("ZSCORE", KEYS[1], member)
+		if current == false or tonumber(value) > tonumber(current) then
+			redis.call("ZADD", KEYS[1], value, member)
+		end
+	elseif op == "insert" then
+		redis.call("ZADD", KEYS[1], "NX", value, member)
+	elseif op == "remove" then
+		redis.call("ZREM", KEYS[1], member)
+	elseif op == "trim_score" then
+		redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. value)
+	elseif op == "trim_rank" then
+		redis.call("ZREMRANGEBYRANK", KEYS[1], 0, -tonumber(value) - 1)
+	end
+end
+
+redis.call("EXPIRE", KEYS[1], ARGV[#ARGV])
+return 1
+`)
+
+// Replaces KEYS[2] with projection KEYS[1] if nothing was appended to log KEYS[3]
+// after ARGV[1], bumping version in metadata KEYS[4]. Returns 0 if projection
+// has to catch up first.
+var swapProjectionScript = redis.NewScript(versionLua + `
+local last = redis.call("XREVRANGE", KEYS[3], "+", "-", "COUNT", 1)
+local lastID = "0-0"
+if #last > 0 then
+	lastID = last[1][1]
+end
+
+if lastID ~= ARGV[1] then
+	return 0
+end
+
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("RENAME", KEYS[1], KEYS[2])
+	redis.call("PERSIST", KEYS[2])
+else
+	redis.call("DEL", KEYS[2])
+end
+bumpVersion(KEYS[4])
+
+return 1
+`)
+
+// ScoreLogEntry is operation applied to the board, see WithEventSourcing.
+type ScoreLogEntry struct {
+	ID     string `json:"id"`
+	Op     string `json:"op"`
+	UserID string `json:"user_id"`
+	// Value is increment, score, cutoff score or rank, depending on Op
+	Value float64 `json:"value"`
+}
+
+// WithEventSourcing makes append-only score log ("<leaderboardName>:scorelog"
+// stream) the source of truth of the board. Every score change is appended to
+// the log atomically with the write and the sorted set becomes a projection
+// which can be rebuilt from the log at any time with RebuildFromEvents, e.g.
+// after a scoring bug was fixed. Log is never trimmed.
+//
+// Rebuild, BulkLoad and MigrateTo bypass the log and return ErrEventSourcingEnabled.
+func WithEventSourcing() Option {
+	return func(l *Leaderboard) {
+		l.eventSourced = true
+	}
+}
+
+func (l *Leaderboard) scoreLogKey() string {
+	return l.leaderboardName + ":scorelog"
+}
+
+// scoreLogFlag is last argument of write scripts telling whether to log operation.
+func (l *Leaderboard) scoreLogFlag() string {
+	if l.eventSourced {
+		return "1"
+	}
+
+	return "0"
+}
+
+// logOps appends operations to score log within pipe (MULTI/EXEC) of the write.
+func (l *Leaderboard) logOps(ctx context.Context, pipe redis.Pipeliner, op string, members []MemberScore) {
+	if !l.eventSourced {
+		return
+	}
+
+	for _, m := range members {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: l.scoreLogKey(),
+			Values: map[string]interface{}{"op": op, "user_id": m.UserID, "value": m.Score},
+		})
+	}
+}
+
+// ScoreLog returns up to count entries of score log with IDs between start
+// and end (inclusive), use "-" and "+" for the oldest and the newest entry.
+func (l *Leaderboard) ScoreLog(ctx context.Context, start, end string, count int64) (_ []ScoreLogEntry, err error) {
+	defer l.wrapError("ScoreLog", "", &err)
+
+	messages, err := l.client().XRangeN(ctx, l.scoreLogKey(), start, end, count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ScoreLogEntry, 0, len(messages))
+	for _, msg := range messages {
+		entries = append(entries, scoreLogEntryFromMessage(msg))
+	}
+
+	return entries, nil
+}
+
+// RebuildFromEvents rebuilds the board by replaying whole score log into a
+// temporary key which then replaces the board. Writes done meanwhile are
+// replayed as well before the swap, so none of them is lost.
+func (l *Leaderboard) RebuildFromEvents(ctx context.Context) (err error) {
+	done, err := l.trackOp("RebuildFromEvents")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !l.eventSourced {
+		return ErrEventSourcingDisabled
+	}
+
+	tmpKey, err := temporaryKey(l.leaderboardName + ":projection")
+	if err != nil {
+		return err
+	}
+
+	lastID := "0-0"
+	for {
+		if lastID, err = l.replayScoreLog(ctx, tmpKey, lastID); err != nil {
+			_ = l.client().Del(ctx, tmpKey).Err()
+			return err
+		}
+
+		swapped, err := swapProjectionScript.Run(ctx, l.client(), []string{tmpKey, l.leaderboardName, l.scoreLogKey(), l.metaKey()}, lastID).Int()
+		if err != nil {
+			_ = l.client().Del(ctx, tmpKey).Err()
+			return err
+		}
+
+		if swapped == 1 {
+			l.recordWrite(ctx)
+			return nil
+		}
+	}
+}
+
+// replayScoreLog applies log entries after ID after to key and returns ID of the last applied entry.
+func (l *Leaderboard) replayScoreLog(ctx context.Context, key, after string) (string, error) {
+	for {
+		messages, err := l.client().XRangeN(ctx, l.scoreLogKey(), nextStreamID(after), "+", replayBatchSize).Result()
+		if err != nil {
+			return after, err
+		}
+
+		if len(messages) == 0 {
+			return after, nil
+		}
+
+		args := make([]interface{}, 0, 3*len(messages)+1)
+		for _, msg := range messages {
+			args = append(args, msg.Values["op"], msg.Values["user_id"], msg.Values["value"])
+		}
+		args = append(args, int(rebuildKeyTTL.Seconds()))
+
+		if err := replayScoreLogScript.Run(ctx, l.client(), []string{key}, args...).Err(); err != nil {
+			return after, err
+		}
+
+		after = messages[len(messages)-1].ID
+	}
+}
+
+func scoreLogEntryFromMessage(msg redis.XMessage) ScoreLogEntry {
+	entry := ScoreLogEntry{ID: msg.ID}
+	entry.Op, _ = msg.Values["op"].(string)
+	entry.UserID, _ = msg.Values["user_id"].(string)
+
+	if value, ok := msg.Values["value"].(string); ok {
+		entry.Value, _ = strconv.ParseFloat(value, 64)
+	}
+
+	return entry
+}
@@ -0,0 +1,71 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"reflect"
+	"testing"
+)
+
+func TestRebuildFromEventsRestoresProjection(t *testing.T) {
+	l, _ := newTestBoard(t, WithEventSourcing())
+	ctx := context.Background()
+
+	writes := []func() error{
+		func() error {
+			_, err := l.IncrementMemberScore(ctx, "a", 5)
+			return err
+		},
+		func() error {
+			_, err := l.FirstOrInsertMember(ctx, "b", 3)
+			return err
+		},
+		func() error {
+			return l.UpsertMembers(ctx, []MemberScore{{UserID: "c", Score: 1}, {UserID: "d", Score: 8}}, 0)
+		},
+		func() error {
+			return l.UpdateScoreCAS(ctx, "b", 3, 4)
+		},
+		func() error {
+			_, err := l.RemoveMember(ctx, "d")
+			return err
+		},
+		func() error {
+			_, err := l.TrimBelowScore(ctx, 2)
+			return err
+		},
+		func() error {
+			_, err := l.IncrementMemberScore(ctx, "b", 2)
+			return err
+		},
+	}
+	for _, write := range writes {
+		if err := write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := l.client().ZRangeWithScores(ctx, l.leaderboardName, 0, -1).Val()
+	if len(want) != 2 {
+		t.Fatalf("got %v, want a and b left", want)
+	}
+
+	// Projection drifts, e.g. by a write bypassing the log
+	l.client().ZAdd(ctx, l.leaderboardName, &redis.Z{Score: 100, Member: "x"})
+
+	if err := l.RebuildFromEvents(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := l.client().ZRangeWithScores(ctx, l.leaderboardName, 0, -1).Val(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	entries, err := l.ScoreLog(ctx, "-", "+", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 8 {
+		t.Fatalf("got %d log entries, want 8", len(entries))
+	}
+}
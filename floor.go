@@ -0,0 +1,115 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+var (
+	ErrDecrementByMustBePositiveInteger = errors.New("leaderboard: decrementBy must be positive integer")
+)
+
+// Decrements score of existing member ARGV[1] by ARGV[2], clamped at zero when
+// ARGV[3] is "1", and returns {newScore, clamped}. Locks and submission window
+// are honoured like by incrementScoreScript, nil is returned for missing member.
+var decrementScoreScript = redis.NewScript(scoreLogLua + submissionWindowLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not score then
+	return false
+end
+
+local by, clamped = tonumber(ARGV[2]), 0
+if ARGV[3] == "1" and tonumber(score) - by < 0 then
+	clamped = by - math.max(tonumber(score), 0)
+	by = by - clamped
+end
+
+local newScore = redis.call("ZINCRBY", KEYS[1], -by, ARGV[1])
+logOp(KEYS[3], "incr", ARGV[1], -by)
+bumpVersion(KEYS[4])
+
+return {newScore, clamped}
+`)
+
+// ScoreDecrement is result of DecrementMemberScore.
+type ScoreDecrement struct {
+	User
+	// Clamped is part of the decrement which wasn't applied because of zero floor
+	Clamped int `json:"clamped"`
+}
+
+// WithZeroFloor makes DecrementMemberScore clamp decrements so scores never go
+// below zero, for "spendable points" boards. Clamping is done in the same
+// script as the write.
+func WithZeroFloor() Option {
+	return func(l *Leaderboard) {
+		l.zeroFloor = true
+	}
+}
+
+// DecrementMemberScore decreases score of member already on the board by
+// decrementBy, e.g. when points are spent. With WithZeroFloor the decrement is
+// clamped at zero and the clamped amount is reported. ErrMemberNotFound is
+// returned for members not on the board.
+func (l *Leaderboard) DecrementMemberScore(ctx context.Context, userID string, decrementBy int) (result ScoreDecrement, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "DecrementMemberScore", userID)
+	if err != nil {
+		return ScoreDecrement{}, err
+	}
+	defer done(&err)
+
+	if decrementBy < 0 {
+		return ScoreDecrement{}, ErrDecrementByMustBePositiveInteger
+	}
+
+	before := l.memberBefore(ctx, userID)
+
+	floor := "0"
+	if l.zeroFloor {
+		floor = "1"
+	}
+
+	keys := []string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey()}
+	reply, err := decrementScoreScript.Run(ctx, l.client(), keys, userID, decrementBy, floor, l.scoreLogFlag()).Result()
+	if errors.Is(err, redis.Nil) {
+		return ScoreDecrement{}, ErrMemberNotFound
+	}
+	if err != nil {
+		return ScoreDecrement{}, scriptError(err)
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return ScoreDecrement{}, errUnexpectedScriptReply
+	}
+
+	scoreText, _ := res[0].(string)
+	floatScore, err := strconv.ParseFloat(scoreText, 64)
+	if err != nil {
+		return ScoreDecrement{}, err
+	}
+	clamped, _ := res[1].(int64)
+
+	newScore := l.precision.whole(floatScore)
+	l.scoreWritten(ctx, userID, floatScore)
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return ScoreDecrement{}, err
+	}
+
+	result = ScoreDecrement{User: User{UserID: userID, Score: newScore, Rank: rank}, Clamped: int(clamped)}
+	l.emitScoreChange(ctx, before, result.User)
+
+	return result, nil
+}
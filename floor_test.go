@@ -0,0 +1,43 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDecrementMemberScore(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		userID      string
+		by          int
+		want        error
+		wantScore   int
+		wantClamped int
+	}{
+		{"within score", nil, "a", 3, nil, 2, 0},
+		{"below zero", nil, "a", 8, nil, -3, 0},
+		{"clamped at zero", []Option{WithZeroFloor()}, "a", 8, nil, 0, 3},
+		{"exactly zero", []Option{WithZeroFloor()}, "a", 5, nil, 0, 0},
+		{"missing member", nil, "b", 1, ErrMemberNotFound, 0, 0},
+		{"negative", nil, "a", -1, ErrDecrementByMustBePositiveInteger, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t, tt.opts...)
+			seed(t, l, "a", 5)
+
+			result, err := l.DecrementMemberScore(ctx, tt.userID, tt.by)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if result.Score != tt.wantScore || result.Clamped != tt.wantClamped {
+				t.Fatalf("got score %d clamped %d, want %d and %d", result.Score, result.Clamped, tt.wantScore, tt.wantClamped)
+			}
+		})
+	}
+}
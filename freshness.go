@@ -0,0 +1,237 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// FreshnessRemove removes stale members from the board
+	FreshnessRemove = "remove"
+	// FreshnessZero keeps stale members on the board with score 0
+	FreshnessZero = "zero"
+
+	freshnessSweepBatch = 500
+)
+
+// FreshnessOptions configures WithScoreFreshness.
+type FreshnessOptions struct {
+	// TTL is how long score stays fresh after it was last written
+	TTL time.Duration
+	// Action is FreshnessRemove (default) or FreshnessZero
+	Action string
+	// SweepInterval makes stale scores swept in background until board is
+	// shut down, 0 leaves sweeping to explicit SweepStale calls
+	SweepInterval time.Duration
+	// OnError is called with errors of background sweeps
+	OnError func(error)
+}
+
+type freshness struct {
+	opts FreshnessOptions
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Sweeps up to ARGV[2] members of KEYS[1] not refreshed since ARGV[1] (unix ms)
+// according to KEYS[2] and returns them as {member, oldScore, oldRank, newRank}
+// quadruples (0-based ranks, -1 for removed members). ARGV[3] is action.
+// Version in metadata KEYS[4] is bumped when anything was swept.
+var sweepStaleScript = redis.NewScript(scoreLogLua + versionLua + `
+local stale = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+local swept = {}
+
+for _, member in ipairs(stale) do
+	local score = redis.call("ZSCORE", KEYS[1], member)
+	if score then
+		local rank = redis.call("ZREVRANK", KEYS[1], member)
+		local newRank = -1
+		if ARGV[3] == "zero" then
+			redis.call("ZADD", KEYS[1], 0, member)
+			newRank = redis.call("ZREVRANK", KEYS[1], member)
+			logOp(KEYS[3], "set", member, 0)
+		else
+			redis.call("ZREM", KEYS[1], member)
+			logOp(KEYS[3], "remove", member, "")
+		end
+
+		swept[#swept + 1] = member
+		swept[#swept + 1] = score
+		swept[#swept + 1] = rank
+		swept[#swept + 1] = newRank
+	end
+end
+
+if #stale > 0 then
+	redis.call("ZREM", KEYS[2], unpack(stale))
+end
+if #swept > 0 then
+	bumpVersion(KEYS[4])
+end
+
+return {#stale, swept}
+`)
+
+// WithScoreFreshness makes scores not refreshed within opts.TTL removed (or
+// zeroed) by SweepStale, for "active this hour" boards where stale entries are
+// misleading. Time of the last write of every member is kept in
+// "<leaderboardName>:fresh" sorted set, costing one extra write per score update.
+func WithScoreFreshness(opts FreshnessOptions) Option {
+	return func(l *Leaderboard) {
+		if opts.Action != FreshnessZero {
+			opts.Action = FreshnessRemove
+		}
+
+		l.freshness = &freshness{opts: opts, stop: make(chan struct{})}
+	}
+}
+
+func (l *Leaderboard) freshnessKey() string {
+	return l.leaderboardName + ":fresh"
+}
+
+// startSweeper starts background sweeping when configured.
+func (l *Leaderboard) startSweeper() {
+	f := l.freshness
+	if f == nil || f.opts.SweepInterval <= 0 {
+		return
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		ticker := time.NewTicker(f.opts.SweepInterval)
+		defer ticker.Stop()
+
+		ctx := context.Background()
+
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+				if _, err := l.SweepStale(ctx); err != nil && f.opts.OnError != nil {
+					f.opts.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+// stopSweeper stops background sweeping, waiting for running sweep to finish.
+func (l *Leaderboard) stopSweeper() {
+	if f := l.freshness; f != nil {
+		f.stopOnce.Do(func() {
+			close(f.stop)
+		})
+		f.wg.Wait()
+	}
+}
+
+// touch records that member's score was just written. Errors are ignored,
+// freshness tracking must never fail the write.
+func (l *Leaderboard) touch(ctx context.Context, userID string) {
+	if l.freshness == nil {
+		return
+	}
+
+	_ = l.client().ZAdd(ctx, l.freshnessKey(), &redis.Z{Score: float64(time.Now().UnixNano() / int64(time.Millisecond)), Member: userID}).Err()
+}
+
+// touchAll is touch for many members in one ZADD.
+func (l *Leaderboard) touchAll(ctx context.Context, members []MemberScore) {
+	if l.freshness == nil {
+		return
+	}
+
+	now := float64(time.Now().UnixNano() / int64(time.Millisecond))
+	z := make([]*redis.Z, len(members))
+	for i, m := range members {
+		z[i] = &redis.Z{Score: now, Member: m.UserID}
+	}
+
+	_ = l.client().ZAdd(ctx, l.freshnessKey(), z...).Err()
+}
+
+func (l *Leaderboard) untouch(ctx context.Context, userID string) {
+	if l.freshness == nil {
+		return
+	}
+
+	_ = l.client().ZRem(ctx, l.freshnessKey(), userID).Err()
+}
+
+// SweepStale removes (or zeroes) scores not refreshed within freshness TTL and
+// returns affected members with scores and ranks they had before the sweep.
+// Events are emitted as for any other write.
+func (l *Leaderboard) SweepStale(ctx context.Context) (swept []User, err error) {
+	ctx, done, err := l.startOp(ctx, "SweepStale")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	f := l.freshness
+	if f == nil {
+		return nil, nil
+	}
+
+	cutoff := time.Now().Add(-f.opts.TTL).UnixNano() / int64(time.Millisecond)
+	keys := []string{l.leaderboardName, l.freshnessKey(), l.scoreLogKey(), l.metaKey()}
+
+	for {
+		reply, err := sweepStaleScript.Run(ctx, l.client(), keys, cutoff, freshnessSweepBatch, f.opts.Action, l.scoreLogFlag()).Result()
+		if err != nil {
+			return swept, err
+		}
+
+		res, ok := reply.([]interface{})
+		if !ok || len(res) != 2 {
+			return swept, errUnexpectedScriptReply
+		}
+
+		scanned, _ := res[0].(int64)
+		values, _ := res[1].([]interface{})
+
+		batch := make([]User, 0, len(values)/4)
+		events := make([]Event, 0, len(values)/4)
+		for i := 0; i+3 < len(values); i += 4 {
+			score, err := strconv.ParseFloat(values[i+1].(string), 64)
+			if err != nil {
+				return swept, err
+			}
+
+			oldRank, _ := values[i+2].(int64)
+			newRank, _ := values[i+3].(int64)
+
+			user := l.scoredUser(values[i].(string), score, int(oldRank)+1)
+			batch = append(batch, user)
+
+			event := Event{Type: EventMemberRemoved, UserID: user.UserID, OldRank: user.Rank, OldScore: user.Score, NewRank: UnrankedMember}
+			if f.opts.Action == FreshnessZero {
+				event.Type, event.NewRank = EventRankChanged, int(newRank)+1
+				l.mirrorScore(ctx, user.UserID, 0)
+			}
+			events = append(events, event)
+		}
+
+		if len(batch) > 0 {
+			if f.opts.Action == FreshnessRemove {
+				l.mirrorTrim(ctx, batch)
+			}
+			l.recordWrite(ctx)
+			l.emitAll(ctx, events)
+			swept = append(swept, batch...)
+		}
+
+		if scanned < freshnessSweepBatch {
+			return swept, nil
+		}
+	}
+}
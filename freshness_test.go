@@ -0,0 +1,80 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"testing"
+	"time"
+)
+
+// age makes member's last write look d old.
+func age(t *testing.T, l *Leaderboard, userID string, d time.Duration) {
+	t.Helper()
+
+	at := float64(time.Now().Add(-d).UnixNano() / int64(time.Millisecond))
+	if err := l.client().ZAdd(context.Background(), l.freshnessKey(), &redis.Z{Score: at, Member: userID}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSweepStale(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		action  string
+		wantIDs []string
+	}{
+		{FreshnessRemove, []string{"b"}},
+		{FreshnessZero, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			l, _ := newTestBoard(t, WithScoreFreshness(FreshnessOptions{TTL: time.Hour, Action: tt.action}))
+
+			if err := l.UpsertMembers(ctx, []MemberScore{{UserID: "a", Score: 5}, {UserID: "b", Score: 3}}, 0); err != nil {
+				t.Fatal(err)
+			}
+			age(t, l, "a", 2*time.Hour)
+
+			swept, err := l.SweepStale(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(swept) != 1 || swept[0].UserID != "a" || swept[0].Score != 5 || swept[0].Rank != 1 {
+				t.Fatalf("got swept %+v, want a with score 5 at rank 1", swept)
+			}
+
+			users, err := l.GetLeaders(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(userIDs(users), tt.wantIDs) {
+				t.Fatalf("got %v, want %v", userIDs(users), tt.wantIDs)
+			}
+
+			// Swept members aren't swept again
+			if swept, err := l.SweepStale(ctx); err != nil || len(swept) != 0 {
+				t.Fatalf("got %v and %v, want nothing swept", swept, err)
+			}
+		})
+	}
+}
+
+func TestSweepStaleInBackground(t *testing.T) {
+	l, _ := newTestBoard(t, WithScoreFreshness(FreshnessOptions{TTL: time.Hour, SweepInterval: 10 * time.Millisecond}))
+	ctx := context.Background()
+
+	if _, err := l.IncrementMemberScore(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	age(t, l, "a", 2*time.Hour)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for l.client().ZCard(ctx, l.leaderboardName).Val() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stale member wasn't swept")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
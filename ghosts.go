@@ -0,0 +1,277 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrGhostsDisabled = errors.New("leaderboard: ghosts are not enabled, see WithGhosts")
+)
+
+// Places ghost ARGV[1] with score ARGV[2] on board KEYS[1] and records it in
+// ghosts set KEYS[2] and metadata KEYS[3], where member cap finds it. Score is
+// logged to KEYS[4].
+var addGhostScript = redis.NewScript(scoreLogLua + versionLua + `
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("HSET", KEYS[3], "ghost:" .. ARGV[1], 1, "ghosts", redis.call("SCARD", KEYS[2]))
+logOp(KEYS[4], "set", ARGV[1], ARGV[2])
+bumpVersion(KEYS[3])
+
+return 1
+`)
+
+// Takes ghost ARGV[1] off board KEYS[1], ghosts set KEYS[2] and metadata
+// KEYS[3]. Removal is logged to KEYS[4].
+var removeGhostScript = redis.NewScript(scoreLogLua + versionLua + `
+redis.call("ZREM", KEYS[1], ARGV[1])
+redis.call("SREM", KEYS[2], ARGV[1])
+redis.call("HDEL", KEYS[3], "ghost:" .. ARGV[1])
+redis.call("HSET", KEYS[3], "ghosts", redis.call("SCARD", KEYS[2]))
+logOp(KEYS[4], "remove", ARGV[1], 0)
+bumpVersion(KEYS[3])
+
+return 1
+`)
+
+// WithGhosts lets AddGhost place ghosts on the board, e.g. last week's winner
+// on a time-trial board. Ghosts are listed by GetLeaders, GetLeadersInto and
+// GetLeadersPage with Ghost set, but aren't counted by TotalMembers or member
+// cap, are never evicted by it and don't take ranks from members (a ghost gets
+// rank of the member right below it). Ranks returned by writes, their events
+// and member lookups (GetMember, GetMemberFull, GetRanks, CompareMembers,
+// GetMemberRelative, GapToNextRank, around and top/bottom reads, Query) leave
+// ghosts out alike. Ghosts are left out of signed standings. Boards read from
+// display set of WithEmbeddedInfo don't list ghosts.
+func WithGhosts() Option {
+	return func(l *Leaderboard) {
+		l.ghosts = true
+	}
+}
+
+// ghostsKey is set of IDs of ghosts on the board.
+func (l *Leaderboard) ghostsKey() string {
+	return l.leaderboardName + ":ghosts"
+}
+
+// AddGhost places ghost with given score and info on the board, replacing
+// ghost with the same ID. ghostID shares namespace with user IDs, so prefix it
+// (e.g. "ghost:2024-W12") to keep it apart from members.
+func (l *Leaderboard) AddGhost(ctx context.Context, ghostID string, score int, info AdditionalUserInfo) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "AddGhost", ghostID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !l.ghosts {
+		return ErrGhostsDisabled
+	}
+
+	keys := l.ghostKeys()
+	if err := addGhostScript.Run(ctx, l.client(), keys, ghostID, score, l.scoreLogFlag()).Err(); err != nil {
+		return err
+	}
+
+	// Score log is carried over by migration itself
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return addGhostScript.Run(ctx, cli, keys, ghostID, score, "0").Err()
+	})
+
+	if info == nil {
+		return nil
+	}
+
+	value, err := l.profiles.upsert(ctx, ghostID, info, nil)
+	if err != nil {
+		return err
+	}
+	l.mirrorInfo(ctx, ghostID, value)
+
+	return nil
+}
+
+// RemoveGhost takes ghost off the board together with its info, unless the
+// info is kept in profiles shared with other boards.
+func (l *Leaderboard) RemoveGhost(ctx context.Context, ghostID string) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "RemoveGhost", ghostID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	keys := l.ghostKeys()
+	if err := removeGhostScript.Run(ctx, l.client(), keys, ghostID, l.scoreLogFlag()).Err(); err != nil {
+		return err
+	}
+
+	// Shared profile may still be used by other boards
+	if !l.profiles.shared {
+		if err := l.profiles.delete(ctx, ghostID); err != nil {
+			return err
+		}
+	}
+
+	l.mirrorRemove(ctx, ghostID)
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return removeGhostScript.Run(ctx, cli, keys, ghostID, "0").Err()
+	})
+
+	return nil
+}
+
+// ghostsLua defines helpers of scripts reading ranks, which leave out ghosts
+// like markGhosts does. Positions are 0-based indexes of the board in
+// descending order, ranks are positions not counting ghosts.
+const ghostsLua = `
+-- ghostPositions returns sorted positions of ghosts in set ghostsKey on board.
+local function ghostPositions(board, ghostsKey)
+	local positions = {}
+	for _, ghost in ipairs(redis.call("SMEMBERS", ghostsKey)) do
+		local position = redis.call("ZREVRANK", board, ghost)
+		if position then
+			positions[#positions + 1] = position
+		end
+	end
+	table.sort(positions)
+
+	return positions
+end
+
+local function rankAt(ghosts, position)
+	local rank = position
+	for _, ghost in ipairs(ghosts) do
+		if ghost >= position then
+			break
+		end
+		rank = rank - 1
+	end
+
+	return rank
+end
+
+local function positionOf(ghosts, rank)
+	local position = rank
+	for _, ghost in ipairs(ghosts) do
+		if ghost > position then
+			break
+		end
+		position = position + 1
+	end
+
+	return position
+end
+`
+
+// ghostKeys returns keys of addGhostScript and removeGhostScript.
+func (l *Leaderboard) ghostKeys() []string {
+	return []string{l.leaderboardName, l.ghostsKey(), l.metaKey(), l.scoreLogKey()}
+}
+
+// ghostRanks returns 1-based ranks of ghosts on the board, keyed by ghost ID.
+func (l *Leaderboard) ghostRanks(ctx context.Context, cli redis.UniversalClient) (map[string]int, error) {
+	if !l.ghosts {
+		return nil, nil
+	}
+
+	ghostIDs, err := cli.SMembers(ctx, l.ghostsKey()).Result()
+	if err != nil || len(ghostIDs) == 0 {
+		return nil, err
+	}
+
+	pipe := cli.Pipeline()
+	rankCmds := make([]*redis.IntCmd, len(ghostIDs))
+	for i, ghostID := range ghostIDs {
+		rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, ghostID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	ranks := make(map[string]int, len(ghostIDs))
+	for i, ghostID := range ghostIDs {
+		if rank, err := rankCmds[i].Result(); err == nil {
+			ranks[ghostID] = int(rank) + 1
+		}
+	}
+
+	return ranks, nil
+}
+
+// markGhosts sets Ghost on ghosts among ranked users and drops ghosts ranked
+// above from ranks of all of them, in place. It's the one place ranks are
+// adjusted for ghosts, every read and write returning ranks goes through it.
+func (l *Leaderboard) markGhosts(ctx context.Context, cli redis.UniversalClient, users []User) error {
+	if !l.ghosts || len(users) == 0 {
+		return nil
+	}
+
+	ranks, err := l.ghostRanks(ctx, cli)
+	if err != nil {
+		return err
+	}
+	markGhostRanks(ranks, users)
+
+	return nil
+}
+
+// markGhostRanks is markGhosts with ranks of ghosts already read.
+func markGhostRanks(ghostRanks map[string]int, users []User) {
+	if len(ghostRanks) == 0 {
+		return
+	}
+
+	for i := range users {
+		if users[i].Rank <= 0 {
+			continue
+		}
+
+		_, users[i].Ghost = ghostRanks[users[i].UserID]
+		users[i].Rank -= ghostsAbove(ghostRanks, users[i].Rank)
+	}
+}
+
+// markGhost is markGhosts for one user.
+func (l *Leaderboard) markGhost(ctx context.Context, cli redis.UniversalClient, user User) (User, error) {
+	marked := []User{user}
+	err := l.markGhosts(ctx, cli, marked)
+
+	return marked[0], err
+}
+
+// memberRank returns member's rank on the board as writes report it, see markGhosts.
+func (l *Leaderboard) memberRank(ctx context.Context, userID string) (int, error) {
+	rank, err := getMemberRank(ctx, l.client(), l.leaderboardName, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	user, err := l.markGhost(ctx, l.client(), User{UserID: userID, Rank: rank})
+
+	return user.Rank, err
+}
+
+// ghostsAbove returns number of ghosts ranked above rank.
+func ghostsAbove(ghostRanks map[string]int, rank int) int {
+	above := 0
+	for _, ghostRank := range ghostRanks {
+		if ghostRank < rank {
+			above++
+		}
+	}
+
+	return above
+}
+
+// countGhosts returns number of ghosts on the board.
+func (l *Leaderboard) countGhosts(ctx context.Context) (int, error) {
+	if !l.ghosts {
+		return 0, nil
+	}
+
+	ghosts, err := l.readClient(readOptions{}).SCard(ctx, l.ghostsKey()).Result()
+
+	return int(ghosts), err
+}
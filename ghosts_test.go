@@ -0,0 +1,187 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newTestGhostBoard returns board with members a, b and c and ghost g ranked
+// between a and b, so b has rank 2 and c rank 3.
+func newTestGhostBoard(t *testing.T) *Leaderboard {
+	t.Helper()
+
+	l, _ := newTestBoard(t, WithGhosts())
+	seed(t, l, "a", 10, "b", 8, "c", 5)
+	if err := l.AddGhost(context.Background(), "g", 9, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return l
+}
+
+func TestGhostsDontTakeRanks(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		rank func(l *Leaderboard) (int, error)
+	}{
+		{"GetMember", func(l *Leaderboard) (int, error) {
+			user, err := l.GetMember(ctx, "c", false)
+			return user.Rank, err
+		}},
+		{"GetMemberFull", func(l *Leaderboard) (int, error) {
+			user, err := l.GetMemberFull(ctx, "c")
+			return user.Rank, err
+		}},
+		{"GetRanks", func(l *Leaderboard) (int, error) {
+			ranks, err := l.GetRanks(ctx, []string{"c"})
+			return ranks["c"], err
+		}},
+		{"CompareMembers", func(l *Leaderboard) (int, error) {
+			comparison, err := l.CompareMembers(ctx, "b", "c")
+			return comparison.B.Rank, err
+		}},
+		{"GetMembersAroundMany", func(l *Leaderboard) (int, error) {
+			around, err := l.GetMembersAroundMany(ctx, []string{"c"}, 1)
+			users := around["c"]
+			if err != nil || len(users) != 2 {
+				return 0, err
+			}
+			return users[1].Rank, nil
+		}},
+		{"Query", func(l *Leaderboard) (int, error) {
+			users, err := l.Query().Run(ctx)
+			if err != nil || len(users) != 4 {
+				return 0, err
+			}
+			return users[3].Rank, nil
+		}},
+		{"GetMemberRelative", func(l *Leaderboard) (int, error) {
+			standing, err := l.GetMemberRelative(ctx, "c")
+			return standing.Rank, err
+		}},
+		{"GapToNextRank", func(l *Leaderboard) (int, error) {
+			gap, err := l.GapToNextRank(ctx, "c", 1)
+			return gap.Rank, err
+		}},
+		{"IncrementMemberScore", func(l *Leaderboard) (int, error) {
+			user, err := l.IncrementMemberScore(ctx, "c", 0)
+			return user.Rank, err
+		}},
+		{"SubmitScore", func(l *Leaderboard) (int, error) {
+			user, err := l.SubmitScore(ctx, "c", 0)
+			return user.Rank, err
+		}},
+		{"FirstOrInsertMember", func(l *Leaderboard) (int, error) {
+			user, err := l.FirstOrInsertMember(ctx, "c", 0)
+			return user.Rank, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestGhostBoard(t)
+
+			rank, err := tt.rank(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rank != 3 {
+				t.Fatalf("got rank %d, want 3", rank)
+			}
+		})
+	}
+}
+
+func TestGhostsSkippedAsNextMember(t *testing.T) {
+	ctx := context.Background()
+	l := newTestGhostBoard(t)
+
+	standing, err := l.GetMemberRelative(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standing.Rank != 2 || standing.NextUserID != "a" || standing.GapToNext != 2 || standing.LeaderScore != 10 {
+		t.Fatalf("got %+v, want rank 2 and gap 2 to a", standing)
+	}
+
+	gap, err := l.GapToNextRank(ctx, "c", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gap.NextUserID != "b" || gap.GapToNext != 3 || gap.TargetUserID != "b" || gap.GapToTarget != 3 {
+		t.Fatalf("got %+v, want gap 3 to b", gap)
+	}
+}
+
+func TestGhostsOvertaken(t *testing.T) {
+	l := newTestGhostBoard(t)
+
+	// c ties with g and stays below it
+	update, err := l.IncrementMemberScoreWithOvertaken(context.Background(), "c", 4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Rank != 2 || update.PreviousRank != 3 {
+		t.Fatalf("got rank %d from %d, want 2 from 3", update.Rank, update.PreviousRank)
+	}
+	if len(update.Overtaken) != 1 || update.Overtaken[0].UserID != "b" || update.Overtaken[0].Rank != 3 {
+		t.Fatalf("got overtaken %+v, want b with rank 3", update.Overtaken)
+	}
+}
+
+func TestGhostsDontCountTowardsMemberCap(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reject", func(t *testing.T) {
+		l := newTestGhostBoard(t)
+		if err := l.SetMemberCap(ctx, 4, CapReject); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := l.FirstOrInsertMember(ctx, "d", 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.FirstOrInsertMember(ctx, "e", 1); !errors.Is(err, ErrBoardFull) {
+			t.Fatalf("got error %v, want %v", err, ErrBoardFull)
+		}
+	})
+
+	t.Run("evict lowest", func(t *testing.T) {
+		l := newTestGhostBoard(t)
+		if err := l.AddGhost(ctx, "low", 0, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.SetMemberCap(ctx, 3, CapEvictLowest); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := l.FirstOrInsertMember(ctx, "d", 7); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.UpsertMembers(ctx, []MemberScore{{UserID: "e", Score: 6}}, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := l.client().ZRevRange(ctx, l.leaderboardName, 0, -1).Val()
+		if want := []string{"a", "g", "b", "e", "low"}; !equalStrings(ids, want) {
+			t.Fatalf("got members %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("removed ghost", func(t *testing.T) {
+		l := newTestGhostBoard(t)
+		if err := l.SetMemberCap(ctx, 3, CapReject); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.RemoveGhost(ctx, "g"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := l.FirstOrInsertMember(ctx, "d", 1); !errors.Is(err, ErrBoardFull) {
+			t.Fatalf("got error %v, want %v", err, ErrBoardFull)
+		}
+	})
+}
@@ -1,5 +1,8 @@
 module github.com/croatiangrn/go-redis-leaderboard
 
-go 1.15
+go 1.16
 
-require github.com/go-redis/redis/v8 v8.4.2
+require (
+	github.com/alicebob/miniredis/v2 v2.23.0
+	github.com/go-redis/redis/v8 v8.4.2
+)
@@ -0,0 +1,196 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metaLastWriteAt = "last_write_at"
+
+	// Writes are counted in per-minute buckets kept for writeStatsRetention
+	writeStatsBucket    = time.Minute
+	writeStatsRetention = 24 * time.Hour
+)
+
+// WithWriteStats makes every score write and removal record time of the last
+// write and per-minute write counters, exposed by LastWriteAt and WriteRate.
+// It costs one extra pipelined round trip per write.
+func WithWriteStats() Option {
+	return func(l *Leaderboard) {
+		l.writeStats = true
+	}
+}
+
+// scoreWritten must be called after every successful score change with score
+// as stored in Redis, so it's mirrored unrounded.
+func (l *Leaderboard) scoreWritten(ctx context.Context, userID string, score float64) {
+	l.mirrorScore(ctx, userID, score)
+	l.recordWrite(ctx)
+	l.touch(ctx, userID)
+	l.detectAnomaly(ctx, userID, l.precision.whole(score))
+	l.syncEmbedded(ctx, userID, "")
+}
+
+// scoresWritten is scoreWritten for members written together, every hook costs
+// one round trip for the whole batch.
+func (l *Leaderboard) scoresWritten(ctx context.Context, written []redis.Z) {
+	if len(written) == 0 {
+		return
+	}
+
+	members := make([]MemberScore, len(written))
+	for i, z := range written {
+		members[i] = MemberScore{UserID: z.Member.(string), Score: l.precision.whole(z.Score)}
+	}
+
+	l.mirrorScores(ctx, written)
+	l.recordWrite(ctx)
+	l.touchAll(ctx, members)
+	l.detectAnomalies(ctx, members)
+	l.syncEmbeddedAll(ctx, members)
+}
+
+// storedScores returns members with scores as ZADD stores them.
+func storedScores(members []MemberScore) []redis.Z {
+	z := make([]redis.Z, len(members))
+	for i, m := range members {
+		z[i] = redis.Z{Score: float64(m.Score), Member: m.UserID}
+	}
+
+	return z
+}
+
+// scriptCall is one call of a script run by runPipelined.
+type scriptCall struct {
+	keys []string
+	args []interface{}
+}
+
+// runPipelined runs script once per call in one round trip. Script is sent
+// whole only when the server doesn't have it cached yet.
+func runPipelined(ctx context.Context, cli redis.UniversalClient, script *redis.Script, calls []scriptCall) ([]*redis.Cmd, error) {
+	run := func(eval func(pipe redis.Pipeliner, c scriptCall) *redis.Cmd) ([]*redis.Cmd, error) {
+		pipe := cli.Pipeline()
+		cmds := make([]*redis.Cmd, len(calls))
+		for i, c := range calls {
+			cmds[i] = eval(pipe, c)
+		}
+		_, err := pipe.Exec(ctx)
+
+		return cmds, err
+	}
+
+	cmds, err := run(func(pipe redis.Pipeliner, c scriptCall) *redis.Cmd {
+		return script.EvalSha(ctx, pipe, c.keys, c.args...)
+	})
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		cmds, err = run(func(pipe redis.Pipeliner, c scriptCall) *redis.Cmd {
+			return script.Eval(ctx, pipe, c.keys, c.args...)
+		})
+	}
+
+	return cmds, err
+}
+
+// memberRemoved must be called after member is removed from the board.
+func (l *Leaderboard) memberRemoved(ctx context.Context, userID string) {
+	l.mirrorRemove(ctx, userID)
+	l.recordWrite(ctx)
+	l.untouch(ctx, userID)
+	l.syncEmbedded(ctx, userID, "")
+}
+
+// membersRemoved is memberRemoved for members removed together whose
+// freshness was removed in the same MULTI/EXEC as their scores.
+func (l *Leaderboard) membersRemoved(ctx context.Context, members []MemberScore) {
+	for _, m := range members {
+		l.mirrorRemove(ctx, m.UserID)
+	}
+	l.recordWrite(ctx)
+	l.syncEmbeddedAll(ctx, members)
+}
+
+func (l *Leaderboard) writeStatsKey(bucket int64) string {
+	return l.leaderboardName + ":writes:" + strconv.FormatInt(bucket, 10)
+}
+
+func writeStatsBucketOf(t time.Time) int64 {
+	return t.Unix() / int64(writeStatsBucket/time.Second)
+}
+
+// recordWrite mirrors board's version bumped by the write and updates write
+// stats. Errors are ignored, stats must never fail the write itself.
+func (l *Leaderboard) recordWrite(ctx context.Context) {
+	l.mirrorVersion(ctx)
+
+	if !l.writeStats {
+		return
+	}
+
+	now := time.Now()
+	bucketKey := l.writeStatsKey(writeStatsBucketOf(now))
+
+	pipe := l.client().Pipeline()
+	pipe.HSet(ctx, l.metaKey(), metaLastWriteAt, now.UnixNano()/int64(time.Millisecond))
+	pipe.Incr(ctx, bucketKey)
+	pipe.Expire(ctx, bucketKey, writeStatsRetention+writeStatsBucket)
+	_, _ = pipe.Exec(ctx)
+}
+
+// LastWriteAt returns time of the last score write or removal. Zero time is
+// returned if no write was recorded, see WithWriteStats.
+func (l *Leaderboard) LastWriteAt(ctx context.Context) (_ time.Time, err error) {
+	defer l.wrapError("LastWriteAt", "", &err)
+
+	ms, err := l.client().HGet(ctx, l.metaKey(), metaLastWriteAt).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// WriteRate returns average number of writes per second during last window,
+// see WithWriteStats. Window is rounded up to whole minutes and capped at 24 hours.
+func (l *Leaderboard) WriteRate(ctx context.Context, window time.Duration) (_ float64, err error) {
+	defer l.wrapError("WriteRate", "", &err)
+
+	if window > writeStatsRetention {
+		window = writeStatsRetention
+	}
+
+	buckets := int64((window + writeStatsBucket - 1) / writeStatsBucket)
+	if buckets < 1 {
+		buckets = 1
+	}
+
+	current := writeStatsBucketOf(time.Now())
+	keys := make([]string, 0, buckets)
+	for b := current - buckets + 1; b <= current; b++ {
+		keys = append(keys, l.writeStatsKey(b))
+	}
+
+	values, err := l.client().MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var writes int64
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			n, _ := strconv.ParseInt(s, 10, 64)
+			writes += n
+		}
+	}
+
+	return float64(writes) / (float64(buckets) * writeStatsBucket.Seconds()), nil
+}
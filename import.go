@@ -0,0 +1,171 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotANumber    = errors.New("leaderboard: value is not a number")
+	ErrMissingUserID = errors.New("leaderboard: record has no user ID")
+)
+
+// Transform converts value of one record field during import.
+type Transform func(value interface{}) (interface{}, error)
+
+// FieldTransform applies Transform to Field of every imported record.
+type FieldTransform struct {
+	Field     string
+	Transform Transform
+}
+
+// ImportMapping declares how records of a source system map to board members.
+type ImportMapping struct {
+	UserIDField string
+	ScoreField  string
+	// Transforms are applied in order before fields are mapped, e.g.
+	// {ScoreField, ParseNumber()} followed by {ScoreField, ScaleBy(1000)}
+	// converts "12.5" seconds to 12500 milliseconds
+	Transforms []FieldTransform
+}
+
+// ImportError tells which record failed to import and why.
+type ImportError struct {
+	// Record is 0-based index of the record in source
+	Record int
+	Field  string
+	Err    error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("leaderboard: import of record %d failed on field %q: %v", e.Record, e.Field, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ParseNumber converts strings (and json.Number) to float64, numbers pass through.
+func ParseNumber() Transform {
+	return func(value interface{}) (interface{}, error) {
+		return toFloat(value)
+	}
+}
+
+// ScaleBy multiplies numeric value by factor, e.g. ScaleBy(1000) for seconds to milliseconds.
+func ScaleBy(factor float64) Transform {
+	return func(value interface{}) (interface{}, error) {
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return f * factor, nil
+	}
+}
+
+// Round rounds numeric value to the nearest integer.
+func Round() Transform {
+	return func(value interface{}) (interface{}, error) {
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return math.Round(f), nil
+	}
+}
+
+// Import loads records of a source system into the board using BulkLoad after
+// applying mapping's transforms. Scores are truncated to integers, use Round
+// transform to round them instead. First record which can't be mapped stops
+// the import with *ImportError, records before it are already loaded.
+//
+// source must call yield for every record and stop when yield returns false.
+func (l *Leaderboard) Import(ctx context.Context, source func(yield func(record map[string]interface{}) bool), mapping ImportMapping, opts BulkLoadOptions) (loaded int, err error) {
+	done, err := l.trackOp("Import")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	var mapErr error
+
+	loaded, err = l.BulkLoad(ctx, func(yield func(member MemberScore) bool) {
+		index := 0
+		source(func(record map[string]interface{}) bool {
+			member, err := mapping.apply(index, record)
+			if err != nil {
+				mapErr = err
+				return false
+			}
+			index++
+
+			return yield(member)
+		})
+	}, opts)
+
+	if mapErr != nil {
+		return loaded, mapErr
+	}
+
+	return loaded, err
+}
+
+func (m ImportMapping) apply(index int, record map[string]interface{}) (MemberScore, error) {
+	fields := make(map[string]interface{}, len(record))
+	for k, v := range record {
+		fields[k] = v
+	}
+
+	for _, t := range m.Transforms {
+		value, err := t.Transform(fields[t.Field])
+		if err != nil {
+			return MemberScore{}, &ImportError{Record: index, Field: t.Field, Err: err}
+		}
+		fields[t.Field] = value
+	}
+
+	userID := strings.TrimSpace(fmt.Sprint(fields[m.UserIDField]))
+	if fields[m.UserIDField] == nil || userID == "" {
+		return MemberScore{}, &ImportError{Record: index, Field: m.UserIDField, Err: ErrMissingUserID}
+	}
+
+	score, err := toFloat(fields[m.ScoreField])
+	if err != nil {
+		return MemberScore{}, &ImportError{Record: index, Field: m.ScoreField, Err: err}
+	}
+
+	return MemberScore{UserID: userID, Score: int(score)}, nil
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, ErrNotANumber
+		}
+
+		return f, nil
+	default:
+		return 0, ErrNotANumber
+	}
+}
@@ -0,0 +1,194 @@
+package go_redis_leaderboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSON types of InfoSchema fields.
+const (
+	InfoTypeString  = "string"
+	InfoTypeNumber  = "number"
+	InfoTypeBoolean = "boolean"
+	InfoTypeObject  = "object"
+	InfoTypeArray   = "array"
+)
+
+// InfoSchema describes AdditionalInfo accepted by a profile store. Info must
+// be a JSON object.
+type InfoSchema struct {
+	// MaxSize is maximum size of info in bytes, 0 means unlimited
+	MaxSize int
+	// Fields maps top-level fields to their JSON type (InfoType*)
+	Fields map[string]string
+	// Required are fields which must be present
+	Required []string
+	// AllowUnknown accepts fields not listed in Fields
+	AllowUnknown bool
+}
+
+// InfoValidationError is returned when info doesn't match InfoSchema.
+type InfoValidationError struct {
+	// Field is offending field, empty when info as a whole is invalid
+	Field  string
+	Reason string
+}
+
+func (e *InfoValidationError) Error() string {
+	if e.Field == "" {
+		return "leaderboard: invalid info: " + e.Reason
+	}
+
+	return fmt.Sprintf("leaderboard: invalid info field %q: %s", e.Field, e.Reason)
+}
+
+// InfoSchemaFor derives schema from struct's json tags: every exported field is
+// allowed with JSON type matching its Go type and fields without omitempty are
+// required. Unknown fields are rejected.
+func InfoSchemaFor(v interface{}) *InfoSchema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := &InfoSchema{Fields: map[string]string{}}
+	if t.Kind() != reflect.Struct {
+		return schema
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, omitEmpty := f.Name, false
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" && len(parts) == 1 {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+
+		schema.Fields[name] = jsonTypeOf(f.Type)
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+func jsonTypeOf(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return InfoTypeString
+	case reflect.Bool:
+		return InfoTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return InfoTypeNumber
+	case reflect.Slice, reflect.Array:
+		return InfoTypeArray
+	default:
+		return InfoTypeObject
+	}
+}
+
+// Validate checks raw JSON info against schema.
+func (s *InfoSchema) Validate(info []byte) error {
+	if s.MaxSize > 0 && len(info) > s.MaxSize {
+		return &InfoValidationError{Reason: fmt.Sprintf("%d bytes exceeds limit of %d bytes", len(info), s.MaxSize)}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(info, &fields); err != nil || fields == nil {
+		return &InfoValidationError{Reason: "must be a JSON object"}
+	}
+
+	for _, name := range s.Required {
+		if _, ok := fields[name]; !ok {
+			return &InfoValidationError{Field: name, Reason: "is required"}
+		}
+	}
+
+	for name, value := range fields {
+		expected, ok := s.Fields[name]
+		if !ok {
+			if s.AllowUnknown {
+				continue
+			}
+
+			return &InfoValidationError{Field: name, Reason: "is unknown"}
+		}
+
+		if actual := rawJSONType(value); actual != expected && actual != "null" {
+			return &InfoValidationError{Field: name, Reason: fmt.Sprintf("must be %s, got %s", expected, actual)}
+		}
+	}
+
+	return nil
+}
+
+func rawJSONType(value json.RawMessage) string {
+	trimmed := strings.TrimSpace(string(value))
+	if trimmed == "" {
+		return "null"
+	}
+
+	switch trimmed[0] {
+	case '"':
+		return InfoTypeString
+	case '{':
+		return InfoTypeObject
+	case '[':
+		return InfoTypeArray
+	case 't', 'f':
+		return InfoTypeBoolean
+	case 'n':
+		return "null"
+	default:
+		return InfoTypeNumber
+	}
+}
+
+// WithInfoSchema makes UpsertMemberInfo (and its localized variant) reject info
+// not matching schema with *InfoValidationError. When board shares profile
+// store (Manager), only writes done through this board are validated, use
+// ProfileStore.SetSchema to validate all of them.
+func WithInfoSchema(schema *InfoSchema) Option {
+	return func(l *Leaderboard) {
+		cp := *l.profiles
+		cp.schema = schema
+		l.profiles = &cp
+	}
+}
+
+// SetSchema makes store reject info not matching schema, nil disables
+// validation. It must be called before store is used.
+func (p *ProfileStore) SetSchema(schema *InfoSchema) {
+	p.schema = schema
+}
+
+func (p *ProfileStore) validate(additionalData AdditionalUserInfo) error {
+	if p.schema == nil {
+		return nil
+	}
+
+	return p.schema.Validate(additionalData)
+}
@@ -7,9 +7,10 @@ import (
 	"errors"
 	"github.com/go-redis/redis/v8"
 	"math"
-	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -21,18 +22,11 @@ const (
 	DefaultPageSize = 25
 )
 
-var ctx = context.Background()
-
 var (
 	ErrIncrementByMustBePositiveInteger = errors.New("leaderboard: incrementBy must be positive integer")
+	ErrNilClient                        = errors.New("leaderboard: redis client must not be nil")
 )
 
-var allowedModes = map[string]bool{
-	DevMode:        true,
-	StagingMode:    true,
-	ProductionMode: true,
-}
-
 var allowedPageSizes = map[int]bool{
 	10:  true,
 	25:  true,
@@ -46,161 +40,477 @@ type User struct {
 	Score          int             `json:"score"`
 	Rank           int             `json:"rank"`
 	AdditionalInfo json.RawMessage `json:"additional_info"`
+	Tags           []string        `json:"tags,omitempty"`
+	// Ranked is set by member lookups of boards using UnrankedFlag policy
+	Ranked *bool `json:"ranked,omitempty"`
+	// Record is set when board tracks win/loss records, see WithRecords
+	Record *Record `json:"record,omitempty"`
+	// Pending is set on scores waiting for verification, see WithPendingVerification
+	Pending bool `json:"pending,omitempty"`
+	// PreciseScore is set by boards keeping one decimal place, see WithPrecision
+	PreciseScore float64 `json:"precise_score,omitempty"`
+	// Ghost is set on ghosts listed for comparison, see WithGhosts
+	Ghost bool `json:"ghost,omitempty"`
 }
 
 type Leaderboard struct {
 	RedisSettings    RedisSettings
 	PageSize         int
 	mode             string
-	redisCli         *redis.Client
+	redisCli         atomic.Value // clientHolder, swapped on migration cutover
+	replicaCli       atomic.Value // clientHolder with client serving eventually consistent reads
+	regionalReplicas atomic.Value // map[string]redis.UniversalClient keyed by region
+	migration        atomic.Value // *migrationTarget receiving dual writes
+	migrationMu      sync.Mutex
 	leaderboardName  string
-	userInfoHashName string
+	profiles         *ProfileStore
+	ownsClient       bool
+	scoringMode      string
+	countCache       *countCache
+	pagesCount       countCache
+	dedupWindow      time.Duration
+	eventsMaxLen     int64
+	writeStats       bool
+	scoreUnit        scoreUnitCache
+	defaultTimeout   time.Duration
+	slowOpThreshold  time.Duration
+	slowOpHook       func(OperationStats)
+	tiers            tierCache
+	clientCache      *clientCache
+	inflight         opTracker
+	eventSourced     bool
+	anomaly          *AnomalyOptions
+	unranked         UnrankedOptions
+	freshness        *freshness
+	top              *materializedTop
+	zeroFloor        bool
+	records          bool
+	scoreTiers       []ScoreTier
+	infoResolver     InfoResolver
+	onResolveError   func(error)
+	savepoints       *savepoints
+	baselineTTL      time.Duration
+	embedded         *embeddedInfo
+	flights          *flightGroup
+	precision        Precision
+	banChecker       BanChecker
+	onBanCheckError  func(error)
+	pending          *pendingVerification
+	earningCap       *earningCap
+	ghosts           bool
+	teamRollup       *TeamLeaderboard
+	budgetReserve    time.Duration
+	optionErr        error
 }
 
+// Option configures optional Leaderboard behaviour in NewLeaderboard.
+type Option func(*Leaderboard)
+
 // NewLeaderboard is constructor for Leaderboard.
 //
 // IMPORTANT: ``leaderboardName`` and ``uniqueIdentifier`` must be unique project/app wide!
 //
 // uniqueIdentifier is something like table name that will be used to store user info.
+//
+// mode selects ModeProfile of the board (connection pool, operation logging,
+// validation), see RegisterModeProfile. Unknown modes fall back to DevMode.
 //goland:noinspection GoUnusedExportedFunction
-func NewLeaderboard(redisSettings RedisSettings, mode, leaderboardName, userInfoStorageHash string, pageSize int) (*Leaderboard, error) {
-	redisConn := connectToRedis(redisSettings.Host, redisSettings.Password, redisSettings.DB)
-	if _, ok := allowedModes[mode]; !ok {
-		mode = DevMode
-	}
+func NewLeaderboard(redisSettings RedisSettings, mode, leaderboardName, userInfoStorageHash string, pageSize int, opts ...Option) (*Leaderboard, error) {
+	mode, profile := resolveMode(mode)
 
-	if _, ok := allowedPageSizes[pageSize]; !ok {
-		pageSize = DefaultPageSize
+	pageSize, err := resolvePageSize(pageSize, profile)
+	if err != nil {
+		return nil, err
 	}
 
+	redisConn := connectToRedis(redisSettings, profile.PoolSize)
+
 	// Leaderboard naming convention: "go_leaderboard-<mode>-<appID>-<eventType>-<metaData>"
-	return &Leaderboard{RedisSettings: redisSettings, redisCli: redisConn, leaderboardName: leaderboardName, userInfoHashName: userInfoStorageHash, PageSize: pageSize}, nil
+	l, err := newLeaderboard(redisConn, mode, leaderboardName, pageSize, userInfoStorageHash, nil, opts)
+	if err != nil {
+		_ = redisConn.Close()
+		return nil, err
+	}
+	l.RedisSettings = redisSettings
+	l.ownsClient = true
+
+	return l, nil
+}
+
+// NewLeaderboardWithClient is NewLeaderboard on top of client owned by the
+// application, e.g. pooled and instrumented *redis.Client or
+// *redis.ClusterClient shared by several boards. Board never closes the client
+// (Shutdown leaves it open) and can't be migrated with MigrateTo.
+// WithClientSideCache is ignored, as connection settings for invalidations
+// aren't known.
+func NewLeaderboardWithClient(cli redis.UniversalClient, mode, leaderboardName, userInfoStorageHash string, pageSize int, opts ...Option) (*Leaderboard, error) {
+	if cli == nil {
+		return nil, ErrNilClient
+	}
+
+	mode, profile := resolveMode(mode)
+
+	pageSize, err := resolvePageSize(pageSize, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := newLeaderboard(cli, mode, leaderboardName, pageSize, userInfoStorageHash, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	l.clientCache = nil
+
+	return l, nil
+}
+
+// newLeaderboard creates board on top of existing client. Board gets its own
+// ProfileStore stored in userInfoHash unless shared profiles are given. Invalid
+// options are reported before any background goroutine is started.
+func newLeaderboard(redisConn redis.UniversalClient, mode, leaderboardName string, pageSize int, userInfoHash string, profiles *ProfileStore, opts []Option) (*Leaderboard, error) {
+	l := &Leaderboard{mode: mode, leaderboardName: leaderboardName, PageSize: pageSize, scoringMode: ScoringCumulative}
+	l.redisCli.Store(clientHolder{redisConn})
+	l.replicaCli.Store(clientHolder{})
+	l.migration.Store((*migrationTarget)(nil))
+
+	if profiles == nil {
+		profiles = &ProfileStore{hashName: userInfoHash, client: l.client}
+	}
+	l.profiles = profiles
+
+	profile := l.modeProfile()
+	for _, opt := range profile.Options {
+		opt(l)
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.optionErr != nil {
+		return nil, l.optionErr
+	}
+	if err := l.checkEmbeddedInfo(); err != nil {
+		return nil, err
+	}
+
+	if profile.LogOperations && l.slowOpHook == nil {
+		l.slowOpHook = logOperation
+	}
+	l.startSweeper()
+	l.startMaterializer()
+	l.startPendingVerifier()
+
+	return l, nil
+}
+
+func (l *Leaderboard) client() redis.UniversalClient {
+	return l.redisCli.Load().(clientHolder).cli
 }
 
-// InsertMember inserts member to leaderboard if the member doesn't exist
-func (l *Leaderboard) FirstOrInsertMember(userID string, score int) (user User, err error) {
-	currentRank, err := getMemberRank(l.redisCli, l.leaderboardName, userID)
-	if err != nil && !errors.Is(err, redis.Nil) {
+// FirstOrInsertMember inserts member to leaderboard with score if the member
+// doesn't exist, existing member keeps its score. Either way member's score and
+// rank are returned, checked and written atomically in one round trip.
+func (l *Leaderboard) FirstOrInsertMember(ctx context.Context, userID string, score int) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "FirstOrInsertMember", userID)
+	if err != nil {
 		return User{}, err
 	}
+	defer done(&err)
 
-	// Member already exists in our leaderboard, fetch score and info, too and return the data
-	if currentRank > 0 {
-		currentScore, err := getMemberScore(l.redisCli, l.leaderboardName, userID)
-		if err != nil {
-			return User{}, err
-		}
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
 
-		user = User{
-			UserID: userID,
-			Score:  currentScore,
-			Rank:   currentRank,
-		}
+	// Lookup and insert are done by one script, so concurrent calls never
+	// overwrite score of a member inserted in the meantime
+	user, inserted, err := l.insertMember(ctx, l.client(), l.leaderboardName, userID, score, l.scoreLogFlag())
+	if err != nil {
+		return User{}, err
+	}
+	if user, err = l.markGhost(ctx, l.client(), user); err != nil {
+		return User{}, err
+	}
 
-		return user, nil
+	if inserted {
+		l.scoreWritten(ctx, userID, float64(score))
+		l.emitScoreChange(ctx, User{UserID: userID, Rank: UnrankedMember}, user)
 	}
 
-	// Member doesn't exist. Insert rank, score and info and return the data
-	if err := insertMemberScore(l.redisCli, l.leaderboardName, userID, score); err != nil {
+	return user, nil
+}
+
+// GetMember fetches member's rank, score and (optionally) info in one MULTI/EXEC
+// round trip, so all values come from the same state of the board. Unranked
+// member is returned according to board's UnrankedPolicy.
+func (l *Leaderboard) GetMember(ctx context.Context, userID string, withInfo bool, opts ...ReadOption) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMember", userID)
+	if err != nil {
 		return User{}, err
 	}
+	defer done(&err)
 
-	rank, err := updateMemberRank(l.redisCli, l.leaderboardName, userID)
+	o := newReadOptions(opts)
+	user, err = l.coalesceMember(flightKey("member", userID+"|"+strconv.FormatBool(withInfo), o), func() (User, error) {
+		return l.getMember(ctx, userID, withInfo, o)
+	})
 	if err != nil {
 		return User{}, err
 	}
 
-	u := User{
-		UserID: userID,
-		Score:  score,
-		Rank:   rank,
+	marked := []User{user}
+	if err := l.markGhosts(ctx, l.readClient(o), marked); err != nil {
+		return User{}, err
 	}
 
-	return u, nil
+	return l.presentMember(ctx, marked[0])
 }
 
-func (l *Leaderboard) GetMember(userID string, withInfo bool) (user User, err error) {
-	rank, err := getMemberRank(l.redisCli, l.leaderboardName, userID)
+// getMember is GetMember returning unranked member with UnrankedMember rank.
+func (l *Leaderboard) getMember(ctx context.Context, userID string, withInfo bool, o readOptions) (user User, err error) {
+	pipe := l.readClient(o).TxPipeline()
+	rankCmd := pipe.ZRevRank(ctx, l.leaderboardName, userID)
+	scoreCmd := pipe.ZScore(ctx, l.leaderboardName, userID)
+
+	var infoCmd *redis.StringCmd
+	if withInfo {
+		infoCmd = pipe.HGet(ctx, l.profiles.hashName, userID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return User{}, err
+	}
+
+	rank, err := rankCmd.Result()
 	if err != nil {
 		if !errors.Is(err, redis.Nil) {
 			return User{}, err
 		}
 
-		rank = UnrankedMember
+		return User{UserID: userID, Rank: UnrankedMember}, nil
+	}
+
+	score, err := scoreCmd.Result()
+	if err != nil {
+		return User{}, err
 	}
 
-	var score int
 	var additionalInfo json.RawMessage
+	if withInfo {
+		storedData, err := infoCmd.Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return User{}, err
+		}
 
-	if rank != UnrankedMember {
-		memberScore, scoreErr := getMemberScore(l.redisCli, l.leaderboardName, userID)
-		if scoreErr != nil {
-			if !errors.Is(err, redis.Nil) {
+		if err == nil {
+			if additionalInfo, err = decodeMemberInfo(storedData, l.profiles.keyring); err != nil {
 				return User{}, err
 			}
 		}
+	}
 
-		score = memberScore
-		if withInfo {
-			message, err := l.GetMemberInfo(userID)
-			if err != nil {
-				if !errors.Is(err, redis.Nil) {
-					return User{}, err
-				}
-			}
+	user = l.scoredUser(userID, score, int(rank)+1)
+	user.AdditionalInfo = additionalInfo
 
-			additionalInfo = message
+	users := []User{user}
+	if err := l.loadRecords(ctx, l.readClient(o), users); err != nil {
+		return User{}, err
+	}
+
+	if withInfo {
+		if err := l.hydrateInfo(ctx, users); err != nil {
+			return User{}, err
 		}
 	}
 
-	user = User{
-		UserID:         userID,
-		Score:          score,
-		Rank:           rank,
-		AdditionalInfo: additionalInfo,
+	return users[0], nil
+}
+
+// RemoveMember removes member's score, info (unless profiles are shared with
+// other boards) and companion data (tags, record, freshness) in one MULTI/EXEC
+// and reports whether member was on the board.
+func (l *Leaderboard) RemoveMember(ctx context.Context, userID string) (existed bool, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "RemoveMember", userID)
+	if err != nil {
+		return false, err
+	}
+	defer done(&err)
+
+	existing, err := l.removeMembers(ctx, []string{userID})
+	if err != nil {
+		return false, err
 	}
 
-	return
+	return existing[0], nil
 }
 
-func (l *Leaderboard) RemoveMember(userID string) error {
-	if _, err := l.GetMember(userID, false); err != nil {
-		return err
+// RemoveMembers is RemoveMember for many members, their scores, info and
+// companion data are removed in one MULTI/EXEC. Returned flags tell whether members existed, in
+// order of userIDs.
+func (l *Leaderboard) RemoveMembers(ctx context.Context, userIDs ...string) (existed []bool, err error) {
+	ctx, done, err := l.startOp(ctx, "RemoveMembers")
+	if err != nil {
+		return nil, err
 	}
+	defer done(&err)
 
-	if _, err := l.redisCli.ZRem(ctx, l.leaderboardName, userID).Result(); err != nil {
-		return err
+	if len(userIDs) == 0 {
+		return []bool{}, nil
 	}
 
-	if _, err := l.redisCli.HDel(ctx, l.userInfoHashName, userID).Result(); err != nil {
-		return err
+	return l.removeMembers(ctx, userIDs)
+}
+
+func (l *Leaderboard) removeMembers(ctx context.Context, userIDs []string) ([]bool, error) {
+	// Tag names and locales are needed up front, to remove members' companion data in the same MULTI/EXEC
+	pipe := l.client().Pipeline()
+	tagsCmd := pipe.SMembers(ctx, l.tagsKey())
+	localesCmd := pipe.SMembers(ctx, l.profiles.localesKey())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
 	}
 
-	return nil
+	removed := make([]MemberScore, len(userIDs))
+	for i, userID := range userIDs {
+		removed[i] = MemberScore{UserID: userID}
+	}
+
+	pipe = l.client().TxPipeline()
+	rankCmds := make([]*redis.IntCmd, len(userIDs))
+	scoreCmds := make([]*redis.FloatCmd, len(userIDs))
+	remCmds := make([]*redis.IntCmd, len(userIDs))
+	for i, userID := range userIDs {
+		rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+		scoreCmds[i] = pipe.ZScore(ctx, l.leaderboardName, userID)
+		remCmds[i] = pipe.ZRem(ctx, l.leaderboardName, userID)
+
+		for _, tag := range tagsCmd.Val() {
+			pipe.SRem(ctx, l.tagKey(tag), userID)
+		}
+		l.removeRecord(ctx, pipe, userID)
+	}
+	// Shared profile may still be used by other boards
+	if !l.profiles.shared {
+		pipe.HDel(ctx, l.profiles.hashName, userIDs...)
+		for _, locale := range localesCmd.Val() {
+			pipe.HDel(ctx, l.profiles.localeHash(locale), userIDs...)
+		}
+	}
+	if l.freshness != nil {
+		pipe.ZRem(ctx, l.freshnessKey(), stringsToInterfaces(userIDs)...)
+	}
+	l.logOps(ctx, pipe, ScoreOpRemove, removed)
+	l.bumpVersion(ctx, pipe)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	l.membersRemoved(ctx, removed)
+
+	existed := make([]bool, len(userIDs))
+	events := make([]Event, 0, len(userIDs))
+	for i, userID := range userIDs {
+		existed[i] = remCmds[i].Val() > 0
+
+		if existed[i] && rankCmds[i].Err() == nil {
+			events = append(events, Event{
+				Type:     EventMemberRemoved,
+				UserID:   userID,
+				OldRank:  int(rankCmds[i].Val()) + 1,
+				OldScore: l.precision.whole(scoreCmds[i].Val()),
+				NewRank:  UnrankedMember,
+			})
+		}
+	}
+	l.emitAll(ctx, events)
+
+	return existed, nil
 }
 
-func (l *Leaderboard) IncrementMemberScore(userID string, incrementBy int) (user User, err error) {
-	newScore, err := incrementMemberScore(l.redisCli, l.leaderboardName, userID, incrementBy)
+func (l *Leaderboard) IncrementMemberScore(ctx context.Context, userID string, incrementBy int) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "IncrementMemberScore", userID)
 	if err != nil {
 		return User{}, err
 	}
+	defer done(&err)
+
+	user, _, err = l.incrementMemberScore(ctx, userID, incrementBy)
+
+	return user, err
+}
+
+// incrementMemberScore increments member's score by what's left of its earning
+// cap and returns member with how many points were credited.
+func (l *Leaderboard) incrementMemberScore(ctx context.Context, userID string, incrementBy int) (User, int, error) {
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, 0, err
+	}
+
+	before := l.memberBefore(ctx, userID)
 
-	rank, err := updateMemberRank(l.redisCli, l.leaderboardName, userID)
+	if incrementBy < 0 {
+		return User{}, 0, ErrIncrementByMustBePositiveInteger
+	}
+
+	floatScore, credited, err := l.runIncrement(ctx, l.leaderboardName, userID, incrementBy, l.scoreLogFlag())
 	if err != nil {
-		return User{}, err
+		return User{}, 0, err
+	}
+	l.scoreWritten(ctx, userID, floatScore)
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return User{}, 0, err
 	}
 
-	user = User{
-		UserID: userID,
-		Score:  newScore,
-		Rank:   rank,
+	user := l.scoredUser(userID, floatScore, rank)
+	l.emitScoreChange(ctx, before, user)
+
+	return user, int(credited), nil
+}
+
+// runIncrement runs incrementScoreScript on board zset and returns member's new
+// score and credited points.
+func (l *Leaderboard) runIncrement(ctx context.Context, board, userID string, incrementBy interface{}, logFlag string) (float64, float64, error) {
+	keys, args, earnedKey := l.appendEarningCap([]string{board, l.locksKey(), l.scoreLogKey(), l.metaKey()}, []interface{}{userID, incrementBy})
+	reply, err := incrementScoreScript.Run(ctx, l.client(), keys, append(args, logFlag)...).Result()
+	if err != nil {
+		return 0, 0, scriptError(err)
 	}
 
-	return user, nil
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return 0, 0, errUnexpectedScriptReply
+	}
+
+	scoreText, _ := res[0].(string)
+	score, err := parseScore(scoreText)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	creditedText, _ := res[1].(string)
+	credited, err := strconv.ParseFloat(creditedText, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if credited > 0 {
+		l.mirrorEarned(ctx, earnedKey, userID)
+	}
+
+	return score, credited, nil
+}
+
+func (l *Leaderboard) GetMemberInfo(ctx context.Context, userID string) (bytes []byte, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMemberInfo", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	return l.profiles.get(ctx, userID)
 }
 
-func (l *Leaderboard) GetMemberInfo(userID string) (bytes []byte, err error) {
-	return getMemberInfo(l.redisCli, l.userInfoHashName, userID)
+// Profiles returns store holding board's user info.
+func (l *Leaderboard) Profiles() *ProfileStore {
+	return l.profiles
 }
 
 type AdditionalUserInfo json.RawMessage
@@ -213,21 +523,66 @@ func (a *AdditionalUserInfo) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, a)
 }
 
-func (l *Leaderboard) UpsertMemberInfo(userID string, additionalData AdditionalUserInfo) error {
-	data, err := json.Marshal(&additionalData)
+func (l *Leaderboard) UpsertMemberInfo(ctx context.Context, userID string, additionalData AdditionalUserInfo) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "UpsertMemberInfo", userID)
 	if err != nil {
 		return err
 	}
+	defer done(&err)
 
-	if _, err := l.redisCli.HSet(ctx, l.userInfoHashName, userID, string(data)).Result(); err != nil {
+	value, err := l.profiles.upsert(ctx, userID, additionalData, l.queueBumpVersion(ctx))
+	if err != nil {
 		return err
 	}
+	l.mirrorInfo(ctx, userID, value)
+	l.mirrorVersion(ctx)
+	l.embeddedInfoUpserted(ctx, userID, additionalData)
 
 	return nil
 }
 
-func (l *Leaderboard) TotalMembers() (int, error) {
-	members, err := l.redisCli.ZCard(ctx, l.leaderboardName).Result()
+func (l *Leaderboard) TotalMembers(ctx context.Context) (total int, err error) {
+	ctx, done, err := l.startOp(ctx, "TotalMembers")
+	if err != nil {
+		return 0, err
+	}
+	defer done(&err)
+
+	if total, err = l.listedMembers(ctx); err != nil {
+		return 0, err
+	}
+
+	ghosts, err := l.countGhosts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return total - ghosts, nil
+}
+
+// listedMembers counts entries listed on the board, members and ghosts.
+func (l *Leaderboard) listedMembers(ctx context.Context) (total int, err error) {
+	if l.cacheReady(readOptions{}) {
+		if total, generation, ok := l.clientCache.totalMembers(); ok {
+			return total, nil
+		} else if total, err = l.countMembers(ctx); err == nil {
+			l.clientCache.storeTotal(generation, total)
+		}
+
+		return total, err
+	}
+
+	if l.countCache != nil {
+		return l.countCache.get(func() (int, error) {
+			return l.countMembers(ctx)
+		})
+	}
+
+	return l.countMembers(ctx)
+}
+
+func (l *Leaderboard) countMembers(ctx context.Context) (int, error) {
+	members, err := l.readClient(readOptions{}).ZCard(ctx, l.leaderboardName).Result()
 	if err != nil {
 		return 0, err
 	}
@@ -235,40 +590,140 @@ func (l *Leaderboard) TotalMembers() (int, error) {
 	return int(members), nil
 }
 
-func (l *Leaderboard) TotalPages() int {
-	pages := 0
+// CountPages returns number of pages. Unlike TotalPages it reports Redis errors.
+func (l *Leaderboard) CountPages(ctx context.Context) (_ int, err error) {
+	defer l.wrapError("CountPages", "", &err)
 
-	total, err := l.redisCli.ZCount(ctx, l.leaderboardName, "-inf", "+inf").Result()
-	if err == nil {
-		pages = int(math.Ceil(float64(total) / float64(l.PageSize)))
+	total, err := l.listedMembers(ctx)
+	if err != nil {
+		return 0, err
 	}
 
+	return l.pagesOf(total), nil
+}
+
+// TotalPages returns number of pages or 0 if it couldn't be counted, see CountPages.
+// Members are counted with ZCARD, see also ExactTotalPages and CachedTotalPages.
+func (l *Leaderboard) TotalPages(ctx context.Context) int {
+	pages, _ := l.CountPages(ctx)
+
 	return pages
 }
 
-func (l *Leaderboard) GetLeaders(page int) ([]User, error) {
+func (l *Leaderboard) GetLeaders(ctx context.Context, page int, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetLeaders")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
 	if page < 1 {
 		page = 1
 	}
 
-	if page > l.TotalPages() {
-		page = l.TotalPages()
+	o := newReadOptions(opts)
+	pageSize := l.pageSize(o)
+	page, startOffset, endOffset := l.pageOffsets(ctx, page, pageSize)
+
+	read := func() ([]User, error) {
+		return l.coalesceUsers(flightKey("leaders", strconv.Itoa(page), o), func() ([]User, error) {
+			return l.leadersPage(ctx, o, startOffset, endOffset)
+		})
+	}
+
+	// Cached pages are pages of board's own page size
+	if pageSize != l.PageSize || !l.cacheReady(o) {
+		if users, err = read(); err != nil {
+			return nil, err
+		}
+
+		return l.hideBanned(ctx, users)
+	}
+
+	cached, generation, ok := l.clientCache.page(page)
+	if ok {
+		return l.hideBanned(ctx, cached)
+	}
+
+	if users, err = read(); err != nil {
+		return nil, err
+	}
+	// Bans change without board's keys changing, so pages are cached
+	// unfiltered. Only complete pages are cached, they serve reads without info too
+	if !o.withoutInfo {
+		l.clientCache.storePage(page, generation, users)
+	}
+
+	return l.hideBanned(ctx, users)
+}
+
+// leadersPage reads members between 0-based offsets for GetLeaders.
+func (l *Leaderboard) leadersPage(ctx context.Context, o readOptions, startOffset, endOffset int) ([]User, error) {
+	if l.embeddedReads(o) {
+		return l.readRange(ctx, o, startOffset, endOffset, make([]User, 0, endOffset-startOffset+1))
+	}
+
+	cli := l.readClient(o)
+	if o.withoutInfo {
+		values, err := cli.ZRevRangeWithScores(ctx, l.rangeKey(o, endOffset), int64(startOffset), int64(endOffset)).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		users := appendUsers(make([]User, 0, len(values)), values, startOffset, l.precision)
+		if err := l.loadRecords(ctx, cli, users); err != nil {
+			return nil, err
+		}
+
+		return users, l.markGhosts(ctx, cli, users)
+	}
+
+	users, err := getMembersByRange(ctx, cli, l.rangeKey(o, endOffset), l.profiles, startOffset, endOffset, l.precision)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.loadRecords(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.hydrateInfo(ctx, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.markGhosts(ctx, cli, users); err != nil {
+		return nil, err
 	}
 
-	redisIndex := page - 1
+	return users, nil
+}
 
-	startOffset := redisIndex * l.PageSize
-	if startOffset < 0 {
-		startOffset = 0
+func (l *Leaderboard) pageSize(o readOptions) int {
+	if o.pageSize > 0 {
+		return o.pageSize
 	}
-	endOffset := (startOffset + l.PageSize) - 1
 
-	return getMembersByRange(l.redisCli, l.leaderboardName, l.userInfoHashName, startOffset, endOffset)
+	return l.PageSize
+}
+
+// pageOffsets returns page clamped to the last one with its 0-based start and
+// end offsets. If members can't be counted the requested page is used as is
+// instead of failing the whole request.
+func (l *Leaderboard) pageOffsets(ctx context.Context, page, pageSize int) (int, int, int) {
+	if total, err := l.listedMembers(ctx); err == nil && total > 0 {
+		if totalPages := int(math.Ceil(float64(total) / float64(pageSize))); page > totalPages {
+			page = totalPages
+		}
+	}
+
+	startOffset := (page - 1) * pageSize
+
+	return page, startOffset, startOffset + pageSize - 1
 }
 
 // Returns the rank of member in the sorted set stored at key,
 // with the scores ordered from high to low starting from one.
-func getMemberRank(redisCli *redis.Client, leaderboardName, userID string) (rank int, err error) {
+func getMemberRank(ctx context.Context, redisCli redis.UniversalClient, leaderboardName, userID string) (rank int, err error) {
 	rankInt64, err := redisCli.ZRevRank(ctx, leaderboardName, userID).Result()
 	if err != nil {
 		return 0, err
@@ -277,7 +732,7 @@ func getMemberRank(redisCli *redis.Client, leaderboardName, userID string) (rank
 	return int(rankInt64) + 1, nil
 }
 
-func updateMemberRank(redisCli *redis.Client, leaderboardName, userID string) (rank int, err error) {
+func updateMemberRank(ctx context.Context, redisCli redis.UniversalClient, leaderboardName, userID string) (rank int, err error) {
 	// Returns the rank of member in the sorted set stored at key, with the scores ordered from high to low.
 	// The rank (or index) is 0-based, which means that the member with the highest score has rank 0.
 	res, err := redisCli.ZRevRank(ctx, leaderboardName, userID).Result()
@@ -288,7 +743,7 @@ func updateMemberRank(redisCli *redis.Client, leaderboardName, userID string) (r
 	return int(res) + 1, nil
 }
 
-func getMemberScore(redisCli *redis.Client, leaderboardName, userID string) (score int, err error) {
+func getMemberScore(ctx context.Context, redisCli redis.UniversalClient, leaderboardName, userID string) (score int, err error) {
 	floatScore, err := redisCli.ZScore(ctx, leaderboardName, userID).Result()
 	if err != nil {
 		return 0, err
@@ -297,7 +752,7 @@ func getMemberScore(redisCli *redis.Client, leaderboardName, userID string) (sco
 	return int(floatScore), nil
 }
 
-func insertMemberScore(redisCli *redis.Client, leaderboardName, userID string, score int) error {
+func insertMemberScore(ctx context.Context, redisCli redis.UniversalClient, leaderboardName, userID string, score int) error {
 	member := &redis.Z{
 		Score:  float64(score),
 		Member: userID,
@@ -311,7 +766,7 @@ func insertMemberScore(redisCli *redis.Client, leaderboardName, userID string, s
 	return nil
 }
 
-func incrementMemberScore(redisCli *redis.Client, leaderboardName, userID string, incrementBy int) (newScore int, err error) {
+func incrementMemberScore(ctx context.Context, redisCli redis.UniversalClient, leaderboardName, userID string, incrementBy int) (newScore int, err error) {
 	if incrementBy < 0 {
 		return 0, ErrIncrementByMustBePositiveInteger
 	}
@@ -324,78 +779,34 @@ func incrementMemberScore(redisCli *redis.Client, leaderboardName, userID string
 	return int(res), nil
 }
 
-func getMembersByRange(redisCli *redis.Client, leaderboard, userInfoHashName string, startOffset int, endOffset int) ([]User, error) {
+func getMembersByRange(ctx context.Context, redisCli redis.UniversalClient, leaderboard string, profiles *ProfileStore, startOffset int, endOffset int, precision Precision) ([]User, error) {
 	values, err := redisCli.ZRevRangeWithScores(ctx, leaderboard, int64(startOffset), int64(endOffset)).Result()
 	if err != nil {
 		return nil, err
 	}
 
-	fatalErrors := make(chan error)
-	wgDone := make(chan bool)
-
-	var users []User
-	var wg sync.WaitGroup
-
-	for i := range values {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			userID := values[i].Member.(string)
-
-			rank, err := getMemberRank(redisCli, leaderboard, userID)
-			if err != nil {
-				fatalErrors <- err
-				return
-			}
-
-			score, err := getMemberScore(redisCli, leaderboard, userID)
-			if err != nil {
-				fatalErrors <- err
-				return
-			}
-
-			additionalInfo, err := getMemberInfo(redisCli, userInfoHashName, userID)
-			if err != nil && !errors.Is(err, redis.Nil) {
-				fatalErrors <- err
-				return
-			}
-
-			user := User{
-				UserID:         userID,
-				Score:          score,
-				Rank:           rank,
-				AdditionalInfo: additionalInfo,
-			}
-
-			users = append(users, user)
-		}(i)
+	// Range comes with scores and ranks follow from the offset, so only info
+	// is read afterwards, in one pipelined round trip
+	users := appendUsers(make([]User, 0, len(values)), values, startOffset, precision)
+	if err := profiles.load(ctx, redisCli, users); err != nil {
+		return nil, err
 	}
 
-	go func() {
-		wg.Wait()
-		close(wgDone)
-	}()
+	return users, nil
+}
 
-	select {
-	case <-wgDone:
-		break
-	case err := <-fatalErrors:
+func getMemberInfo(ctx context.Context, redisCli redis.UniversalClient, userInfoHashName, userID string, keyring *Keyring) ([]byte, error) {
+	storedData, err := redisCli.HGet(ctx, userInfoHashName, userID).Result()
+	if err != nil {
 		return nil, err
 	}
 
-	if len(users) == 0 {
-		users = []User{}
-	}
-
-	sort.Slice(users, func(i, j int) bool {
-		return users[i].Rank < users[j].Rank
-	})
-
-	return users, nil
+	return decodeMemberInfo(storedData, keyring)
 }
 
-func getMemberInfo(redisCli *redis.Client, userInfoHashName, userID string) ([]byte, error) {
-	stringifiedData, err := redisCli.HGet(ctx, userInfoHashName, userID).Result()
+// decodeMemberInfo converts value stored in user info hash back to raw JSON
+func decodeMemberInfo(storedData string, keyring *Keyring) ([]byte, error) {
+	stringifiedData, err := decryptMemberInfo(keyring, storedData)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,200 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"testing"
+)
+
+// newTestBoard returns board "test" on top of fresh miniredis.
+func newTestBoard(t *testing.T, opts ...Option) (*Leaderboard, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = cli.Close()
+	})
+
+	l, err := NewLeaderboardWithClient(cli, StagingMode, "test", "test_info", 10, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Shutdown(context.Background())
+	})
+
+	return l, mr
+}
+
+// seed sets scores of members given as userID, score pairs.
+func seed(t *testing.T, l *Leaderboard, scores ...interface{}) {
+	t.Helper()
+
+	for i := 0; i < len(scores); i += 2 {
+		z := &redis.Z{Member: scores[i], Score: float64(scores[i+1].(int))}
+		if err := l.client().ZAdd(context.Background(), l.leaderboardName, z).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// userIDs returns IDs of users in order.
+func userIDs(users []User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.UserID
+	}
+
+	return ids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestIncrementMemberScore(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	if _, err := l.IncrementMemberScore(ctx, "a", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := l.IncrementMemberScore(ctx, "b", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Score != 7 || user.Rank != 1 {
+		t.Fatalf("got score %d rank %d, want 7 and 1", user.Score, user.Rank)
+	}
+
+	if _, err := l.IncrementMemberScore(ctx, "a", -1); !errors.Is(err, ErrIncrementByMustBePositiveInteger) {
+		t.Fatalf("got %v, want ErrIncrementByMustBePositiveInteger", err)
+	}
+}
+
+func TestFirstOrInsertMemberKeepsExistingScore(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	if _, err := l.FirstOrInsertMember(ctx, "a", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := l.FirstOrInsertMember(ctx, "a", 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Score != 10 || user.Rank != 1 {
+		t.Fatalf("got score %d rank %d, want 10 and 1", user.Score, user.Rank)
+	}
+}
+
+func TestRemoveMembers(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 1, "b", 2)
+
+	if err := l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"A"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	existed, err := l.RemoveMembers(ctx, "a", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !existed[0] || existed[1] {
+		t.Fatalf("got %v, want [true false]", existed)
+	}
+
+	if n := l.client().HExists(ctx, "test_info", "a").Val(); n {
+		t.Fatal("info of removed member was kept")
+	}
+
+	users, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"b"}) {
+		t.Fatalf("got %v, want [b]", userIDs(users))
+	}
+}
+
+func TestRemoveMembersRemovesCompanionData(t *testing.T) {
+	l, _ := newTestBoard(t, WithRecords(), WithEvents(100))
+	ctx := context.Background()
+
+	if _, err := l.RecordResult(ctx, "a", OutcomeWin, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.TagMember(ctx, "a", "cheater"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.UpsertMemberInfoLocalized(ctx, "a", "de", AdditionalUserInfo(`{"name":"A"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.RemoveMembers(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := l.client()
+	if cli.Exists(ctx, l.recordsKey(), l.tagKey("cheater"), l.profiles.localeHash("de")).Val() != 0 {
+		t.Fatal("companion data of removed member was kept")
+	}
+
+	events, err := l.Events(ctx, "-", "+", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := events[len(events)-1]
+	if last.Type != EventMemberRemoved || last.OldRank != 1 || last.OldScore != 5 {
+		t.Fatalf("got %+v, want member_removed from rank 1 with score 5", last)
+	}
+}
+
+func TestGetLeadersWithoutInfo(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t, WithGhosts())
+	seed(t, l, "a", 10, "b", 8)
+	if err := l.AddGhost(ctx, "g", 9, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"Al"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	withInfo, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutInfo, err := l.GetLeaders(ctx, 1, WithoutInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(withInfo) != 3 || len(withoutInfo) != 3 {
+		t.Fatalf("got %d and %d members, want 3", len(withInfo), len(withoutInfo))
+	}
+	for i := range withInfo {
+		if withInfo[i].UserID != withoutInfo[i].UserID || withInfo[i].Rank != withoutInfo[i].Rank || withInfo[i].Ghost != withoutInfo[i].Ghost {
+			t.Fatalf("got %+v without info, want %+v", withoutInfo[i], withInfo[i])
+		}
+	}
+	if withInfo[0].AdditionalInfo == nil || withoutInfo[0].AdditionalInfo != nil {
+		t.Fatalf("got info %s and %s, want it read only when asked for", withInfo[0].AdditionalInfo, withoutInfo[0].AdditionalInfo)
+	}
+}
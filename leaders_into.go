@@ -0,0 +1,100 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+)
+
+// GetLeadersInto is GetLeaders decoding page into buf, for hot paths serving
+// many pages per second. Users are appended to buf[:0] and the resulting slice
+// is returned, so reusing it across calls (e.g. from a sync.Pool) avoids
+// allocating a new page every time. Ranks are derived from page offset and
+// info is fetched in one pipelined round trip.
+func (l *Leaderboard) GetLeadersInto(ctx context.Context, page int, buf []User, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetLeadersInto")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if page < 1 {
+		page = 1
+	}
+
+	o := newReadOptions(opts)
+	pageSize := l.pageSize(o)
+	page, startOffset, endOffset := l.pageOffsets(ctx, page, pageSize)
+
+	cacheable := pageSize == l.PageSize && l.cacheReady(o)
+
+	var generation uint64
+	if cacheable {
+		var ok bool
+		if users, generation, ok = l.clientCache.pageInto(page, buf); ok {
+			return l.hideBanned(ctx, users)
+		}
+	}
+
+	if users, err = l.readRange(ctx, o, startOffset, endOffset, buf); err != nil {
+		return users, err
+	}
+
+	// Bans change without board's keys changing, so pages are cached unfiltered
+	if cacheable {
+		l.clientCache.storePage(page, generation, users)
+	}
+
+	return l.hideBanned(ctx, users)
+}
+
+// readRange reads members (with info) between 0-based offsets into buf[:0].
+// Boards with embedded info are read from their display set.
+func (l *Leaderboard) readRange(ctx context.Context, o readOptions, startOffset, endOffset int, buf []User) ([]User, error) {
+	cli := l.readClient(o)
+
+	key := l.rangeKey(o, endOffset)
+	if l.embeddedReads(o) {
+		key = l.displayKey()
+	}
+
+	values, err := cli.ZRevRangeWithScores(ctx, key, int64(startOffset), int64(endOffset)).Result()
+	if err != nil {
+		return buf[:0], err
+	}
+
+	var users []User
+	if l.embeddedReads(o) {
+		users = l.appendEmbeddedUsers(buf[:0], values, startOffset)
+	} else {
+		users = appendUsers(buf[:0], values, startOffset, l.precision)
+		if err := l.profiles.load(ctx, cli, users); err != nil {
+			return users[:0], err
+		}
+	}
+
+	if err := l.loadRecords(ctx, cli, users); err != nil {
+		return users[:0], err
+	}
+
+	if err := l.hydrateInfo(ctx, users); err != nil {
+		return users[:0], err
+	}
+
+	// Ghosts aren't kept in display set
+	if !l.embeddedReads(o) {
+		if err := l.markGhosts(ctx, cli, users); err != nil {
+			return users[:0], err
+		}
+	}
+
+	return users, nil
+}
+
+// appendUsers decodes range of the board starting at 0-based offset into buf.
+func appendUsers(buf []User, values []redis.Z, offset int, precision Precision) []User {
+	for i, z := range values {
+		buf = append(buf, precision.user(z.Member.(string), z.Score, offset+i+1))
+	}
+
+	return buf
+}
@@ -0,0 +1,131 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"math"
+	"time"
+)
+
+const (
+	DefaultLiveBufferSize = 100
+
+	liveBlockTimeout = 5 * time.Second
+	liveRetryDelay   = time.Second
+)
+
+var (
+	ErrEventsDisabled = errors.New("leaderboard: events aren't enabled for the board, see WithEvents")
+)
+
+// LiveOptions configures Live.
+type LiveOptions struct {
+	// FromRank and ToRank limit feed to events affecting ranks within the window
+	// (inclusive), e.g. 1 and 20 for broadcast overlay showing top 20. 0 leaves
+	// that side unbounded.
+	FromRank int
+	ToRank   int
+	// StartID is ID of event feed starts after, only new events are sent by default
+	StartID string
+	// BufferSize is capacity of returned channel, DefaultLiveBufferSize by default
+	BufferSize int
+}
+
+// affects reports whether event changes visible slice of the board between
+// FromRank and ToRank: member entered, left or moved within the window, or
+// passed through it and shifted members in it.
+func (o LiveOptions) affects(event Event) bool {
+	from, to := float64(o.FromRank), float64(o.ToRank)
+	if o.FromRank <= 0 {
+		from = 1
+	}
+	if o.ToRank <= 0 {
+		to = math.Inf(1)
+	}
+
+	// Unranked members are below everyone
+	rank := func(r int) float64 {
+		if r == UnrankedMember {
+			return math.Inf(1)
+		}
+
+		return float64(r)
+	}
+
+	low, high := math.Min(rank(event.OldRank), rank(event.NewRank)), math.Max(rank(event.OldRank), rank(event.NewRank))
+
+	return low <= to && high >= from
+}
+
+// Live streams board's events as they happen until ctx is done, then the
+// channel is closed. With rank window set only events affecting that slice of
+// the board are sent, so overlays don't have to filter the full firehose.
+// Events must be enabled with WithEvents.
+func (l *Leaderboard) Live(ctx context.Context, opts LiveOptions) (_ <-chan Event, err error) {
+	defer l.wrapError("Live", "", &err)
+
+	if !l.eventsEnabled() {
+		return nil, ErrEventsDisabled
+	}
+
+	if opts.StartID == "" {
+		// Resolve "$" now, so events emitted before first read aren't missed
+		last, err := l.client().XRevRangeN(ctx, l.eventsStream(), "+", "-", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		opts.StartID = "0-0"
+		if len(last) > 0 {
+			opts.StartID = last[0].ID
+		}
+	}
+
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultLiveBufferSize
+	}
+
+	events := make(chan Event, opts.BufferSize)
+	go l.feed(ctx, opts, events)
+
+	return events, nil
+}
+
+func (l *Leaderboard) feed(ctx context.Context, opts LiveOptions, events chan<- Event) {
+	defer close(events)
+
+	lastID := opts.StartID
+	for ctx.Err() == nil {
+		streams, err := l.client().XRead(ctx, &redis.XReadArgs{
+			Streams: []string{l.eventsStream(), lastID},
+			Block:   liveBlockTimeout,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				select {
+				case <-ctx.Done():
+				case <-time.After(liveRetryDelay):
+				}
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				lastID = msg.ID
+
+				event := eventFromMessage(msg)
+				if !opts.affects(event) {
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
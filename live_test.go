@@ -0,0 +1,66 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLiveOptionsAffects(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		old, new int
+		want     bool
+	}{
+		{"moved within window", 1, 20, 5, 3, true},
+		{"entered window", 1, 20, 25, 18, true},
+		{"left window", 1, 20, 18, 25, true},
+		{"passed through window", 10, 20, 25, 5, true},
+		{"below window", 1, 20, 30, 25, false},
+		{"above window", 10, 20, 3, 1, false},
+		{"new member below window", 1, 20, UnrankedMember, 25, false},
+		{"new member within window", 1, 20, UnrankedMember, 2, true},
+		{"removed from window", 1, 20, 2, UnrankedMember, true},
+		{"unbounded", 0, 0, 1000, 999, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := LiveOptions{FromRank: tt.from, ToRank: tt.to}
+			if got := opts.affects(Event{OldRank: tt.old, NewRank: tt.new}); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLiveSendsEventsWithinRankWindow(t *testing.T) {
+	l, _ := newTestBoard(t, WithEvents(0))
+	seed(t, l, "a", 1, "b", 5, "c", 3, "d", 10, "e", 9)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := l.Live(ctx, LiveOptions{FromRank: 1, ToRank: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "a" stays last, "c" jumps to the top
+	if _, err := l.IncrementMemberScore(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.IncrementMemberScore(ctx, "c", 20); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.UserID != "c" || event.OldRank != 4 || event.NewRank != 1 {
+			t.Fatalf("got %+v, want c moving from 4 to 1", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+}
@@ -0,0 +1,162 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strings"
+)
+
+var (
+	ErrInvalidLocale = errors.New("leaderboard: locale must be non-empty and must not contain ':'")
+)
+
+// localeHash is hash holding info for one locale, e.g. "users_info:pt-BR".
+func (p *ProfileStore) localeHash(locale string) string {
+	return p.hashName + ":" + locale
+}
+
+// localesKey is set of all locales info was stored for.
+func (p *ProfileStore) localesKey() string {
+	return p.hashName + ":locales"
+}
+
+// UpsertLocalized stores user's info for given locale (e.g. "de" or "pt-BR").
+func (p *ProfileStore) UpsertLocalized(ctx context.Context, userID, locale string, additionalData AdditionalUserInfo) error {
+	_, err := p.upsertLocalized(ctx, userID, locale, additionalData, nil)
+
+	return err
+}
+
+func (p *ProfileStore) upsertLocalized(ctx context.Context, userID, locale string, additionalData AdditionalUserInfo, also func(pipe redis.Pipeliner)) (string, error) {
+	if locale == "" || strings.Contains(locale, ":") {
+		return "", ErrInvalidLocale
+	}
+
+	if err := p.validate(additionalData); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(&additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := encryptMemberInfo(p.keyring, string(data))
+	if err != nil {
+		return "", err
+	}
+
+	pipe := p.client().TxPipeline()
+	pipe.HSet(ctx, p.localeHash(locale), userID, value)
+	pipe.SAdd(ctx, p.localesKey(), locale)
+	if also != nil {
+		also(pipe)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// GetLocalized returns user's info for locale, falling back to base language
+// ("pt-BR" -> "pt") and then to non-localized info. redis.Nil is returned if
+// user has no info at all.
+func (p *ProfileStore) GetLocalized(ctx context.Context, userID, locale string) ([]byte, error) {
+	chain := localeFallbacks(locale)
+
+	pipe := p.client().Pipeline()
+	cmds := make([]*redis.StringCmd, 0, len(chain)+1)
+	for _, l := range chain {
+		cmds = append(cmds, pipe.HGet(ctx, p.localeHash(l), userID))
+	}
+	cmds = append(cmds, pipe.HGet(ctx, p.hashName, userID))
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		storedData, err := cmd.Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		return decodeMemberInfo(storedData, p.keyring)
+	}
+
+	return nil, redis.Nil
+}
+
+// Locales returns all locales info was stored for.
+func (p *ProfileStore) Locales(ctx context.Context) ([]string, error) {
+	return p.client().SMembers(ctx, p.localesKey()).Result()
+}
+
+// deleteLocalized removes user's info in all locales.
+func (p *ProfileStore) deleteLocalized(ctx context.Context, userID string) error {
+	locales, err := p.Locales(ctx)
+	if err != nil || len(locales) == 0 {
+		return err
+	}
+
+	pipe := p.client().Pipeline()
+	for _, locale := range locales {
+		pipe.HDel(ctx, p.localeHash(locale), userID)
+	}
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// UpsertMemberInfoLocalized stores member's info for given locale.
+func (l *Leaderboard) UpsertMemberInfoLocalized(ctx context.Context, userID, locale string, additionalData AdditionalUserInfo) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "UpsertMemberInfoLocalized", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	value, err := l.profiles.upsertLocalized(ctx, userID, locale, additionalData, l.queueBumpVersion(ctx))
+	if err != nil {
+		return err
+	}
+	l.mirrorLocalizedInfo(ctx, userID, locale, value)
+	l.mirrorVersion(ctx)
+
+	return nil
+}
+
+// GetMemberInfoLocalized returns member's info for locale with fallback, see ProfileStore.GetLocalized.
+func (l *Leaderboard) GetMemberInfoLocalized(ctx context.Context, userID, locale string) (info []byte, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMemberInfoLocalized", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	return l.profiles.GetLocalized(ctx, userID, locale)
+}
+
+// localeFallbacks returns locale followed by its more generic variants,
+// e.g. "zh-Hant-TW" -> ["zh-Hant-TW", "zh-Hant", "zh"].
+func localeFallbacks(locale string) []string {
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	return chain
+}
@@ -0,0 +1,128 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strings"
+)
+
+var (
+	ErrMemberScoreLocked = errors.New("leaderboard: member's score is locked")
+)
+
+// lockedReply is prefix of error reply of write scripts for locked members.
+const lockedReply = "LOCKED"
+
+// Increments member's score by what's left of earning cap unless it's locked
+// (KEYS[2]), submissions are closed or new member doesn't fit member cap
+// (KEYS[4]) and returns {newScore, credited}.
+var incrementScoreScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], ARGV[1]) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+local credited = credit(ARGV[1], tonumber(ARGV[2]))
+local newScore = redis.call("ZINCRBY", KEYS[1], credited, ARGV[1])
+if credited ~= 0 then
+	logOp(KEYS[3], "incr", ARGV[1], credited)
+end
+bumpVersion(KEYS[4])
+
+return {newScore, tostring(credited)}
+`)
+
+// locksKey is hash of locked members and reasons they were locked for.
+func (l *Leaderboard) locksKey() string {
+	return l.leaderboardName + ":locks"
+}
+
+// LockMemberScore blocks score changes of member (increments, submissions and
+// CAS updates fail with ErrMemberScoreLocked), e.g. while suspicious scores are
+// investigated. Lock is checked atomically with the write on the server, so
+// all instances honour it. Member stays on the board with its current score.
+//
+// Inserting a member which isn't on the board yet (FirstOrInsertMember) isn't
+// blocked, nor are maintenance operations like Rebuild or BulkLoad.
+func (l *Leaderboard) LockMemberScore(ctx context.Context, userID, reason string) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "LockMemberScore", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if err := l.client().HSet(ctx, l.locksKey(), userID, reason).Err(); err != nil {
+		return err
+	}
+	l.mirrorHashField(ctx, l.locksKey(), userID, reason)
+
+	return nil
+}
+
+// UnlockMemberScore allows score changes of member again.
+func (l *Leaderboard) UnlockMemberScore(ctx context.Context, userID string) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "UnlockMemberScore", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	write := func(cli redis.UniversalClient) error {
+		return cli.HDel(ctx, l.locksKey(), userID).Err()
+	}
+
+	if err := write(l.client()); err != nil {
+		return err
+	}
+	l.mirrorWrite(write)
+
+	return nil
+}
+
+// MemberScoreLock returns reason member's score was locked for and whether it's locked.
+func (l *Leaderboard) MemberScoreLock(ctx context.Context, userID string) (reason string, locked bool, err error) {
+	defer l.wrapError("MemberScoreLock", userID, &err)
+
+	reason, err = l.client().HGet(ctx, l.locksKey(), userID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+
+		return "", false, err
+	}
+
+	return reason, true, nil
+}
+
+// LockedMembers returns all locked members with reasons they were locked for.
+func (l *Leaderboard) LockedMembers(ctx context.Context) (_ map[string]string, err error) {
+	defer l.wrapError("LockedMembers", "", &err)
+
+	return l.client().HGetAll(ctx, l.locksKey()).Result()
+}
+
+// scriptError converts error replies of write scripts to ErrMemberScoreLocked,
+// ErrSubmissionsClosed and ErrBoardFull.
+func scriptError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case strings.HasPrefix(err.Error(), lockedReply):
+		return ErrMemberScoreLocked
+	case strings.HasPrefix(err.Error(), closedReply):
+		return ErrSubmissionsClosed
+	case strings.HasPrefix(err.Error(), fullReply):
+		return ErrBoardFull
+	}
+
+	return err
+}
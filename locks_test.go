@@ -0,0 +1,93 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLockedScoreRejectsWrites(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		write func(l *Leaderboard) error
+	}{
+		{"IncrementMemberScore", nil, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScore(ctx, "a", 1)
+			return err
+		}},
+		{"IncrementMemberScoreFrom", nil, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScoreFrom(ctx, "a", 1, "quests")
+			return err
+		}},
+		{"IncrementMemberScoreWithOvertaken", nil, func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScoreWithOvertaken(ctx, "a", 1, 1)
+			return err
+		}},
+		{"DecrementMemberScore", nil, func(l *Leaderboard) error {
+			_, err := l.DecrementMemberScore(ctx, "a", 1)
+			return err
+		}},
+		{"SubmitScore", []Option{WithScoringMode(ScoringLatest)}, func(l *Leaderboard) error {
+			_, err := l.SubmitScore(ctx, "a", 1)
+			return err
+		}},
+		{"UpdateScoreCAS", nil, func(l *Leaderboard) error {
+			return l.UpdateScoreCAS(ctx, "a", 5, 1)
+		}},
+		{"RecordResult", []Option{WithRecords()}, func(l *Leaderboard) error {
+			_, err := l.RecordResult(ctx, "a", OutcomeWin, 1)
+			return err
+		}},
+		{"SubmitMatchResults", nil, func(l *Leaderboard) error {
+			_, err := l.SubmitMatchResults(ctx, []MatchResult{{UserID: "b", Points: 1}, {UserID: "a", Points: 1}})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t, tt.opts...)
+			seed(t, l, "a", 5, "b", 5)
+
+			if err := l.LockMemberScore(ctx, "a", "under review"); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(l); !errors.Is(err, ErrMemberScoreLocked) {
+				t.Fatalf("got %v, want ErrMemberScoreLocked", err)
+			}
+			for _, userID := range []string{"a", "b"} {
+				if score := l.client().ZScore(ctx, l.leaderboardName, userID).Val(); score != 5 {
+					t.Fatalf("got score %v of %s, want it unchanged", score, userID)
+				}
+			}
+
+			if err := l.UnlockMemberScore(ctx, "a"); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(l); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMemberScoreLock(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	if err := l.LockMemberScore(ctx, "a", "under review"); err != nil {
+		t.Fatal(err)
+	}
+
+	reason, locked, err := l.MemberScoreLock(ctx, "a")
+	if err != nil || !locked || reason != "under review" {
+		t.Fatalf("got %q, %v, %v, want lock with reason", reason, locked, err)
+	}
+
+	if _, locked, _ := l.MemberScoreLock(ctx, "b"); locked {
+		t.Fatal("got b locked, want it unlocked")
+	}
+}
@@ -0,0 +1,250 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"sync"
+)
+
+// Manager owns one Redis connection and one ProfileStore shared by all
+// leaderboards created through it, which is the usual "one profile store,
+// many boards" layout.
+type Manager struct {
+	RedisSettings RedisSettings
+	mode          string
+	redisCli      redis.UniversalClient
+	profiles      *ProfileStore
+
+	mu     sync.RWMutex
+	boards map[string]*Leaderboard
+}
+
+// NewManager is constructor for Manager. User info of all boards is stored in
+// userInfoStorageHash, encrypted with keyring unless keyring is nil.
+func NewManager(redisSettings RedisSettings, mode, userInfoStorageHash string, keyring *Keyring) *Manager {
+	mode, profile := resolveMode(mode)
+
+	m := &Manager{
+		RedisSettings: redisSettings,
+		mode:          mode,
+		redisCli:      connectToRedis(redisSettings, profile.PoolSize),
+		boards:        map[string]*Leaderboard{},
+	}
+	m.profiles = &ProfileStore{
+		hashName: userInfoStorageHash,
+		keyring:  keyring,
+		client:   m.client,
+		shared:   true,
+	}
+
+	return m
+}
+
+func (m *Manager) client() redis.UniversalClient {
+	return m.redisCli
+}
+
+// Profiles returns store shared by all boards of the manager.
+func (m *Manager) Profiles() *ProfileStore {
+	return m.profiles
+}
+
+// Leaderboard returns board named leaderboardName, creating it on first call.
+// pageSize and opts are used only when board is created. Invalid pageSize is
+// rejected with ErrInvalidPageSize in modes with StrictValidation.
+func (m *Manager) Leaderboard(leaderboardName string, pageSize int, opts ...Option) (*Leaderboard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l, ok := m.boards[leaderboardName]; ok {
+		return l, nil
+	}
+
+	_, profile := resolveMode(m.mode)
+	pageSize, err := resolvePageSize(pageSize, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := newLeaderboard(m.redisCli, m.mode, leaderboardName, pageSize, "", m.profiles, opts)
+	if err != nil {
+		return nil, err
+	}
+	l.RedisSettings = m.RedisSettings
+	m.boards[leaderboardName] = l
+
+	return l, nil
+}
+
+// Boards returns all boards created through the manager, keyed by name.
+func (m *Manager) Boards() map[string]*Leaderboard {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	boards := make(map[string]*Leaderboard, len(m.boards))
+	for name, l := range m.boards {
+		boards[name] = l
+	}
+
+	return boards
+}
+
+// BoardNames returns sorted names of all boards created through the manager.
+func (m *Manager) BoardNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.boards))
+	for name := range m.boards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RemovalResult is outcome of RemoveMemberEverywhere on one board.
+type RemovalResult struct {
+	// Removed is true when member was on the board
+	Removed bool
+	// Rank and Score member had before removal
+	Rank  int
+	Score int
+	Err   error
+}
+
+// RemoveMemberEverywhere removes user from all boards of the manager in one
+// pipeline and deletes their shared profile, for account deletion and ban
+// workflows. Results are keyed by board name, failure on one board doesn't
+// stop removal from the others; err reports failure to start the removal or
+// to delete the profile.
+func (m *Manager) RemoveMemberEverywhere(ctx context.Context, userID string) (results map[string]RemovalResult, err error) {
+	boards := m.Boards()
+	names := m.BoardNames()
+
+	// Tag names are needed up front, to remove member from tag sets in the same pipeline
+	pipe := m.client().Pipeline()
+	tagCmds := make(map[string]*redis.StringSliceCmd, len(names))
+	for _, name := range names {
+		tagCmds[name] = pipe.SMembers(ctx, boards[name].tagsKey())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	type removal struct {
+		rank  *redis.IntCmd
+		score *redis.FloatCmd
+		rem   *redis.IntCmd
+	}
+
+	pipe = m.client().TxPipeline()
+	removals := make(map[string]removal, len(names))
+	for _, name := range names {
+		l := boards[name]
+		removals[name] = removal{
+			rank:  pipe.ZRevRank(ctx, l.leaderboardName, userID),
+			score: pipe.ZScore(ctx, l.leaderboardName, userID),
+			rem:   pipe.ZRem(ctx, l.leaderboardName, userID),
+		}
+		for _, tag := range tagCmds[name].Val() {
+			pipe.SRem(ctx, l.tagKey(tag), userID)
+		}
+		l.logOps(ctx, pipe, ScoreOpRemove, []MemberScore{{UserID: userID}})
+		l.bumpVersion(ctx, pipe)
+	}
+	// Errors are reported per board below
+	_, _ = pipe.Exec(ctx)
+
+	results = make(map[string]RemovalResult, len(names))
+	for _, name := range names {
+		l, r := boards[name], removals[name]
+
+		result := RemovalResult{Rank: UnrankedMember}
+		if err := r.rem.Err(); err != nil {
+			result.Err = err
+			results[name] = result
+			continue
+		}
+
+		result.Removed = r.rem.Val() > 0
+		if result.Removed && !errors.Is(r.rank.Err(), redis.Nil) {
+			result.Rank = int(r.rank.Val()) + 1
+			result.Score = l.precision.whole(r.score.Val())
+		}
+		results[name] = result
+
+		l.memberRemoved(ctx, userID)
+		if result.Removed {
+			l.emit(ctx, Event{Type: EventMemberRemoved, UserID: userID, OldRank: result.Rank, OldScore: result.Score, NewRank: UnrankedMember})
+		}
+	}
+
+	if err := m.profiles.delete(ctx, userID); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// GetLeadersMulti returns top topN members (with info) of every board in
+// boardNames, keyed by board name, reading all boards in one pipeline and info
+// of all members in another, for home screens showing several mini
+// leaderboards. Boards don't have to be created through the manager.
+func (m *Manager) GetLeadersMulti(ctx context.Context, boardNames []string, topN int) (leaders map[string][]User, err error) {
+	leaders = make(map[string][]User, len(boardNames))
+	if topN <= 0 {
+		for _, name := range boardNames {
+			leaders[name] = []User{}
+		}
+
+		return leaders, nil
+	}
+
+	pipe := m.client().Pipeline()
+	cmds := make(map[string]*redis.ZSliceCmd, len(boardNames))
+	for _, name := range boardNames {
+		cmds[name] = pipe.ZRevRangeWithScores(ctx, name, 0, int64(topN-1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	boards := m.Boards()
+	var all []User
+	for _, name := range boardNames {
+		// Boards not created through the manager use the default precision
+		var precision Precision
+		if l, ok := boards[name]; ok {
+			precision = l.precision
+		}
+		all = appendUsers(all, cmds[name].Val(), 0, precision)
+	}
+
+	if err := m.profiles.load(ctx, m.client(), all); err != nil {
+		return nil, err
+	}
+
+	for _, name := range boardNames {
+		n := len(cmds[name].Val())
+		users := make([]User, n)
+		copy(users, all[:n])
+		all = all[n:]
+
+		if l, ok := boards[name]; ok {
+			if err := l.loadRecords(ctx, m.client(), users); err != nil {
+				return nil, err
+			}
+		}
+		leaders[name] = users
+	}
+
+	return leaders, nil
+}
+
+// Close closes connection shared by all boards of the manager.
+func (m *Manager) Close() error {
+	return m.redisCli.Close()
+}
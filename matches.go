@@ -0,0 +1,275 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+var (
+	ErrTeamRollupDisabled = errors.New("leaderboard: team rollup isn't enabled for the board, see WithTeamRollup")
+)
+
+// MatchResult is one player's result of a match, see SubmitMatchResults.
+type MatchResult struct {
+	UserID string
+	// Points is change of player's score, negative for lost points
+	Points int
+	// Outcome is OutcomeWin, OutcomeLoss or OutcomeDraw counted in player's
+	// record (see WithRecords), empty when the match isn't counted
+	Outcome string
+	// TeamID is team the points are credited to (see WithTeamRollup), empty for none
+	TeamID string
+}
+
+// Applies match results to board KEYS[1] unless any player's score is locked,
+// submissions are closed or member cap doesn't admit all new players, no
+// player being evicted for another. ARGV[1] is number of results, ARGV[2] is
+// "1" for zero floor, and every result is given by 5 following args: userID,
+// points, outcome, teamID and index of team's contributions hash among KEYS (0
+// for none), team totals being in KEYS[6] with metadata in KEYS[7]. Outcomes
+// are counted in KEYS[5]. Gained points are credited at most what's left of
+// earning cap. Returns {newScore, teamScore, contribution} for every result,
+// team values are false without team.
+var submitMatchScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+local n = tonumber(ARGV[1])
+local floor = ARGV[2] == "1"
+
+for i = 0, n - 1 do
+	if redis.call("HEXISTS", KEYS[2], ARGV[3 + 5 * i]) == 1 then
+		return redis.error_reply("LOCKED member's score is locked")
+	end
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+local players = {}
+for i = 0, n - 1 do
+	players[#players + 1] = ARGV[3 + 5 * i]
+end
+
+if not admitAll(KEYS[1], KEYS[4], KEYS[3], players) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+local results, teams = {}, false
+for i = 0, n - 1 do
+	local userID, points, outcome = ARGV[3 + 5 * i], tonumber(ARGV[4 + 5 * i]), ARGV[5 + 5 * i]
+	local teamID, team = ARGV[6 + 5 * i], tonumber(ARGV[7 + 5 * i])
+
+	if floor and points < 0 then
+		local score = tonumber(redis.call("ZSCORE", KEYS[1], userID) or "0")
+		points = math.max(points, -math.max(score, 0))
+	end
+	points = credit(userID, points)
+
+	local result = {redis.call("ZINCRBY", KEYS[1], points, userID), false, false}
+	logOp(KEYS[3], "incr", userID, points)
+
+	if outcome ~= "" then
+		redis.call("HINCRBY", KEYS[5], userID .. ":" .. outcome, 1)
+	end
+
+	if team > 0 then
+		result[2] = redis.call("ZINCRBY", KEYS[6], points, teamID)
+		result[3] = redis.call("HINCRBY", KEYS[team], userID, points)
+		teams = true
+	end
+
+	results[#results + 1] = result
+end
+
+bumpVersion(KEYS[4])
+if teams then
+	bumpVersion(KEYS[7])
+end
+
+return results
+`)
+
+// WithTeamRollup credits points of match results with TeamID to team on
+// teams board, see SubmitMatchResults. Teams board must live on the same Redis
+// (same hash slot with Redis Cluster) as the board.
+func WithTeamRollup(teams *TeamLeaderboard) Option {
+	return func(l *Leaderboard) {
+		l.teamRollup = teams
+	}
+}
+
+// SubmitMatchResults applies a whole match at once: every player's score
+// changes by its points (clamped at zero with WithZeroFloor), outcomes are
+// counted in players' records and points are credited to players' teams,
+// all in one script, so either the whole match is applied or nothing is.
+// Locked scores, closed submissions and full boards fail the whole match; with
+// CapEvictLowest players of the match are never evicted to admit each other.
+// Gained points are credited at most what's left of players' earning caps.
+// Points always change scores cumulatively regardless of WithScoringMode and
+// aren't held for WithPendingVerification, as the match is applied whole.
+// Players are returned with their new ranks (and records) in order of results.
+func (l *Leaderboard) SubmitMatchResults(ctx context.Context, results []MatchResult) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "SubmitMatchResults")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if len(results) == 0 {
+		return []User{}, nil
+	}
+
+	userIDs := make([]string, len(results))
+	for i, r := range results {
+		userIDs[i] = r.UserID
+
+		if r.Outcome != "" && !l.records {
+			return nil, ErrRecordsDisabled
+		}
+		if r.Outcome != "" && r.Outcome != OutcomeWin && r.Outcome != OutcomeLoss && r.Outcome != OutcomeDraw {
+			return nil, ErrUnknownOutcome
+		}
+		if r.TeamID != "" && l.teamRollup == nil {
+			return nil, ErrTeamRollupDisabled
+		}
+	}
+
+	if err := l.checkBanned(ctx, userIDs...); err != nil {
+		return nil, err
+	}
+
+	befores := make([]User, len(results))
+	for i, userID := range userIDs {
+		befores[i] = l.memberBefore(ctx, userID)
+	}
+
+	// Board stands in for teams board when there are no teams, it's never touched then
+	keys := []string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey(), l.recordsKey(), l.leaderboardName, l.metaKey()}
+	if l.teamRollup != nil {
+		keys[5], keys[6] = l.teamRollup.board.leaderboardName, l.teamRollup.board.metaKey()
+	}
+
+	floor := "0"
+	if l.zeroFloor {
+		floor = "1"
+	}
+
+	args := []interface{}{len(results), floor}
+	teamKeys := map[string]int{}
+	for _, r := range results {
+		team := 0
+		if r.TeamID != "" {
+			if team = teamKeys[r.TeamID]; team == 0 {
+				keys = append(keys, l.teamRollup.contributionsKey(r.TeamID))
+				team = len(keys)
+				teamKeys[r.TeamID] = team
+			}
+		}
+		args = append(args, r.UserID, r.Points, r.Outcome, r.TeamID, team)
+	}
+	keys, args, earnedKey := l.appendEarningCap(keys, args)
+
+	reply, err := submitMatchScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Result()
+	if err != nil {
+		return nil, scriptError(err)
+	}
+	l.mirrorEarned(ctx, earnedKey, userIDs...)
+
+	replies, ok := reply.([]interface{})
+	if !ok || len(replies) != len(results) {
+		return nil, errUnexpectedScriptReply
+	}
+
+	scores := make([]float64, len(results))
+	teamScores := map[string]float64{}
+	for i, r := range replies {
+		values, ok := r.([]interface{})
+		if !ok || len(values) != 3 {
+			return nil, errUnexpectedScriptReply
+		}
+
+		scoreText, _ := values[0].(string)
+		if scores[i], err = parseScore(scoreText); err != nil {
+			return nil, err
+		}
+
+		if teamID := results[i].TeamID; teamID != "" {
+			l.matchTeamWritten(ctx, teamID, userIDs[i], values[1], values[2], teamScores)
+		}
+	}
+	if l.teamRollup != nil {
+		l.teamRollup.board.scoresWritten(ctx, memberScores(teamScores))
+	}
+
+	pipe := l.client().Pipeline()
+	rankCmds := make([]*redis.IntCmd, len(results))
+	for i, userID := range userIDs {
+		rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	users = make([]User, len(results))
+	for i, userID := range userIDs {
+		users[i] = l.scoredUser(userID, scores[i], int(rankCmds[i].Val())+1)
+	}
+
+	if err := l.markGhosts(ctx, l.client(), users); err != nil {
+		return nil, err
+	}
+	if err := l.loadRecords(ctx, l.client(), users); err != nil {
+		return nil, err
+	}
+
+	written := make([]redis.Z, len(users))
+	events := make([]Event, 0, len(users))
+	for i, user := range users {
+		written[i] = redis.Z{Score: scores[i], Member: user.UserID}
+		if results[i].Outcome != "" && user.Record != nil {
+			userID, record := user.UserID, user.Record
+			l.mirrorWrite(func(cli redis.UniversalClient) error {
+				return cli.HSet(ctx, l.recordsKey(), recordField(userID, OutcomeWin), record.Wins, recordField(userID, OutcomeLoss), record.Losses, recordField(userID, OutcomeDraw), record.Draws).Err()
+			})
+		}
+
+		events = append(events, Event{
+			Type:     EventRankChanged,
+			UserID:   user.UserID,
+			OldRank:  befores[i].Rank,
+			NewRank:  user.Rank,
+			OldScore: befores[i].Score,
+			NewScore: user.Score,
+		})
+	}
+	l.scoresWritten(ctx, written)
+	l.emitAll(ctx, events)
+
+	return users, nil
+}
+
+// matchTeamWritten records team's new total in teamScores, for post-write hooks
+// of the whole match, and mirrors player's contribution.
+func (l *Leaderboard) matchTeamWritten(ctx context.Context, teamID, userID string, teamScore, contribution interface{}, teamScores map[string]float64) {
+	teams := l.teamRollup
+
+	scoreText, _ := teamScore.(string)
+	if score, err := parseScore(scoreText); err == nil {
+		teamScores[teamID] = score
+	}
+
+	if points, ok := contribution.(int64); ok {
+		teams.board.mirrorHashField(ctx, teams.contributionsKey(teamID), userID, strconv.FormatInt(points, 10))
+	}
+}
+
+// memberScores returns scores keyed by userID as redis.Z slice.
+func memberScores(scores map[string]float64) []redis.Z {
+	members := make([]redis.Z, 0, len(scores))
+	for userID, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: userID})
+	}
+
+	return members
+}
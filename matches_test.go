@@ -0,0 +1,52 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSubmitMatchResultsCountsNewPlayersTogether(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "x", 10)
+
+	if err := l.SetMemberCap(ctx, 2, CapReject); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := l.SubmitMatchResults(ctx, []MatchResult{{UserID: "a", Points: 1}, {UserID: "b", Points: 2}})
+	if !errors.Is(err, ErrBoardFull) {
+		t.Fatalf("got %v, want ErrBoardFull", err)
+	}
+	if n := l.client().ZCard(ctx, l.leaderboardName).Val(); n != 1 {
+		t.Fatalf("got %d members, want 1", n)
+	}
+}
+
+func TestSubmitMatchResultsNeverEvictsPlayersOfTheMatch(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 0, "x", 5, "y", 6)
+
+	if err := l.SetMemberCap(ctx, 3, CapEvictLowest); err != nil {
+		t.Fatal(err)
+	}
+
+	// "a" is the lowest member but plays the match, so "x" makes room for "b"
+	users, err := l.SubmitMatchResults(ctx, []MatchResult{{UserID: "a", Points: 1}, {UserID: "b", Points: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users[0].Score != 1 || users[1].Score != 2 {
+		t.Fatalf("got %+v, want scores 1 and 2", users)
+	}
+
+	leaders, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(leaders), []string{"y", "b", "a"}) {
+		t.Fatalf("got %v, want [y b a]", userIDs(leaders))
+	}
+}
@@ -0,0 +1,117 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// materializedTop is copy of top of the board kept in a small dedicated key.
+type materializedTop struct {
+	size     int
+	interval time.Duration
+	onError  func(error)
+	// ready is set to 1 once the copy was written at least once
+	ready int32
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// WithMaterializedTop copies top size members into "<leaderboardName>:top" key
+// with ZRANGESTORE (Redis 6.2+) every interval and serves GetLeaders and
+// GetLeadersInto pages lying within it from the copy, decoupling the hot read
+// path from the huge board. Pages served from the copy may be up to interval
+// stale; reads with WithConsistency(Strong) always use the board itself.
+// onError, if not nil, is called with errors of background refreshes.
+func WithMaterializedTop(size int, interval time.Duration, onError func(error)) Option {
+	return func(l *Leaderboard) {
+		if size <= 0 || interval <= 0 {
+			return
+		}
+
+		l.top = &materializedTop{size: size, interval: interval, onError: onError, stop: make(chan struct{})}
+	}
+}
+
+func (l *Leaderboard) topKey() string {
+	return l.leaderboardName + ":top"
+}
+
+// rangeKey returns key range of the board ending at 0-based endOffset is read from.
+func (l *Leaderboard) rangeKey(o readOptions, endOffset int) string {
+	t := l.top
+	if t == nil || o.consistency == Strong || endOffset >= t.size || atomic.LoadInt32(&t.ready) == 0 {
+		return l.leaderboardName
+	}
+
+	return l.topKey()
+}
+
+// RefreshMaterializedTop copies current top of the board into the materialized
+// key right away, see WithMaterializedTop.
+func (l *Leaderboard) RefreshMaterializedTop(ctx context.Context) (err error) {
+	ctx, done, err := l.startOp(ctx, "RefreshMaterializedTop")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	t := l.top
+	if t == nil {
+		return nil
+	}
+
+	err = l.client().Do(ctx, "ZRANGESTORE", l.topKey(), l.leaderboardName, 0, t.size-1, "REV").Err()
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&t.ready, 1)
+
+	return nil
+}
+
+// startMaterializer starts background refreshing of materialized top when configured.
+func (l *Leaderboard) startMaterializer() {
+	t := l.top
+	if t == nil {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ctx := context.Background()
+		refresh := func() {
+			if err := l.RefreshMaterializedTop(ctx); err != nil && t.onError != nil {
+				t.onError(err)
+			}
+		}
+		refresh()
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+}
+
+// stopMaterializer stops background refreshing, waiting for running refresh to finish.
+func (l *Leaderboard) stopMaterializer() {
+	if t := l.top; t != nil {
+		t.stopOnce.Do(func() {
+			close(t.stop)
+		})
+		t.wg.Wait()
+	}
+}
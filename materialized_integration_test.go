@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"os"
+	"testing"
+	"time"
+)
+
+// integrationRedisAddr returns address of real Redis at LEADERBOARD_TEST_REDIS
+// (localhost:6379 by default), for features miniredis doesn't support. Test is
+// skipped when it isn't reachable.
+func integrationRedisAddr(t *testing.T) string {
+	t.Helper()
+
+	addr := os.Getenv("LEADERBOARD_TEST_REDIS")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
+	cli := redis.NewClient(&redis.Options{Addr: addr})
+	defer cli.Close()
+	if err := cli.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("Redis at %s isn't reachable: %v", addr, err)
+	}
+
+	return addr
+}
+
+// newIntegrationBoard returns board named after the test on Redis at
+// integrationRedisAddr.
+func newIntegrationBoard(t *testing.T, pageSize int, opts ...Option) *Leaderboard {
+	t.Helper()
+
+	addr := integrationRedisAddr(t)
+	name := "test:" + t.Name()
+	l, err := NewLeaderboardWithClient(redis.NewClient(&redis.Options{Addr: addr}), StagingMode, name, name+":info", pageSize, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanupIntegrationBoard(t, l, addr)
+
+	return l
+}
+
+// cleanupIntegrationBoard shuts board down and deletes its keys after the test.
+func cleanupIntegrationBoard(t *testing.T, l *Leaderboard, addr string) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		_ = l.Shutdown(ctx)
+
+		keys := redis.NewClient(&redis.Options{Addr: addr})
+		defer keys.Close()
+		for _, pattern := range []string{l.leaderboardName, l.leaderboardName + ":*"} {
+			for _, key := range keys.Keys(ctx, pattern).Val() {
+				keys.Del(ctx, key)
+			}
+		}
+	})
+}
+
+func TestMaterializedTopServesPagesWithinIt(t *testing.T) {
+	l := newIntegrationBoard(t, 2, WithMaterializedTop(3, time.Hour, nil))
+	ctx := context.Background()
+
+	seed(t, l, "a", 1, "b", 2, "c", 3, "d", 4)
+	if err := l.RefreshMaterializedTop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	seed(t, l, "x", 10)
+
+	tests := []struct {
+		name string
+		page int
+		opts []ReadOption
+		want []string
+	}{
+		{"within copy", 1, nil, []string{"d", "c"}},
+		{"strong", 1, []ReadOption{WithConsistency(Strong)}, []string{"x", "d"}},
+		{"past copy", 2, nil, []string{"c", "b"}},
+	}
+
+	for _, tt := range tests {
+		users, err := l.GetLeaders(ctx, tt.page, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalStrings(userIDs(users), tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, userIDs(users), tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// Returns {rank, score, info} of ARGV[1] on board KEYS[1] with info from hash
+// KEYS[2], or nil when member isn't ranked.
+var memberFullScript = redis.NewScript(`
+local rank = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+if not rank then
+	return false
+end
+
+return {rank, redis.call("ZSCORE", KEYS[1], ARGV[1]), redis.call("HGET", KEYS[2], ARGV[1])}
+`)
+
+// GetMemberFull returns member's rank, score and info read by one script on
+// the primary, so all three come from the same point in time, e.g. for prize
+// verification snapshots. Unranked members are returned without info according
+// to board's UnrankedPolicy. With Redis Cluster, board and info hash must share
+// a hash slot.
+func (l *Leaderboard) GetMemberFull(ctx context.Context, userID string) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMemberFull", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	reply, err := memberFullScript.Run(ctx, l.client(), []string{l.leaderboardName, l.profiles.hashName}, userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return l.presentMember(ctx, User{UserID: userID, Rank: UnrankedMember})
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return User{}, errUnexpectedScriptReply
+	}
+
+	rank, ok := values[0].(int64)
+	if !ok {
+		return User{}, errUnexpectedScriptReply
+	}
+
+	scoreText, ok := values[1].(string)
+	if !ok {
+		return User{}, errUnexpectedScriptReply
+	}
+
+	score, err := parseScore(scoreText)
+	if err != nil {
+		return User{}, err
+	}
+
+	user = l.scoredUser(userID, score, int(rank)+1)
+	if user, err = l.markGhost(ctx, l.client(), user); err != nil {
+		return User{}, err
+	}
+
+	if storedData, ok := values[2].(string); ok {
+		if user.AdditionalInfo, err = decodeMemberInfo(storedData, l.profiles.keyring); err != nil {
+			return User{}, err
+		}
+	}
+
+	return user, nil
+}
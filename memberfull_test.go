@@ -0,0 +1,44 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetMemberFull(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 1, "b", 2)
+
+	if err := l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"a"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		userID   string
+		rank     int
+		score    int
+		wantInfo string
+	}{
+		{"a", 2, 1, `{"name":"a"}`},
+		{"b", 1, 2, ""},
+	}
+
+	for _, tt := range tests {
+		user, err := l.GetMemberFull(ctx, tt.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Rank != tt.rank || user.Score != tt.score || string(user.AdditionalInfo) != tt.wantInfo {
+			t.Fatalf("got %+v, want rank %d, score %d and info %q", user, tt.rank, tt.score, tt.wantInfo)
+		}
+	}
+
+	user, err := l.GetMemberFull(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Rank != UnrankedMember {
+		t.Fatalf("got rank %d of unranked member, want UnrankedMember", user.Rank)
+	}
+}
@@ -0,0 +1,688 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MigrationPhaseDualWrite = "dual_write"
+	MigrationPhaseBackfill  = "backfill"
+	MigrationPhaseVerify    = "verify"
+	MigrationPhaseCutover   = "cutover"
+
+	DefaultMigrationBatchSize = 500
+)
+
+var (
+	ErrMigrationInProgress = errors.New("leaderboard: migration is already in progress")
+	ErrSharedClient        = errors.New("leaderboard: board uses shared client and can't be migrated on its own")
+)
+
+// MigrationOptions configures MigrateTo.
+type MigrationOptions struct {
+	// BatchSize is number of members copied per ZSCAN/HSCAN iteration.
+	BatchSize int
+	// OnProgress, when set, is called after every copied batch.
+	OnProgress func(progress MigrationProgress)
+}
+
+// MigrationProgress describes state of a running migration.
+type MigrationProgress struct {
+	Phase         string
+	CopiedMembers int
+	CopiedInfo    int
+	FixedMembers  int
+	MirrorErrors  int
+}
+
+// migrationTarget is destination of a running migration. Failed dual writes are
+// counted so they can be reported, verify phase fixes them afterwards.
+type migrationTarget struct {
+	cli    redis.UniversalClient
+	mu     sync.Mutex
+	errors int
+}
+
+// MigrateTo moves leaderboard (scores, user info and companion keys holding
+// configuration, locks, records, streams, ...) to another Redis instance
+// without downtime:
+//
+//  1. dual write: every write done through this Leaderboard is mirrored to destination,
+//  2. backfill: existing members, companion keys and info are copied to destination,
+//  3. verify: source and destination are compared and differences are fixed,
+//  4. cutover: this Leaderboard starts using destination and mirroring stops.
+//
+// Mirrored writes carry absolute values (not deltas), so copying the same member
+// twice is harmless. If several application instances write to the same board,
+// all of them must go through a migrating Leaderboard, otherwise writes done by
+// the others between verify and cutover are not carried over. Neither are
+// writes of this Leaderboard still in flight while cutover happens.
+//
+// Read replica configured with WithReadReplica belongs to the old instance and
+// is dropped on cutover, reads then go to destination.
+//
+// Boards created through a Manager share client and profiles with other boards
+// and can't be migrated, ErrSharedClient is returned for them.
+func (l *Leaderboard) MigrateTo(ctx context.Context, dest RedisSettings, opts MigrationOptions) (err error) {
+	done, err := l.trackOp("MigrateTo")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !l.ownsClient {
+		return ErrSharedClient
+	}
+
+	if l.eventSourced {
+		return ErrEventSourcingEnabled
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultMigrationBatchSize
+	}
+
+	destCli := connectToRedis(dest, l.modeProfile().PoolSize)
+	if err := destCli.Ping(ctx).Err(); err != nil {
+		_ = destCli.Close()
+		return err
+	}
+
+	target := &migrationTarget{cli: destCli}
+
+	l.migrationMu.Lock()
+	if l.migrationTarget() != nil {
+		l.migrationMu.Unlock()
+		_ = destCli.Close()
+		return ErrMigrationInProgress
+	}
+	l.migration.Store(target)
+	l.migrationMu.Unlock()
+
+	progress := MigrationProgress{Phase: MigrationPhaseDualWrite}
+	report(opts, progress)
+
+	if err := l.backfill(ctx, destCli, opts, &progress); err != nil {
+		l.abortMigration(target)
+		return err
+	}
+
+	if err := l.verifyMigration(ctx, target, opts, &progress); err != nil {
+		l.abortMigration(target)
+		return err
+	}
+
+	source := l.client()
+	l.dropReplica()
+	l.redisCli.Store(clientHolder{destCli})
+	l.migration.Store((*migrationTarget)(nil))
+	l.RedisSettings = dest
+
+	progress.Phase = MigrationPhaseCutover
+	progress.MirrorErrors = target.count()
+	report(opts, progress)
+
+	return source.Close()
+}
+
+func (l *Leaderboard) abortMigration(target *migrationTarget) {
+	l.migration.Store((*migrationTarget)(nil))
+	_ = target.cli.Close()
+}
+
+func (l *Leaderboard) backfill(ctx context.Context, destCli redis.UniversalClient, opts MigrationOptions, progress *MigrationProgress) error {
+	progress.Phase = MigrationPhaseBackfill
+
+	err := scanSortedSet(ctx, l.client(), l.leaderboardName, opts.BatchSize, func(members []redis.Z) error {
+		if err := destCli.ZAdd(ctx, l.leaderboardName, zPointers(members)...).Err(); err != nil {
+			return err
+		}
+
+		progress.CopiedMembers += len(members)
+		report(opts, *progress)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	keys, err := l.companionKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := copyKey(ctx, l.client(), destCli, key, opts.BatchSize); err != nil {
+			return err
+		}
+	}
+
+	locales, err := l.profiles.Locales(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(locales) > 0 {
+		if err := destCli.SAdd(ctx, l.profiles.localesKey(), stringsToInterfaces(locales)...).Err(); err != nil {
+			return err
+		}
+	}
+
+	hashes := []string{l.profiles.hashName}
+	for _, locale := range locales {
+		hashes = append(hashes, l.profiles.localeHash(locale))
+	}
+
+	for _, hash := range hashes {
+		err := scanHash(ctx, l.client(), hash, opts.BatchSize, func(values map[string]interface{}) error {
+			if err := destCli.HSet(ctx, hash, values).Err(); err != nil {
+				return err
+			}
+
+			progress.CopiedInfo += len(values)
+			report(opts, *progress)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// companionKeys returns keys holding board's state besides its sorted set and
+// info: configuration, locks, tags, records, streams, snapshots, ... Features
+// adding a key must list it here, so the key is carried over by MigrateTo and
+// removed with the board. Per-minute write stats aren't listed, they expire
+// within a day.
+func (l *Leaderboard) companionKeys(ctx context.Context) ([]string, error) {
+	keys := []string{
+		l.metaKey(), l.eventsStream(), l.submissionsStream(), l.quarantineStream(), l.locksKey(), l.tagsKey(),
+		l.scoreLogKey(), l.freshnessKey(), l.topKey(), l.recordsKey(), l.headToHeadKey(), l.sourcesKey(),
+		l.displayKey(), l.displayIndexKey(), l.pendingKey(), l.baselineKey(), l.ghostsKey(), l.deadLetterStream(),
+		l.snapshotsKey(),
+	}
+
+	if l.earningCap != nil {
+		// Period is validated by WithEarningCap
+		period, _ := PeriodOf(l.earningCap.period, time.Now(), l.earningCap.loc)
+
+		// Hash of the previous period is kept until the current one ends
+		for _, p := range []Period{period, period.Previous()} {
+			key, _ := l.earnedKey(p.Start)
+			keys = append(keys, key)
+		}
+	}
+
+	tags, err := l.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		keys = append(keys, l.tagKey(tag))
+	}
+
+	snapshots, err := l.client().ZRange(ctx, l.snapshotsKey(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range snapshots {
+		keys = append(keys, l.snapshotKey(SnapshotID(id)))
+	}
+
+	return keys, nil
+}
+
+// copyKey copies key of any type from src to dest in batches, keeping its
+// expiry. Existing values at dest are merged with copied ones. Stream entries
+// dest already has (e.g. mirrored ones) are kept, consumer groups aren't copied.
+func copyKey(ctx context.Context, src, dest redis.UniversalClient, key string, batchSize int) error {
+	kind, err := src.Type(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "none":
+		return nil
+	case "string":
+		value, err := src.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		err = dest.Set(ctx, key, value, 0).Err()
+	case "hash":
+		err = scanHash(ctx, src, key, batchSize, func(values map[string]interface{}) error {
+			return dest.HSet(ctx, key, values).Err()
+		})
+	case "set":
+		err = scanSet(ctx, src, key, batchSize, func(members []string) error {
+			return dest.SAdd(ctx, key, stringsToInterfaces(members)...).Err()
+		})
+	case "zset":
+		err = scanSortedSet(ctx, src, key, batchSize, func(members []redis.Z) error {
+			return dest.ZAdd(ctx, key, zPointers(members)...).Err()
+		})
+	case "stream":
+		err = copyStream(ctx, src, dest, key, batchSize)
+	default:
+		return errors.New("leaderboard: can't copy " + kind + " key " + key)
+	}
+	if err != nil {
+		return err
+	}
+
+	ttl, err := src.PTTL(ctx, key).Result()
+	if err != nil || ttl <= 0 {
+		return err
+	}
+
+	return dest.PExpire(ctx, key, ttl).Err()
+}
+
+// copyStream appends entries of stream src key to dest under their IDs.
+func copyStream(ctx context.Context, src, dest redis.UniversalClient, key string, batchSize int) error {
+	start := "-"
+	for {
+		messages, err := src.XRangeN(ctx, key, start, "+", int64(batchSize)).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			err := dest.XAdd(ctx, &redis.XAddArgs{Stream: key, ID: msg.ID, Values: msg.Values}).Err()
+			// Entries at or below the last ID at dest are already there
+			if err != nil && !strings.Contains(err.Error(), "equal or smaller") {
+				return err
+			}
+		}
+
+		if len(messages) < batchSize {
+			return nil
+		}
+		start = nextStreamID(messages[len(messages)-1].ID)
+	}
+}
+
+// maxReconcileAttempts bounds retries of reconcileMember for a member written
+// continuously, the member is counted as mirror error then.
+const maxReconcileAttempts = 5
+
+// Sets score of member ARGV[1] to ARGV[3] (removes member when empty) only if
+// its current score is ARGV[2] (member is missing when empty). Returns 1 when
+// applied and 0 when member changed in the meantime.
+var reconcileScoreScript = redis.NewScript(`
+local current = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if ARGV[2] == "" then
+	if current then
+		return 0
+	end
+elseif not current or tonumber(current) ~= tonumber(ARGV[2]) then
+	return 0
+end
+
+if ARGV[3] == "" then
+	redis.call("ZREM", KEYS[1], ARGV[1])
+else
+	redis.call("ZADD", KEYS[1], ARGV[3], ARGV[1])
+end
+
+return 1
+`)
+
+// verifyMigration compares source and destination and fixes destination
+// members which differ (e.g. because a mirrored write failed) or exist only at
+// destination (e.g. removed while backfill copied them).
+func (l *Leaderboard) verifyMigration(ctx context.Context, target *migrationTarget, opts MigrationOptions, progress *MigrationProgress) error {
+	progress.Phase = MigrationPhaseVerify
+
+	// Every source member missing or differing at destination
+	err := scanSortedSet(ctx, l.client(), l.leaderboardName, opts.BatchSize, func(members []redis.Z) error {
+		return l.reconcileBatch(ctx, target, false, members, opts, progress)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Every destination member missing at source
+	return scanSortedSet(ctx, target.cli, l.leaderboardName, opts.BatchSize, func(members []redis.Z) error {
+		return l.reconcileBatch(ctx, target, true, members, opts, progress)
+	})
+}
+
+// reconcileBatch reads scores of members scanned at destination (or source
+// when scannedDest is false) from the other side and reconciles members whose
+// scores differ.
+func (l *Leaderboard) reconcileBatch(ctx context.Context, target *migrationTarget, scannedDest bool, members []redis.Z, opts MigrationOptions, progress *MigrationProgress) error {
+	other := target.cli
+	if scannedDest {
+		other = l.client()
+	}
+
+	pipe := other.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(members))
+	for i := range members {
+		cmds[i] = pipe.ZScore(ctx, l.leaderboardName, members[i].Member.(string))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	for i, cmd := range cmds {
+		otherScore, err := optionalScore(cmd)
+		if err != nil {
+			return err
+		}
+
+		scanned := members[i].Score
+		if otherScore != nil && *otherScore == scanned {
+			continue
+		}
+
+		observed := otherScore
+		if scannedDest {
+			observed = &scanned
+		}
+
+		fixed, err := l.reconcileMember(ctx, target, members[i].Member.(string), observed)
+		if err != nil {
+			return err
+		}
+		if fixed {
+			progress.FixedMembers++
+		}
+	}
+
+	progress.MirrorErrors = target.count()
+	report(opts, *progress)
+
+	return nil
+}
+
+// reconcileMember makes destination score of userID match source, observed is
+// destination score last read (nil when missing). Source is re-read right
+// before every write and destination is written only if it still holds
+// observed, so mirrored writes landing meanwhile aren't overwritten with older
+// scores. Source is read again after every write, as a write may have set it
+// to the observed score in between.
+func (l *Leaderboard) reconcileMember(ctx context.Context, target *migrationTarget, userID string, observed *float64) (fixed bool, err error) {
+	for attempt := 0; attempt < maxReconcileAttempts; attempt++ {
+		source, err := optionalScore(l.client().ZScore(ctx, l.leaderboardName, userID))
+		if err != nil {
+			return fixed, err
+		}
+
+		if equalScores(source, observed) {
+			return fixed, nil
+		}
+
+		applied, err := reconcileScoreScript.Run(ctx, target.cli, []string{l.leaderboardName}, userID, scoreArg(observed), scoreArg(source)).Int()
+		if err != nil {
+			return fixed, err
+		}
+
+		if applied == 1 {
+			fixed, observed = true, source
+			continue
+		}
+
+		if observed, err = optionalScore(target.cli.ZScore(ctx, l.leaderboardName, userID)); err != nil {
+			return fixed, err
+		}
+	}
+
+	target.failed()
+
+	return fixed, nil
+}
+
+// optionalScore returns score read by cmd, nil when member doesn't exist.
+func optionalScore(cmd *redis.FloatCmd) (*float64, error) {
+	score, err := cmd.Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &score, nil
+}
+
+func equalScores(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// scoreArg formats optional score as reconcileScoreScript argument.
+func scoreArg(score *float64) string {
+	if score == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*score, 'f', -1, 64)
+}
+
+// migrationTarget returns destination of running migration or nil.
+func (l *Leaderboard) migrationTarget() *migrationTarget {
+	return l.migration.Load().(*migrationTarget)
+}
+
+func (l *Leaderboard) mirrorWrite(fn func(cli redis.UniversalClient) error) {
+	target := l.migrationTarget()
+	if target == nil {
+		return
+	}
+
+	if err := fn(target.cli); err != nil {
+		target.failed()
+	}
+}
+
+func (l *Leaderboard) mirrorScore(ctx context.Context, userID string, score float64) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.ZAdd(ctx, l.leaderboardName, &redis.Z{Score: score, Member: userID}).Err()
+	})
+}
+
+// mirrorScores is mirrorScore for many members in one ZADD.
+func (l *Leaderboard) mirrorScores(ctx context.Context, members []redis.Z) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.ZAdd(ctx, l.leaderboardName, zPointers(members)...).Err()
+	})
+}
+
+func (l *Leaderboard) mirrorInfo(ctx context.Context, userID, value string) {
+	l.mirrorHashField(ctx, l.profiles.hashName, userID, value)
+}
+
+func (l *Leaderboard) mirrorHashField(ctx context.Context, hash, field, value string) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.HSet(ctx, hash, field, value).Err()
+	})
+}
+
+func (l *Leaderboard) mirrorLocalizedInfo(ctx context.Context, userID, locale, value string) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		pipe := cli.TxPipeline()
+		pipe.HSet(ctx, l.profiles.localeHash(locale), userID, value)
+		pipe.SAdd(ctx, l.profiles.localesKey(), locale)
+		_, err := pipe.Exec(ctx)
+
+		return err
+	})
+}
+
+func (l *Leaderboard) mirrorRemove(ctx context.Context, userID string) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		if err := cli.ZRem(ctx, l.leaderboardName, userID).Err(); err != nil {
+			return err
+		}
+
+		if err := l.removeTags(ctx, cli, userID); err != nil {
+			return err
+		}
+
+		if l.profiles.shared {
+			return nil
+		}
+
+		locales, err := cli.SMembers(ctx, l.profiles.localesKey()).Result()
+		if err != nil {
+			return err
+		}
+
+		pipe := cli.Pipeline()
+		pipe.HDel(ctx, l.profiles.hashName, userID)
+		for _, locale := range locales {
+			pipe.HDel(ctx, l.profiles.localeHash(locale), userID)
+		}
+		_, err = pipe.Exec(ctx)
+
+		return err
+	})
+}
+
+// mirrorTrim removes members from destination's sorted set only, their info is kept.
+func (l *Leaderboard) mirrorTrim(ctx context.Context, members []User) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		userIDs := make([]interface{}, len(members))
+		for i, m := range members {
+			userIDs[i] = m.UserID
+		}
+
+		return cli.ZRem(ctx, l.leaderboardName, userIDs...).Err()
+	})
+}
+
+func (m *migrationTarget) failed() {
+	m.mu.Lock()
+	m.errors++
+	m.mu.Unlock()
+}
+
+func (m *migrationTarget) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.errors
+}
+
+func report(opts MigrationOptions, progress MigrationProgress) {
+	if opts.OnProgress != nil {
+		opts.OnProgress(progress)
+	}
+}
+
+func scanSortedSet(ctx context.Context, redisCli redis.UniversalClient, key string, batchSize int, fn func(members []redis.Z) error) error {
+	var cursor uint64
+	for {
+		values, next, err := redisCli.ZScan(ctx, key, cursor, "", int64(batchSize)).Result()
+		if err != nil {
+			return err
+		}
+
+		members := make([]redis.Z, 0, len(values)/2)
+		for i := 0; i+1 < len(values); i += 2 {
+			score, err := parseScore(values[i+1])
+			if err != nil {
+				return err
+			}
+
+			members = append(members, redis.Z{Member: values[i], Score: score})
+		}
+
+		if len(members) > 0 {
+			if err := fn(members); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func scanSet(ctx context.Context, redisCli redis.UniversalClient, key string, batchSize int, fn func(members []string) error) error {
+	var cursor uint64
+	for {
+		members, next, err := redisCli.SScan(ctx, key, cursor, "", int64(batchSize)).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(members) > 0 {
+			if err := fn(members); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func scanHash(ctx context.Context, redisCli redis.UniversalClient, key string, batchSize int, fn func(values map[string]interface{}) error) error {
+	var cursor uint64
+	for {
+		fields, next, err := redisCli.HScan(ctx, key, cursor, "", int64(batchSize)).Result()
+		if err != nil {
+			return err
+		}
+
+		values := make(map[string]interface{}, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			values[fields[i]] = fields[i+1]
+		}
+
+		if len(values) > 0 {
+			if err := fn(values); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func parseScore(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i := range values {
+		result[i] = values[i]
+	}
+
+	return result
+}
+
+func zPointers(members []redis.Z) []*redis.Z {
+	pointers := make([]*redis.Z, len(members))
+	for i := range members {
+		pointers[i] = &members[i]
+	}
+
+	return pointers
+}
@@ -0,0 +1,220 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"testing"
+)
+
+func TestMigrateToCopiesCompanionKeys(t *testing.T) {
+	src, dest := miniredis.RunT(t), miniredis.RunT(t)
+	ctx := context.Background()
+
+	l, err := NewLeaderboard(RedisSettings{Host: src.Addr()}, StagingMode, "test", "test_info", 10, WithRecords(), WithGhosts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Shutdown(ctx)
+
+	if _, err := l.RecordResult(ctx, "a", OutcomeWin, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetScoreUnit(ctx, ScoreUnitMilliseconds); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetMemberCap(ctx, 100, CapReject); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AddGhost(ctx, "ghost", 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.LockMemberScore(ctx, "a", "review"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.MigrateTo(ctx, RedisSettings{Host: dest.Addr()}, MigrationOptions{BatchSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{l.metaKey(), l.recordsKey(), l.ghostsKey(), l.locksKey(), l.leaderboardName} {
+		if !dest.Exists(key) {
+			t.Errorf("%s wasn't migrated", key)
+		}
+	}
+
+	if unit := dest.HGet(l.metaKey(), metaScoreUnit); unit != ScoreUnitMilliseconds {
+		t.Errorf("got score unit %q at destination, want %q", unit, ScoreUnitMilliseconds)
+	}
+
+	user, err := l.GetMember(ctx, "a", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Record == nil || user.Record.Wins != 1 {
+		t.Fatalf("got record %+v after cutover, want 1 win", user.Record)
+	}
+}
+
+func TestReconcileMember(t *testing.T) {
+	ctx := context.Background()
+	const missing = -1
+
+	tests := []struct {
+		name      string
+		source    float64
+		dest      float64
+		observed  float64
+		wantDest  float64
+		wantFixed bool
+	}{
+		{"stale", 5, 1, 1, 5, true},
+		{"missing at destination", 5, missing, missing, 5, true},
+		{"removed at source", missing, 3, 3, missing, true},
+		// Mirrored write landed after destination was read
+		{"written meanwhile", 7, 7, 1, 7, false},
+		{"removed meanwhile", missing, missing, 3, missing, false},
+		{"decimal", 2.5, 3, 3, 2.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, src := newTestBoard(t)
+			dest := miniredis.RunT(t)
+			target := &migrationTarget{cli: redis.NewClient(&redis.Options{Addr: dest.Addr()})}
+			defer target.cli.Close()
+
+			if tt.source != missing {
+				src.ZAdd(l.leaderboardName, tt.source, "a")
+			}
+			if tt.dest != missing {
+				dest.ZAdd(l.leaderboardName, tt.dest, "a")
+			}
+
+			var observed *float64
+			if tt.observed != missing {
+				observed = &tt.observed
+			}
+
+			fixed, err := l.reconcileMember(ctx, target, "a", observed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fixed != tt.wantFixed {
+				t.Fatalf("got fixed %v, want %v", fixed, tt.wantFixed)
+			}
+
+			got, err := dest.ZScore(l.leaderboardName, "a")
+			if err != nil {
+				got = missing
+			}
+			if got != tt.wantDest {
+				t.Fatalf("got destination score %v, want %v", got, tt.wantDest)
+			}
+		})
+	}
+}
+
+func TestMigrateToRemovesDestinationOnlyMembers(t *testing.T) {
+	src, dest := miniredis.RunT(t), miniredis.RunT(t)
+	ctx := context.Background()
+
+	l, err := NewLeaderboard(RedisSettings{Host: src.Addr()}, StagingMode, "test", "test_info", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Shutdown(ctx)
+
+	seed(t, l, "a", 1, "b", 2)
+	// Left over by backfill copying a member removed meanwhile
+	dest.ZAdd(l.leaderboardName, 5, "removed")
+
+	if err := l.MigrateTo(ctx, RedisSettings{Host: dest.Addr()}, MigrationOptions{BatchSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := dest.ZMembers(l.leaderboardName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(members, []string{"a", "b"}) {
+		t.Fatalf("got destination members %v, want [a b]", members)
+	}
+}
+
+func TestMigrateToWithConcurrentWrites(t *testing.T) {
+	src, dest := miniredis.RunT(t), miniredis.RunT(t)
+	ctx := context.Background()
+
+	l, err := NewLeaderboard(RedisSettings{Host: src.Addr()}, StagingMode, "test", "test_info", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Shutdown(ctx)
+
+	for i := 0; i < 100; i++ {
+		seed(t, l, "m"+strconv.Itoa(i), i)
+	}
+
+	// Writes run while migration copies and verifies members and must all be
+	// done before cutover, as writes in flight during cutover aren't carried over
+	done := make(chan struct{})
+	var writeErr error
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if _, err := l.IncrementMemberScore(ctx, "m"+strconv.Itoa(i%100), 1); err != nil {
+				writeErr = err
+				return
+			}
+		}
+	}()
+
+	opts := MigrationOptions{BatchSize: 5, OnProgress: func(progress MigrationProgress) {
+		if progress.Phase == MigrationPhaseVerify {
+			<-done
+		}
+	}}
+
+	if err := l.MigrateTo(ctx, RedisSettings{Host: dest.Addr()}, opts); err != nil {
+		t.Fatal(err)
+	}
+	if writeErr != nil {
+		t.Fatal(writeErr)
+	}
+
+	for i := 0; i < 100; i++ {
+		userID := "m" + strconv.Itoa(i)
+		if score, err := dest.ZScore(l.leaderboardName, userID); err != nil || score != float64(i+2) {
+			t.Fatalf("got destination score %v (%v) of %s, want %d", score, err, userID, i+2)
+		}
+	}
+}
+
+func TestMirroredScoresKeepDecimals(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t, WithPrecision(PrecisionOneDecimal))
+	dest := miniredis.RunT(t)
+
+	target := &migrationTarget{cli: redis.NewClient(&redis.Options{Addr: dest.Addr()})}
+	defer target.cli.Close()
+	l.migration.Store(target)
+	defer l.migration.Store((*migrationTarget)(nil))
+
+	if _, err := l.IncrementMemberScoreDecimal(ctx, "a", 2.5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.IncrementMemberScoreDecimal(ctx, "b", 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.IncrementMemberScore(ctx, "b", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for userID, want := range map[string]float64{"a": 2.5, "b": 1.5} {
+		if score, err := dest.ZScore(l.leaderboardName, userID); err != nil || score != want {
+			t.Fatalf("got destination score %v (%v) of %s, want %v", score, err, userID, want)
+		}
+	}
+}
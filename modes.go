@@ -0,0 +1,96 @@
+package go_redis_leaderboard
+
+import (
+	"errors"
+	"log"
+	"sync"
+)
+
+var (
+	ErrInvalidPageSize = errors.New("leaderboard: page size must be 10, 25, 50 or 100")
+)
+
+// ModeProfile is behaviour selected by mode boards and managers are created in.
+type ModeProfile struct {
+	// PoolSize is size of connection pool of clients created in the mode, 0
+	// leaves go-redis default
+	PoolSize int
+	// LogOperations logs every operation with its duration through log package,
+	// unless board has its own WithSlowOperationHook
+	LogOperations bool
+	// StrictValidation makes constructors reject invalid page size with
+	// ErrInvalidPageSize instead of falling back to DefaultPageSize
+	StrictValidation bool
+	// Options are applied to every board created in the mode before board's own
+	// options, e.g. WithSlowOperationHook reporting metrics in production
+	Options []Option
+}
+
+var (
+	modeProfilesMu sync.RWMutex
+	modeProfiles   = map[string]ModeProfile{
+		DevMode:        {PoolSize: 5, LogOperations: true},
+		StagingMode:    {StrictValidation: true},
+		ProductionMode: {StrictValidation: true},
+	}
+)
+
+// RegisterModeProfile sets profile of mode, replacing the built-in one for
+// DevMode, StagingMode and ProductionMode or adding a new mode. Profile applies
+// to boards and managers created afterwards.
+func RegisterModeProfile(mode string, profile ModeProfile) {
+	modeProfilesMu.Lock()
+	defer modeProfilesMu.Unlock()
+
+	modeProfiles[mode] = profile
+}
+
+// ModeProfileOf returns profile registered for mode.
+func ModeProfileOf(mode string) (ModeProfile, bool) {
+	modeProfilesMu.RLock()
+	defer modeProfilesMu.RUnlock()
+
+	profile, ok := modeProfiles[mode]
+
+	return profile, ok
+}
+
+// resolveMode returns mode with its profile, unknown modes fall back to DevMode.
+func resolveMode(mode string) (string, ModeProfile) {
+	if profile, ok := ModeProfileOf(mode); ok {
+		return mode, profile
+	}
+
+	profile, _ := ModeProfileOf(DevMode)
+
+	return DevMode, profile
+}
+
+func (l *Leaderboard) modeProfile() ModeProfile {
+	_, profile := resolveMode(l.mode)
+
+	return profile
+}
+
+// resolvePageSize validates pageSize according to profile.
+func resolvePageSize(pageSize int, profile ModeProfile) (int, error) {
+	if allowedPageSizes[pageSize] {
+		return pageSize, nil
+	}
+
+	if profile.StrictValidation {
+		return 0, ErrInvalidPageSize
+	}
+
+	return DefaultPageSize, nil
+}
+
+// logOperation is slow operation hook used by profiles with LogOperations.
+func logOperation(stats OperationStats) {
+	if stats.Err != nil {
+		log.Printf("leaderboard: %s %s took %s: %v", stats.Board, stats.Operation, stats.Duration, stats.Err)
+		return
+	}
+
+	log.Printf("leaderboard: %s %s took %s", stats.Board, stats.Operation, stats.Duration)
+}
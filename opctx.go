@@ -0,0 +1,100 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"time"
+)
+
+// OperationStats describes a single board operation reported to the slow
+// operation hook.
+type OperationStats struct {
+	Board     string
+	Operation string
+	Duration  time.Duration
+	Err       error
+}
+
+// WithDefaultTimeout bounds every request path operation (reads, score and info
+// writes) by timeout unless caller's context already carries a deadline.
+// Long running maintenance (MigrateTo, Rebuild, BulkLoad, ...) isn't affected.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(l *Leaderboard) {
+		if timeout > 0 {
+			l.defaultTimeout = timeout
+		}
+	}
+}
+
+// WithSlowOperationHook calls hook after every request path operation which took
+// at least threshold, so logging or metrics can point at boards which got slow.
+// Hook is called synchronously and should return quickly.
+func WithSlowOperationHook(threshold time.Duration, hook func(OperationStats)) Option {
+	return func(l *Leaderboard) {
+		l.slowOpThreshold = threshold
+		l.slowOpHook = hook
+	}
+}
+
+// startOp derives context for operation from parent, applying board's default
+// timeout, and tracks operation as in flight until returned func is called with
+// operation's error. The error is wrapped into LeaderboardError. Once Shutdown
+// started ErrShuttingDown is returned instead and operation must not run.
+func (l *Leaderboard) startOp(parent context.Context, operation string) (context.Context, func(*error), error) {
+	return l.startMemberOp(parent, operation, "")
+}
+
+// startMemberOp is startOp for operation on single member.
+func (l *Leaderboard) startMemberOp(parent context.Context, operation, userID string) (context.Context, func(*error), error) {
+	if !l.inflight.begin() {
+		err := ErrShuttingDown
+		l.wrapError(operation, userID, &err)
+		return parent, nil, err
+	}
+
+	if l.defaultTimeout <= 0 && l.slowOpHook == nil {
+		return parent, func(err *error) {
+			l.inflight.end()
+			l.wrapError(operation, userID, err)
+		}, nil
+	}
+
+	opCtx, cancel := parent, context.CancelFunc(func() {})
+	if _, ok := parent.Deadline(); !ok && l.defaultTimeout > 0 {
+		opCtx, cancel = context.WithTimeout(parent, l.defaultTimeout)
+	}
+
+	start := time.Now()
+
+	return opCtx, func(err *error) {
+		cancel()
+		l.inflight.end()
+		l.wrapError(operation, userID, err)
+
+		duration := time.Since(start)
+		if l.slowOpHook == nil || duration < l.slowOpThreshold {
+			return
+		}
+
+		stats := OperationStats{Board: l.leaderboardName, Operation: operation, Duration: duration}
+		if err != nil {
+			stats.Err = *err
+		}
+		l.slowOpHook(stats)
+	}, nil
+}
+
+// trackOp is startOp for maintenance and batch operations, which
+// are tracked as in flight but get neither default timeout nor slow operation
+// hook.
+func (l *Leaderboard) trackOp(operation string) (func(*error), error) {
+	if !l.inflight.begin() {
+		err := ErrShuttingDown
+		l.wrapError(operation, "", &err)
+		return nil, err
+	}
+
+	return func(err *error) {
+		l.inflight.end()
+		l.wrapError(operation, "", err)
+	}, nil
+}
@@ -0,0 +1,123 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"time"
+)
+
+// OpponentOptions configures FindOpponents.
+type OpponentOptions struct {
+	// Exclude are users never returned as opponents (friends, teammates, ...)
+	Exclude []string
+	// RememberFor makes returned opponents remembered for that long and skipped
+	// by next searches of the same member, so the same nearby opponent isn't
+	// offered over and over. 0 disables the memory.
+	RememberFor time.Duration
+}
+
+// recentOpponentsKey is set of opponents recently offered to member.
+func (l *Leaderboard) recentOpponentsKey(userID string) string {
+	return l.leaderboardName + ":opponents:" + userID
+}
+
+// FindOpponents returns up to count members ranked closest to member, nearest
+// first, for matchmaking. Member itself, excluded users and (with RememberFor)
+// opponents recently offered to member are skipped. ErrMemberNotFound is
+// returned when member isn't on the board.
+func (l *Leaderboard) FindOpponents(ctx context.Context, userID string, count int, opts OpponentOptions) (opponents []User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "FindOpponents", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if count <= 0 {
+		return []User{}, nil
+	}
+
+	pipe := l.client().Pipeline()
+	rankCmd := pipe.ZRevRank(ctx, l.leaderboardName, userID)
+	recentCmd := pipe.SMembers(ctx, l.recentOpponentsKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	rank, err := rankCmd.Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrMemberNotFound
+		}
+
+		return nil, err
+	}
+
+	excluded := map[string]bool{userID: true}
+	for _, id := range opts.Exclude {
+		excluded[id] = true
+	}
+	for _, id := range recentCmd.Val() {
+		excluded[id] = true
+	}
+
+	// Window around member is widened until enough candidates are found or
+	// whole board was searched
+	window := int64(count + len(excluded))
+	for {
+		start, end := rank-window, rank+window
+		if start < 0 {
+			start = 0
+		}
+
+		members, err := l.client().ZRevRangeWithScores(ctx, l.leaderboardName, start, end).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		opponents = opponents[:0]
+		for i, z := range members {
+			if id := z.Member.(string); !excluded[id] {
+				opponents = append(opponents, l.scoredUser(id, z.Score, int(start)+i+1))
+			}
+		}
+
+		searchedAll := start == 0 && int64(len(members)) < end-start+1
+		if len(opponents) >= count || searchedAll {
+			break
+		}
+		window *= 2
+	}
+
+	// Nearest first, ties prefer better ranked opponent
+	memberRank := int(rank) + 1
+	sort.SliceStable(opponents, func(i, j int) bool {
+		di, dj := abs(opponents[i].Rank-memberRank), abs(opponents[j].Rank-memberRank)
+		if di != dj {
+			return di < dj
+		}
+
+		return opponents[i].Rank < opponents[j].Rank
+	})
+
+	if len(opponents) > count {
+		opponents = opponents[:count]
+	}
+
+	if opts.RememberFor > 0 && len(opponents) > 0 {
+		ids := make([]interface{}, len(opponents))
+		for i, o := range opponents {
+			ids[i] = o.UserID
+		}
+
+		pipe := l.client().Pipeline()
+		pipe.SAdd(ctx, l.recentOpponentsKey(userID), ids...)
+		pipe.PExpire(ctx, l.recentOpponentsKey(userID), opts.RememberFor)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return opponents, nil
+}
@@ -0,0 +1,142 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// Increments member's score by what's left of earning cap and returns
+// {newScore, oldRank, newRank, overtaken, credited} where overtaken holds up to
+// ARGV[3] members (with scores) which were ranked above member before the
+// increment and are ranked below it now, closest first. Ranks are 0-based,
+// oldRank is -1 for members which weren't on the board. Members locked in
+// KEYS[2] and increments outside submission window (KEYS[4]) are rejected,
+// applied increments are logged to KEYS[3].
+var incrementOvertakingScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], ARGV[1]) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+local oldRank = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+local credited = credit(ARGV[1], tonumber(ARGV[2]))
+local newScore = redis.call("ZINCRBY", KEYS[1], credited, ARGV[1])
+if credited > 0 then
+	logOp(KEYS[3], "incr", ARGV[1], credited)
+end
+bumpVersion(KEYS[4])
+local newRank = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+local limit = tonumber(ARGV[3])
+
+local overtaken = {}
+if oldRank == false then
+	oldRank = -1
+elseif newRank < oldRank and limit > 0 then
+	overtaken = redis.call("ZREVRANGE", KEYS[1], newRank + 1, math.min(oldRank, newRank + limit), "WITHSCORES")
+end
+
+return {newScore, oldRank, newRank, overtaken, credited}
+`)
+
+// ScoreUpdate is result of IncrementMemberScoreWithOvertaken.
+type ScoreUpdate struct {
+	User
+	// PreviousRank is member's rank before update, UnrankedMember for new members
+	PreviousRank int `json:"previous_rank"`
+	// Overtaken are members passed by this update, closest first
+	Overtaken []User `json:"overtaken"`
+}
+
+// IncrementMemberScoreWithOvertaken increments member's score like IncrementMemberScore
+// and reports up to maxOvertaken members it passed ("you passed Alice and Bob!").
+// Everything is computed atomically on the server in one round trip.
+func (l *Leaderboard) IncrementMemberScoreWithOvertaken(ctx context.Context, userID string, incrementBy, maxOvertaken int) (update ScoreUpdate, err error) {
+	if incrementBy < 0 {
+		return ScoreUpdate{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	ctx, done, err := l.startMemberOp(ctx, "IncrementMemberScoreWithOvertaken", userID)
+	if err != nil {
+		return ScoreUpdate{}, err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return ScoreUpdate{}, err
+	}
+
+	keys, args, earnedKey := l.appendEarningCap(
+		[]string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey()},
+		[]interface{}{userID, incrementBy, maxOvertaken},
+	)
+	reply, err := incrementOvertakingScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Result()
+	if err != nil {
+		return ScoreUpdate{}, scriptError(err)
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 5 {
+		return ScoreUpdate{}, errUnexpectedScriptReply
+	}
+
+	newScore, err := strconv.ParseFloat(res[0].(string), 64)
+	if err != nil {
+		return ScoreUpdate{}, err
+	}
+
+	oldRank, newRank := res[1].(int64), res[2].(int64)
+	update = ScoreUpdate{
+		User:         l.scoredUser(userID, newScore, int(newRank)+1),
+		PreviousRank: UnrankedMember,
+	}
+
+	if oldRank >= 0 {
+		update.PreviousRank = int(oldRank) + 1
+	}
+
+	overtaken, _ := res[3].([]interface{})
+	for i := 0; i+1 < len(overtaken); i += 2 {
+		score, err := strconv.ParseFloat(overtaken[i+1].(string), 64)
+		if err != nil {
+			return ScoreUpdate{}, err
+		}
+
+		update.Overtaken = append(update.Overtaken, l.scoredUser(overtaken[i].(string), score, update.Rank+1+i/2))
+	}
+
+	// Ghosts ranked up to member's previous rank now were all above it before,
+	// ghosts it has just overtaken included, so previous rank is marked one lower
+	users := append([]User{update.User}, update.Overtaken...)
+	if update.PreviousRank != UnrankedMember {
+		users = append(users, User{UserID: userID, Rank: update.PreviousRank + 1})
+	}
+	if err := l.markGhosts(ctx, l.client(), users); err != nil {
+		return ScoreUpdate{}, err
+	}
+	update.User = users[0]
+	copy(update.Overtaken, users[1:])
+	if update.PreviousRank != UnrankedMember {
+		update.PreviousRank = users[len(users)-1].Rank - 1
+	}
+
+	l.scoreWritten(ctx, userID, newScore)
+	l.mirrorEarned(ctx, earnedKey, userID)
+
+	// Integer increments are credited as integers
+	credited, _ := res[4].(int64)
+	before := User{UserID: userID, Rank: update.PreviousRank}
+	if update.PreviousRank != UnrankedMember {
+		before.Score = update.Score - int(credited)
+	}
+	l.emitScoreChange(ctx, before, update.User)
+
+	return update, nil
+}
@@ -0,0 +1,49 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIncrementMemberScoreWithOvertaken(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		userID        string
+		max           int
+		wantRank      int
+		wantPrevious  int
+		wantOvertaken []string
+		wantRanks     []int
+	}{
+		{"all passed", "a", 5, 2, 4, []string{"c", "b"}, []int{3, 4}},
+		{"limited", "a", 1, 2, 4, []string{"c"}, []int{3}},
+		{"none asked", "a", 0, 2, 4, []string{}, []int{}},
+		{"new member", "e", 5, 2, UnrankedMember, []string{}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 1, "b", 3, "c", 5, "d", 7)
+
+			update, err := l.IncrementMemberScoreWithOvertaken(ctx, tt.userID, 5, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if update.Rank != tt.wantRank || update.PreviousRank != tt.wantPrevious {
+				t.Fatalf("got rank %d from %d, want %d from %d", update.Rank, update.PreviousRank, tt.wantRank, tt.wantPrevious)
+			}
+			if !equalStrings(userIDs(update.Overtaken), tt.wantOvertaken) {
+				t.Fatalf("got overtaken %v, want %v", userIDs(update.Overtaken), tt.wantOvertaken)
+			}
+			for i, user := range update.Overtaken {
+				if user.Rank != tt.wantRanks[i] {
+					t.Fatalf("got rank %d of overtaken %s, want %d", user.Rank, user.UserID, tt.wantRanks[i])
+				}
+			}
+		})
+	}
+}
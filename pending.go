@@ -0,0 +1,228 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PendingVerifier decides whether pending score may be promoted to the board.
+// Returning an error leaves score pending so it's retried later.
+type PendingVerifier func(ctx context.Context, pending User) (approved bool, err error)
+
+// PendingOptions configures verification of suspicious scores, see WithPendingVerification.
+type PendingOptions struct {
+	// Suspicious tells whether submitted score must be verified before it's
+	// ranked on the board, e.g. because it's far above the current leader
+	Suspicious func(userID string, score int) bool
+	// Verify approves or rejects pending scores
+	Verify PendingVerifier
+	// Interval of automatic verification of pending scores, 0 leaves it to ProcessPending calls
+	Interval time.Duration
+	// OnError is called with errors of automatic verification
+	OnError func(error)
+}
+
+// PendingResult summarizes one ProcessPending run.
+type PendingResult struct {
+	Promoted int
+	Rejected int
+	Failed   int
+}
+
+type pendingVerification struct {
+	opts PendingOptions
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Removes member ARGV[1] from KEYS[1] if its score still equals ARGV[2], so
+// score submitted again during verification stays pending.
+var removePendingScript = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if score and tonumber(score) == tonumber(ARGV[2]) then
+	return redis.call("ZREM", KEYS[1], ARGV[1])
+end
+
+return 0
+`)
+
+// WithPendingVerification keeps submissions opts.Suspicious flags out of the
+// board until they're verified: SubmitScore stores them in pending area
+// "<leaderboardName>:pending" and returns member with rank it would have,
+// flagged as Pending. Approved scores are promoted to the board (respecting
+// scoring mode), rejected ones are dropped, either by ProcessPending or
+// automatically every opts.Interval. One score per member is pending, the
+// latest one. Pending scores aren't counted when ranking other members, so
+// members below a pending score keep their ranks until it's approved.
+func WithPendingVerification(opts PendingOptions) Option {
+	return func(l *Leaderboard) {
+		if opts.Suspicious != nil {
+			l.pending = &pendingVerification{opts: opts, stop: make(chan struct{})}
+		}
+	}
+}
+
+func (l *Leaderboard) pendingKey() string {
+	return l.leaderboardName + ":pending"
+}
+
+// suspicious reports whether submission must wait for verification.
+func (l *Leaderboard) suspicious(userID string, score int) bool {
+	return l.pending != nil && l.pending.opts.Suspicious(userID, score)
+}
+
+// holdPending stores suspicious submission in pending area.
+func (l *Leaderboard) holdPending(ctx context.Context, userID string, score int) (User, error) {
+	if err := l.client().ZAdd(ctx, l.pendingKey(), &redis.Z{Score: float64(score), Member: userID}).Err(); err != nil {
+		return User{}, err
+	}
+
+	return l.provisional(ctx, userID, score)
+}
+
+// provisional returns member with score and rank it would have if pending
+// submission was applied now. Other pending scores aren't counted.
+func (l *Leaderboard) provisional(ctx context.Context, userID string, submitted int) (User, error) {
+	current, err := l.client().ZScore(ctx, l.leaderboardName, userID).Result()
+	exists := err == nil
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return User{}, err
+	}
+
+	score := float64(submitted)
+	switch l.scoringMode {
+	case ScoringCumulative:
+		score += current
+	case ScoringBest:
+		if exists && current > score {
+			score = current
+		}
+	}
+
+	above, err := l.client().ZCount(ctx, l.leaderboardName, "("+strconv.FormatFloat(score, 'f', -1, 64), "+inf").Result()
+	if err != nil {
+		return User{}, err
+	}
+
+	user := l.scoredUser(userID, score, int(above)+1)
+	user.Pending = true
+
+	return user, nil
+}
+
+// PendingScores returns members with pending scores, flagged as Pending, with
+// scores and ranks they would have if their scores were approved now.
+func (l *Leaderboard) PendingScores(ctx context.Context) (users []User, err error) {
+	defer l.wrapError("PendingScores", "", &err)
+
+	values, err := l.client().ZRangeWithScores(ctx, l.pendingKey(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	users = make([]User, 0, len(values))
+	for _, z := range values {
+		user, err := l.provisional(ctx, z.Member.(string), int(z.Score))
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// ProcessPending verifies all pending scores with PendingOptions.Verify,
+// promoting approved scores to the board and dropping rejected ones.
+func (l *Leaderboard) ProcessPending(ctx context.Context) (result PendingResult, err error) {
+	done, err := l.trackOp("ProcessPending")
+	if err != nil {
+		return PendingResult{}, err
+	}
+	defer done(&err)
+
+	if l.pending == nil || l.pending.opts.Verify == nil {
+		return result, ErrNilVerifier
+	}
+
+	values, err := l.client().ZRangeWithScores(ctx, l.pendingKey(), 0, -1).Result()
+	if err != nil {
+		return result, err
+	}
+
+	for _, z := range values {
+		userID, score := z.Member.(string), int(z.Score)
+
+		user, err := l.provisional(ctx, userID, score)
+		if err != nil {
+			return result, err
+		}
+		// Verifier judges the submitted score, not the projected one
+		user.Score = score
+
+		approved, err := l.pending.opts.Verify(ctx, user)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+
+		if approved {
+			if _, err := l.submitScore(ctx, userID, score, ""); err != nil {
+				result.Failed++
+				continue
+			}
+			result.Promoted++
+		} else {
+			result.Rejected++
+		}
+
+		if err := removePendingScript.Run(ctx, l.client(), []string{l.pendingKey()}, userID, score).Err(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (l *Leaderboard) startPendingVerifier() {
+	p := l.pending
+	if p == nil || p.opts.Verify == nil || p.opts.Interval <= 0 {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.opts.Interval)
+		defer ticker.Stop()
+
+		ctx := context.Background()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				if _, err := l.ProcessPending(ctx); err != nil && p.opts.OnError != nil {
+					p.opts.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+func (l *Leaderboard) stopPendingVerifier() {
+	if p := l.pending; p != nil {
+		p.stopOnce.Do(func() {
+			close(p.stop)
+		})
+		p.wg.Wait()
+	}
+}
@@ -0,0 +1,114 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestPendingBoard(t *testing.T, interval time.Duration) *Leaderboard {
+	t.Helper()
+
+	l, _ := newTestBoard(t, WithPendingVerification(PendingOptions{
+		Suspicious: func(userID string, score int) bool {
+			return score >= 100
+		},
+		Verify: func(ctx context.Context, pending User) (bool, error) {
+			return pending.UserID != "cheater", nil
+		},
+		Interval: interval,
+	}))
+	seed(t, l, "x", 50)
+
+	return l
+}
+
+func TestProcessPendingPromotesApprovedScores(t *testing.T) {
+	l := newTestPendingBoard(t, 0)
+	ctx := context.Background()
+
+	user, err := l.SubmitScore(ctx, "a", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.Pending || user.Rank != 1 || user.Score != 150 {
+		t.Fatalf("got %+v, want pending score 150 at rank 1", user)
+	}
+	for _, s := range []struct {
+		userID string
+		score  int
+	}{{"cheater", 200}, {"b", 10}} {
+		if _, err := l.SubmitScore(ctx, s.userID, s.score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending, err := l.PendingScores(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(pending), []string{"a", "cheater"}) {
+		t.Fatalf("got pending %v, want a and cheater", userIDs(pending))
+	}
+
+	result, err := l.ProcessPending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (PendingResult{Promoted: 1, Rejected: 1}) {
+		t.Fatalf("got %+v, want 1 promoted and 1 rejected", result)
+	}
+
+	users, err := l.GetLeaders(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalStrings(userIDs(users), []string{"a", "x", "b"}) {
+		t.Fatalf("got %v, want [a x b]", userIDs(users))
+	}
+	if n := l.client().ZCard(ctx, l.pendingKey()).Val(); n != 0 {
+		t.Fatalf("got %d pending scores left, want 0", n)
+	}
+}
+
+func TestPendingScoresVerifiedInBackground(t *testing.T) {
+	l := newTestPendingBoard(t, 10*time.Millisecond)
+	ctx := context.Background()
+
+	if _, err := l.SubmitScore(ctx, "a", 150); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for l.client().ZScore(ctx, l.leaderboardName, "a").Val() != 150 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending score wasn't promoted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPendingScoreKeepsDecimals(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t, WithScoringMode(ScoringCumulative), WithPrecision(PrecisionOneDecimal), WithPendingVerification(PendingOptions{
+		Suspicious: func(userID string, score int) bool {
+			return score >= 100
+		},
+	}))
+
+	if _, err := l.IncrementMemberScoreDecimal(ctx, "a", 0.6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.IncrementMemberScoreDecimal(ctx, "b", 100.5); err != nil {
+		t.Fatal(err)
+	}
+
+	// 100.6 projected is above b, truncated 100 wouldn't be
+	user, err := l.SubmitScore(ctx, "a", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.Pending || user.Rank != 1 || user.Score != 101 || user.PreciseScore != 100.6 {
+		t.Fatalf("got %+v, want pending score 100.6 at rank 1", user)
+	}
+}
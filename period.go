@@ -0,0 +1,186 @@
+package go_redis_leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// PeriodCustom is kind of periods with arbitrary labels, e.g. "season-3".
+	PeriodCustom = "custom"
+	// PeriodInterval is kind of periods of fixed duration, e.g. 6 hours, see IntervalPeriodOf.
+	PeriodInterval = "interval"
+
+	intervalPeriodLayout = "2006-01-02T1504"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("leaderboard: period must be formatted like 2006-01-02, 2006-W01, 2006-01, 2006-01-02T1504 or be a custom label")
+)
+
+var (
+	weeklyPeriodPattern = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+	customPeriodPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// Period identifies one period of a periodic board, so callers don't format
+// period keys like "2024-W12" by hand. Its String is the key used in names of
+// periodic boards.
+type Period struct {
+	// Kind is PeriodDaily, PeriodWeekly, PeriodMonthly, PeriodInterval or PeriodCustom
+	Kind string
+	// Start is start of the period, zero for custom periods
+	Start time.Time
+	// Label of custom period
+	Label string
+	// Duration of interval period
+	Duration time.Duration
+}
+
+// PeriodOf returns daily, weekly (ISO week) or monthly period t falls into,
+// aligned to loc (UTC when nil).
+func PeriodOf(kind string, t time.Time, loc *time.Location) (Period, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t = t.In(loc)
+	year, month, day := t.Date()
+
+	switch kind {
+	case PeriodDaily:
+		return Period{Kind: kind, Start: time.Date(year, month, day, 0, 0, 0, 0, loc)}, nil
+	case PeriodWeekly:
+		// ISO weeks start on Monday
+		return Period{Kind: kind, Start: time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)}, nil
+	case PeriodMonthly:
+		return Period{Kind: kind, Start: time.Date(year, month, 1, 0, 0, 0, 0, loc)}, nil
+	}
+
+	return Period{}, ErrUnknownPeriod
+}
+
+// IntervalPeriodOf returns period of duration d t falls into, periods being
+// counted from epoch, e.g. 6 hour periods starting at midnight with epoch at
+// any midnight. d is expected to be whole minutes.
+func IntervalPeriodOf(t, epoch time.Time, d time.Duration) Period {
+	n := t.Sub(epoch) / d
+	if t.Before(epoch.Add(n * d)) {
+		n--
+	}
+
+	return Period{Kind: PeriodInterval, Start: epoch.Add(n * d), Duration: d}
+}
+
+// CustomPeriod returns period with arbitrary label made of letters, digits,
+// '_', '.' and '-'.
+func CustomPeriod(label string) (Period, error) {
+	if !customPeriodPattern.MatchString(label) {
+		return Period{}, ErrInvalidPeriod
+	}
+
+	return Period{Kind: PeriodCustom, Label: label}, nil
+}
+
+// ParsePeriod parses period formatted by Period.String, aligned to loc (UTC
+// when nil). Keys not looking like dates are parsed as custom periods.
+// Duration of interval periods isn't part of their key and is left zero, see
+// PeriodicLeaderboard.ParsePeriod.
+func ParsePeriod(s string, loc *time.Location) (Period, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if t, err := time.ParseInLocation(intervalPeriodLayout, s, loc); err == nil {
+		return Period{Kind: PeriodInterval, Start: t}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return Period{Kind: PeriodDaily, Start: t}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", s, loc); err == nil {
+		return Period{Kind: PeriodMonthly, Start: t}, nil
+	}
+
+	if m := weeklyPeriodPattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+
+		// January 4th is always in ISO week 1
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+		period, _ := PeriodOf(PeriodWeekly, jan4, loc)
+		period = period.Add(week - 1)
+
+		if y, w := period.Start.ISOWeek(); week < 1 || y != year || w != week {
+			return Period{}, ErrInvalidPeriod
+		}
+
+		return period, nil
+	}
+
+	return CustomPeriod(s)
+}
+
+// String returns key of the period: "2006-01-02" for daily, ISO week
+// "2006-W01" for weekly, "2006-01" for monthly, start like "2006-01-02T1504"
+// for interval and label for custom periods.
+func (p Period) String() string {
+	switch p.Kind {
+	case PeriodDaily:
+		return p.Start.Format("2006-01-02")
+	case PeriodWeekly:
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonthly:
+		return p.Start.Format("2006-01")
+	case PeriodInterval:
+		return p.Start.Format(intervalPeriodLayout)
+	}
+
+	return p.Label
+}
+
+// Add returns period n periods after p (before it for negative n). Custom
+// periods are returned unchanged.
+func (p Period) Add(n int) Period {
+	switch p.Kind {
+	case PeriodDaily:
+		p.Start = p.Start.AddDate(0, 0, n)
+	case PeriodWeekly:
+		p.Start = p.Start.AddDate(0, 0, 7*n)
+	case PeriodMonthly:
+		p.Start = p.Start.AddDate(0, n, 0)
+	case PeriodInterval:
+		p.Start = p.Start.Add(time.Duration(n) * p.Duration)
+	}
+
+	return p
+}
+
+// Next returns period following p.
+func (p Period) Next() Period {
+	return p.Add(1)
+}
+
+// Previous returns period preceding p.
+func (p Period) Previous() Period {
+	return p.Add(-1)
+}
+
+// End returns start of the next period, zero for custom periods.
+func (p Period) End() time.Time {
+	if p.Kind == PeriodCustom {
+		return time.Time{}
+	}
+
+	return p.Next().Start
+}
+
+// Contains reports whether t falls into the period, false for custom periods.
+func (p Period) Contains(t time.Time) bool {
+	return p.Kind != PeriodCustom && !t.Before(p.Start) && t.Before(p.End())
+}
@@ -0,0 +1,292 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+
+	// periodicCheckInterval is how often background maintenance of periodic boards runs
+	periodicCheckInterval = time.Minute
+)
+
+var (
+	ErrUnknownPeriod   = errors.New("leaderboard: period must be daily, weekly or monthly")
+	ErrInvalidInterval = errors.New("leaderboard: interval of periodic board must be positive whole minutes")
+)
+
+// PeriodicOptions configures PeriodicLeaderboard.
+type PeriodicOptions struct {
+	// Period is PeriodDaily, PeriodWeekly, PeriodMonthly or PeriodInterval
+	Period string
+	// Interval is duration of PeriodInterval periods, e.g. 6 hours
+	Interval time.Duration
+	// Epoch is start of some PeriodInterval period, midnight of 1970-01-01 in Location by default
+	Epoch time.Time
+	// Location periods are aligned to, UTC by default
+	Location *time.Location
+	// Retention is number of past periods kept before their keys expire, 0 keeps them forever
+	Retention int
+	// PrecreateBefore makes next period's board prepared that long before rollover, see Precreate.
+	// 0 disables background preparation.
+	PrecreateBefore time.Duration
+	// BoardOptions are applied to board of every period
+	BoardOptions []Option
+}
+
+// PeriodicLeaderboard is a board which starts over every period (day, week,
+// month or custom interval). Board of each period is a regular Leaderboard named "<name>:<period key>",
+// e.g. "weekly_kills:2024-W07". All of them are created through manager, so
+// they share one client and one profile store.
+type PeriodicLeaderboard struct {
+	name     string
+	pageSize int
+	opts     PeriodicOptions
+	manager  *Manager
+
+	mu       sync.Mutex
+	prepared string
+
+	// indexMu guards indexed on its own, as boards are opened during Precreate
+	indexMu sync.Mutex
+	indexed map[string]bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewPeriodicLeaderboard is constructor for PeriodicLeaderboard. When
+// opts.PrecreateBefore is set, next period's board is prepared in background
+// until Stop is called.
+func NewPeriodicLeaderboard(manager *Manager, name string, pageSize int, opts PeriodicOptions) (*PeriodicLeaderboard, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	switch opts.Period {
+	case PeriodDaily, PeriodWeekly, PeriodMonthly:
+	case PeriodInterval:
+		if opts.Interval <= 0 || opts.Interval%time.Minute != 0 {
+			return nil, ErrInvalidInterval
+		}
+		if opts.Epoch.IsZero() {
+			opts.Epoch = time.Date(1970, time.January, 1, 0, 0, 0, 0, opts.Location)
+		}
+		opts.Epoch = opts.Epoch.In(opts.Location)
+	default:
+		return nil, ErrUnknownPeriod
+	}
+
+	p := &PeriodicLeaderboard{name: name, pageSize: pageSize, opts: opts, manager: manager, stop: make(chan struct{})}
+
+	if opts.PrecreateBefore > 0 {
+		p.wg.Add(1)
+		go p.maintain()
+	}
+
+	return p, nil
+}
+
+// PeriodAt returns period t falls into.
+func (p *PeriodicLeaderboard) PeriodAt(t time.Time) Period {
+	if p.opts.Period == PeriodInterval {
+		return IntervalPeriodOf(t, p.opts.Epoch, p.opts.Interval)
+	}
+
+	// Period kind is validated by NewPeriodicLeaderboard
+	period, _ := PeriodOf(p.opts.Period, t, p.opts.Location)
+
+	return period
+}
+
+// PeriodKey returns key of period t falls into: "2006-01-02" for daily,
+// ISO week "2006-W01" for weekly, "2006-01" for monthly and period's start
+// like "2006-01-02T1504" for interval boards.
+func (p *PeriodicLeaderboard) PeriodKey(t time.Time) string {
+	return p.PeriodAt(t).String()
+}
+
+// ParsePeriod parses period key of the board, e.g. one from a board name or
+// an API request, in board's location.
+func (p *PeriodicLeaderboard) ParsePeriod(key string) (Period, error) {
+	period, err := ParsePeriod(key, p.opts.Location)
+	if err != nil {
+		return Period{}, err
+	}
+
+	if period.Kind == PeriodInterval {
+		if p.opts.Period != PeriodInterval || !IntervalPeriodOf(period.Start, p.opts.Epoch, p.opts.Interval).Start.Equal(period.Start) {
+			return Period{}, ErrInvalidPeriod
+		}
+		period.Duration = p.opts.Interval
+	}
+
+	return period, nil
+}
+
+// expiresAt returns time keys of period expire at, zero time if they don't.
+func (p *PeriodicLeaderboard) expiresAt(period Period) time.Time {
+	if p.opts.Retention <= 0 || period.Kind == PeriodCustom {
+		return time.Time{}
+	}
+
+	return period.Add(p.opts.Retention + 1).Start
+}
+
+// At returns board of period t falls into.
+func (p *PeriodicLeaderboard) At(ctx context.Context, t time.Time) (*Leaderboard, error) {
+	return p.Board(ctx, p.PeriodAt(t))
+}
+
+// Board returns board of period, which may also be a custom period, e.g.
+// CustomPeriod("season-3"). Calendar periods are recorded in index of periods
+// listed by ArchivedPeriods, failing to record one fails the call.
+func (p *PeriodicLeaderboard) Board(ctx context.Context, period Period) (*Leaderboard, error) {
+	l, err := p.board(period)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Kind != PeriodCustom {
+		if err := p.index(ctx, l, period); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (p *PeriodicLeaderboard) board(period Period) (*Leaderboard, error) {
+	return p.manager.Leaderboard(p.name+":"+period.String(), p.pageSize, p.opts.BoardOptions...)
+}
+
+// Current returns board of the current period.
+func (p *PeriodicLeaderboard) Current(ctx context.Context) (*Leaderboard, error) {
+	return p.At(ctx, time.Now())
+}
+
+// Previous returns board of the period before the current one, e.g.
+// yesterday's board of a daily board, for "last week's winners" screens.
+func (p *PeriodicLeaderboard) Previous(ctx context.Context) (*Leaderboard, error) {
+	return p.Board(ctx, p.PeriodAt(time.Now()).Previous())
+}
+
+// Precreate prepares board of period t falls into before its first write:
+// metadata (score unit, schema version, ...) of the current period's board is
+// copied unless already set and expiry is applied, so first writes of a new
+// period don't race to initialize configuration. It's safe to call repeatedly
+// and from several instances.
+func (p *PeriodicLeaderboard) Precreate(ctx context.Context, t time.Time) error {
+	current, err := p.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	next, err := p.At(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	if next.leaderboardName == current.leaderboardName {
+		return p.applyExpiry(ctx, next, p.PeriodAt(t))
+	}
+
+	meta, err := current.client().HGetAll(ctx, current.metaKey()).Result()
+	if err != nil {
+		return err
+	}
+	delete(meta, metaLastWriteAt)
+	delete(meta, metaVersion)
+
+	pipe := next.client().Pipeline()
+	for field, value := range meta {
+		pipe.HSetNX(ctx, next.metaKey(), field, value)
+	}
+	// Meta hash is created even when there's nothing to copy, so it can carry expiry
+	pipe.HSetNX(ctx, next.metaKey(), metaSchemaVersion, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return p.applyExpiry(ctx, next, p.PeriodAt(t))
+}
+
+// applyExpiry sets expiry of board's keys. Sorted set can't exist before its
+// first member, so expiry is re-applied periodically while period is active.
+func (p *PeriodicLeaderboard) applyExpiry(ctx context.Context, l *Leaderboard, period Period) error {
+	expiresAt := p.expiresAt(period)
+	if expiresAt.IsZero() {
+		return nil
+	}
+
+	pipe := l.client().Pipeline()
+	pipe.ExpireAt(ctx, l.metaKey(), expiresAt)
+	pipe.ExpireAt(ctx, l.leaderboardName, expiresAt)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// maintain prepares next period's board PrecreateBefore rollover and keeps
+// expiry of the current one applied. Errors are retried on next check.
+func (p *PeriodicLeaderboard) maintain() {
+	defer p.wg.Done()
+
+	interval := periodicCheckInterval
+	if p.opts.PrecreateBefore < 2*interval {
+		interval = p.opts.PrecreateBefore / 2
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.tick(ctx, now)
+		}
+	}
+}
+
+func (p *PeriodicLeaderboard) tick(ctx context.Context, now time.Time) {
+	if current, err := p.Current(ctx); err == nil {
+		_ = p.applyExpiry(ctx, current, p.PeriodAt(now))
+	}
+
+	next := p.PeriodAt(now).Next()
+	if next.Start.Sub(now) > p.opts.PrecreateBefore {
+		return
+	}
+
+	key := next.String()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.prepared == key {
+		return
+	}
+
+	if err := p.Precreate(ctx, next.Start); err == nil {
+		p.prepared = key
+	}
+}
+
+// Stop stops background preparation of next periods.
+func (p *PeriodicLeaderboard) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+}
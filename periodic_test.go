@@ -0,0 +1,59 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"testing"
+	"time"
+)
+
+func newTestPeriodicBoard(t *testing.T, opts PeriodicOptions) *PeriodicLeaderboard {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	m := NewManager(RedisSettings{Host: mr.Addr()}, StagingMode, "test_info", nil)
+	t.Cleanup(func() {
+		_ = m.Close()
+	})
+
+	p, err := NewPeriodicLeaderboard(m, "test", 10, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Stop)
+
+	return p
+}
+
+func TestPeriodicTickPrecreatesNextPeriod(t *testing.T) {
+	p := newTestPeriodicBoard(t, PeriodicOptions{Period: PeriodDaily, PrecreateBefore: 48 * time.Hour})
+	ctx := context.Background()
+	now := time.Now()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.tick(ctx, now)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tick didn't return")
+	}
+
+	next := p.PeriodAt(now).Next()
+	if p.prepared != next.String() {
+		t.Fatalf("got prepared %q, want %q", p.prepared, next.String())
+	}
+
+	cli := p.manager.client()
+	if n := cli.Exists(ctx, "test:"+next.String()+":meta").Val(); n != 1 {
+		t.Fatal("next period's metadata wasn't created")
+	}
+
+	periods := cli.ZRange(ctx, p.periodsKey(), 0, -1).Val()
+	if !equalStrings(periods, []string{p.PeriodAt(now).String(), next.String()}) {
+		t.Fatalf("got indexed periods %v, want current and next", periods)
+	}
+}
@@ -0,0 +1,107 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"math"
+	"strconv"
+)
+
+// Precision tells how scores of the board are rounded, see WithPrecision.
+type Precision int
+
+const (
+	// PrecisionInteger rounds scores to whole numbers, halves away from zero, the default
+	PrecisionInteger Precision = iota
+	// PrecisionOneDecimal keeps one decimal place, halves are rounded away from
+	// zero. Score of members is rounded to whole number, PreciseScore has the decimal.
+	PrecisionOneDecimal
+	// PrecisionBankers rounds scores to whole numbers, halves to even
+	PrecisionBankers
+)
+
+// WithPrecision sets how scores are rounded. Decimal increments
+// (IncrementMemberScoreDecimal) and Reweigh results are rounded before they're
+// stored, scores read from Redis are rounded the same way by every read
+// instead of being truncated to int.
+func WithPrecision(p Precision) Option {
+	return func(l *Leaderboard) {
+		switch p {
+		case PrecisionInteger, PrecisionOneDecimal, PrecisionBankers:
+			l.precision = p
+		}
+	}
+}
+
+// round rounds score to precision.
+func (p Precision) round(score float64) float64 {
+	switch p {
+	case PrecisionOneDecimal:
+		return math.Round(score*10) / 10
+	case PrecisionBankers:
+		return math.RoundToEven(score)
+	default:
+		return math.Round(score)
+	}
+}
+
+// whole rounds score to whole number reported as User.Score.
+func (p Precision) whole(score float64) int {
+	if p == PrecisionBankers {
+		return int(math.RoundToEven(score))
+	}
+
+	return int(math.Round(score))
+}
+
+// user returns member with score read from Redis rounded to precision.
+func (p Precision) user(userID string, score float64, rank int) User {
+	user := User{UserID: userID, Score: p.whole(score), Rank: rank}
+	if p == PrecisionOneDecimal {
+		user.PreciseScore = p.round(score)
+	}
+
+	return user
+}
+
+// scoredUser returns member with score read from Redis rounded to board's precision.
+func (l *Leaderboard) scoredUser(userID string, score float64, rank int) User {
+	return l.precision.user(userID, score, rank)
+}
+
+// IncrementMemberScoreDecimal is IncrementMemberScore for fractional
+// increments, e.g. 2.5 points. Increment is rounded to board's precision first,
+// so on boards with whole number precision it may round to 0.
+func (l *Leaderboard) IncrementMemberScoreDecimal(ctx context.Context, userID string, incrementBy float64) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "IncrementMemberScoreDecimal", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if incrementBy < 0 || math.IsNaN(incrementBy) || math.IsInf(incrementBy, 0) {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	before := l.memberBefore(ctx, userID)
+
+	increment := strconv.FormatFloat(l.precision.round(incrementBy), 'f', -1, 64)
+	score, _, err := l.runIncrement(ctx, l.leaderboardName, userID, increment, l.scoreLogFlag())
+	if err != nil {
+		return User{}, err
+	}
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	user = l.scoredUser(userID, score, rank)
+	l.scoreWritten(ctx, userID, score)
+	l.emitScoreChange(ctx, before, user)
+
+	return user, nil
+}
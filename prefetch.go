@@ -0,0 +1,95 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultPrefetchParallelism is number of pages PrefetchPages fetches at once.
+const DefaultPrefetchParallelism = 4
+
+// PrefetchPages fetches pages from to to (inclusive) concurrently, at most
+// DefaultPrefetchParallelism at once, for exports or infinite-scroll views.
+// Pages are returned in order; pages past the end of the board are left out
+// instead of being clamped to the last one like in GetLeaders.
+func (l *Leaderboard) PrefetchPages(ctx context.Context, from, to int, opts ...ReadOption) (pages [][]User, err error) {
+	ctx, done, err := l.startOp(ctx, "PrefetchPages")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if from < 1 {
+		from = 1
+	}
+
+	o := newReadOptions(opts)
+	pageSize := l.pageSize(o)
+
+	total, err := l.countMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastPage := (total + pageSize - 1) / pageSize; to > lastPage {
+		to = lastPage
+	}
+
+	if to < from {
+		return [][]User{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages = make([][]User, to-from+1)
+	numbers := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		fetchErr error
+	)
+
+	for w := 0; w < DefaultPrefetchParallelism && w < len(pages); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for page := range numbers {
+				startOffset := (page - 1) * pageSize
+				users, err := l.readRange(ctx, o, startOffset, startOffset+pageSize-1, nil)
+				if err != nil {
+					errOnce.Do(func() {
+						fetchErr = err
+						cancel()
+					})
+					continue
+				}
+
+				pages[page-from] = users
+			}
+		}()
+	}
+
+feed:
+	for page := from; page <= to; page++ {
+		select {
+		case numbers <- page:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,55 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestPrefetchPages(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	// 35 members make 4 pages of 10, the last one partial
+	for i := 0; i < 35; i++ {
+		seed(t, l, "u"+strconv.Itoa(i), i)
+	}
+
+	tests := []struct {
+		name      string
+		from, to  int
+		wantSizes []int
+	}{
+		{"all", 1, 4, []int{10, 10, 10, 5}},
+		{"middle", 2, 3, []int{10, 10}},
+		{"past the end", 3, 9, []int{10, 5}},
+		{"from below first", -1, 1, []int{10}},
+		{"only past the end", 5, 6, []int{}},
+		{"reversed", 3, 2, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := l.PrefetchPages(ctx, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pages) != len(tt.wantSizes) {
+				t.Fatalf("got %d pages, want %d", len(pages), len(tt.wantSizes))
+			}
+
+			first := tt.from
+			if first < 1 {
+				first = 1
+			}
+			for i, page := range pages {
+				if len(page) != tt.wantSizes[i] {
+					t.Fatalf("got %d members on page %d, want %d", len(page), first+i, tt.wantSizes[i])
+				}
+				if want := (first+i-1)*10 + 1; page[0].Rank != want {
+					t.Fatalf("got first rank %d on page %d, want %d", page[0].Rank, first+i, want)
+				}
+			}
+		})
+	}
+}
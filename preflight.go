@@ -0,0 +1,184 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPreflightFailed = errors.New("leaderboard: preflight failed")
+)
+
+// KeyTypeMismatch is existing key holding another type than the board expects.
+type KeyTypeMismatch struct {
+	Key      string
+	Expected string
+	Actual   string
+}
+
+// CapabilityReport describes Redis server the board talks to, see Preflight.
+type CapabilityReport struct {
+	ServerVersion string
+	// Streams are required by events, submissions and score log (Redis 5+)
+	Streams bool
+	// ClientTracking is required by WithClientSideCache (Redis 6+)
+	ClientTracking bool
+	RedisJSON      bool
+	RediSearch     bool
+	// Modules are names of loaded modules
+	Modules []string
+	// KeyTypeMismatches lists existing keys of the board holding wrong types,
+	// e.g. because another application uses the same key names
+	KeyTypeMismatches []KeyTypeMismatch
+	// Problems lists everything that makes Preflight fail
+	Problems []string
+}
+
+// Preflight checks that the board can work against its Redis server, so
+// misconfiguration fails at deploy time instead of at the first user request:
+// connection is verified, server version and loaded modules are probed, and
+// existing keys of the board are checked to hold expected types. The report is
+// returned together with ErrPreflightFailed when connection works but a
+// problem is found (wrong key types, features enabled on the board which the
+// server doesn't support); Problems tells what went wrong.
+func (l *Leaderboard) Preflight(ctx context.Context) (report CapabilityReport, err error) {
+	defer l.wrapError("Preflight", "", &err)
+
+	cli := l.client()
+	if err := cli.Ping(ctx).Err(); err != nil {
+		return report, err
+	}
+
+	info, err := cli.Info(ctx, "server").Result()
+	if err != nil {
+		return report, err
+	}
+	report.ServerVersion = infoField(info, "redis_version")
+	major := serverMajorVersion(report.ServerVersion)
+	report.Streams = major >= 5
+	report.ClientTracking = major >= 6
+
+	// MODULE LIST fails on servers without module support, which means no modules
+	if modules, err := cli.Do(ctx, "module", "list").Result(); err == nil {
+		report.Modules = moduleNames(modules)
+	}
+	for _, module := range report.Modules {
+		switch strings.ToLower(module) {
+		case "rejson", "redisjson":
+			report.RedisJSON = true
+		case "search", "ft":
+			report.RediSearch = true
+		}
+	}
+
+	if report.KeyTypeMismatches, err = l.keyTypeMismatches(ctx); err != nil {
+		return report, err
+	}
+	for _, m := range report.KeyTypeMismatches {
+		report.Problems = append(report.Problems, m.Key+" holds "+m.Actual+" instead of "+m.Expected)
+	}
+
+	if !report.Streams && (l.eventsEnabled() || l.eventSourced) {
+		report.Problems = append(report.Problems, "events and event sourcing require streams (Redis 5+)")
+	}
+	if !report.ClientTracking && l.clientCache != nil {
+		report.Problems = append(report.Problems, "client side cache requires client tracking (Redis 6+)")
+	}
+
+	if len(report.Problems) > 0 {
+		return report, ErrPreflightFailed
+	}
+
+	return report, nil
+}
+
+// keyTypeMismatches checks types of board's existing keys in one pipeline.
+func (l *Leaderboard) keyTypeMismatches(ctx context.Context) ([]KeyTypeMismatch, error) {
+	expected := map[string]string{
+		l.leaderboardName:     "zset",
+		l.profiles.hashName:   "hash",
+		l.metaKey():           "hash",
+		l.eventsStream():      "stream",
+		l.submissionsStream(): "stream",
+		l.quarantineStream():  "stream",
+		l.scoreLogKey():       "stream",
+		l.locksKey():          "hash",
+		l.tagsKey():           "set",
+		l.freshnessKey():      "zset",
+		l.topKey():            "zset",
+		l.recordsKey():        "hash",
+		l.headToHeadKey():     "hash",
+		l.sourcesKey():        "hash",
+		l.displayKey():        "zset",
+		l.displayIndexKey():   "hash",
+		l.pendingKey():        "zset",
+		l.baselineKey():       "zset",
+		l.snapshotsKey():      "zset",
+		l.ghostsKey():         "set",
+		l.deadLetterStream():  "stream",
+	}
+
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+
+	pipe := l.client().Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var mismatches []KeyTypeMismatch
+	for i, key := range keys {
+		actual := typeCmds[i].Val()
+		if actual != "none" && actual != expected[key] {
+			mismatches = append(mismatches, KeyTypeMismatch{Key: key, Expected: expected[key], Actual: actual})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// infoField returns value of field in INFO reply.
+func infoField(info, field string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, field+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, field+":"))
+		}
+	}
+
+	return ""
+}
+
+// serverMajorVersion returns major version of "x.y.z" version, 0 if it's unknown.
+func serverMajorVersion(version string) int {
+	major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+
+	return major
+}
+
+// moduleNames extracts names from MODULE LIST reply, a list of flat name/value pairs.
+func moduleNames(reply interface{}) []string {
+	modules, _ := reply.([]interface{})
+
+	var names []string
+	for _, module := range modules {
+		fields, _ := module.([]interface{})
+		for i := 0; i+1 < len(fields); i += 2 {
+			if key, _ := fields[i].(string); key == "name" {
+				if name, ok := fields[i+1].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,137 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// ProfileStore keeps AdditionalInfo of users in one hash. It may be owned by a
+// single Leaderboard or shared by all boards created through a Manager
+// ("one profile store, many boards").
+type ProfileStore struct {
+	hashName string
+	keyring  *Keyring
+	client   func() redis.UniversalClient
+	// shared stores aren't cleaned up when member is removed from one of the boards
+	shared bool
+	schema *InfoSchema
+}
+
+// HashName returns name of the hash holding user info.
+func (p *ProfileStore) HashName() string {
+	return p.hashName
+}
+
+// Get returns raw JSON info of user. redis.Nil is returned if user has no info.
+func (p *ProfileStore) Get(ctx context.Context, userID string) ([]byte, error) {
+	return p.get(ctx, userID)
+}
+
+// Upsert stores user's info, replacing existing one.
+func (p *ProfileStore) Upsert(ctx context.Context, userID string, additionalData AdditionalUserInfo) error {
+	_, err := p.upsert(ctx, userID, additionalData, nil)
+
+	return err
+}
+
+// Delete removes user's info, including localized info.
+func (p *ProfileStore) Delete(ctx context.Context, userID string) error {
+	return p.delete(ctx, userID)
+}
+
+// Reencrypt seals every value stored in plaintext or with a non-primary key
+// using the current primary key, see Keyring.Rotate.
+func (p *ProfileStore) Reencrypt(ctx context.Context) (updated int, err error) {
+	return p.reencrypt(ctx, nil)
+}
+
+func (p *ProfileStore) get(ctx context.Context, userID string) ([]byte, error) {
+	return getMemberInfo(ctx, p.client(), p.hashName, userID, p.keyring)
+}
+
+func (p *ProfileStore) delete(ctx context.Context, userID string) error {
+	if err := p.client().HDel(ctx, p.hashName, userID).Err(); err != nil {
+		return err
+	}
+
+	return p.deleteLocalized(ctx, userID)
+}
+
+// upsert stores user's info and returns value as it was written to Redis.
+// Commands queued by also, when not nil, run in the same MULTI as the write.
+func (p *ProfileStore) upsert(ctx context.Context, userID string, additionalData AdditionalUserInfo, also func(pipe redis.Pipeliner)) (string, error) {
+	value, err := p.encode(additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	pipe := p.client().TxPipeline()
+	pipe.HSet(ctx, p.hashName, userID, value)
+	if also != nil {
+		also(pipe)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// encode validates info and returns value to be written to Redis.
+func (p *ProfileStore) encode(additionalData AdditionalUserInfo) (string, error) {
+	if err := p.validate(additionalData); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(&additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptMemberInfo(p.keyring, string(data))
+}
+
+// load fills in AdditionalInfo of users with one pipelined round trip through cli.
+func (p *ProfileStore) load(ctx context.Context, cli redis.UniversalClient, users []User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	pipe := cli.Pipeline()
+	cmds := make([]*redis.StringCmd, len(users))
+	for i, u := range users {
+		cmds[i] = pipe.HGet(ctx, p.hashName, u.UserID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	for i, cmd := range cmds {
+		storedData, err := cmd.Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return err
+		}
+
+		if users[i].AdditionalInfo, err = decodeMemberInfo(storedData, p.keyring); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withKeyring returns copy of store sealing values with keyring. Copy is used
+// so encryption enabled on one board doesn't leak into other boards sharing the hash.
+func (p *ProfileStore) withKeyring(keyring *Keyring) *ProfileStore {
+	cp := *p
+	cp.keyring = keyring
+
+	return &cp
+}
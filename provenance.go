@@ -0,0 +1,133 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+var (
+	ErrMissingSource = errors.New("leaderboard: source of the score must not be empty")
+)
+
+// Increments member's score like incrementScoreScript and adds credited points
+// to total of source ARGV[3] in KEYS[5], returns {newScore, sourceTotal}.
+var incrementFromSourceScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], ARGV[1]) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+local credited = credit(ARGV[1], tonumber(ARGV[2]))
+local newScore = redis.call("ZINCRBY", KEYS[1], credited, ARGV[1])
+if credited > 0 then
+	logOp(KEYS[3], "incr", ARGV[1], credited)
+end
+local total = redis.call("HINCRBY", KEYS[5], ARGV[3], credited)
+bumpVersion(KEYS[4])
+
+return {newScore, total}
+`)
+
+// sourcesKey is hash of points earned per source, see IncrementMemberScoreFrom.
+func (l *Leaderboard) sourcesKey() string {
+	return l.leaderboardName + ":sources"
+}
+
+// IncrementMemberScoreFrom is IncrementMemberScore labelling the points with
+// source they originate from (game mode, service, ...). Points are added to
+// source's total in the same script as the score, see ScoreBySource, and
+// source is set on the emitted event.
+func (l *Leaderboard) IncrementMemberScoreFrom(ctx context.Context, userID string, incrementBy int, source string) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "IncrementMemberScoreFrom", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if incrementBy < 0 {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	if source == "" {
+		return User{}, ErrMissingSource
+	}
+
+	before := l.memberBefore(ctx, userID)
+
+	keys, args, earnedKey := l.appendEarningCap(
+		[]string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey(), l.sourcesKey()},
+		[]interface{}{userID, incrementBy, source},
+	)
+	reply, err := incrementFromSourceScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Result()
+	if err != nil {
+		return User{}, scriptError(err)
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return User{}, errUnexpectedScriptReply
+	}
+
+	scoreText, _ := res[0].(string)
+	floatScore, err := parseScore(scoreText)
+	if err != nil {
+		return User{}, err
+	}
+	total, _ := res[1].(int64)
+
+	newScore := l.precision.whole(floatScore)
+	l.scoreWritten(ctx, userID, floatScore)
+	l.mirrorHashField(ctx, l.sourcesKey(), source, strconv.FormatInt(total, 10))
+	l.mirrorEarned(ctx, earnedKey, userID)
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	user = User{UserID: userID, Score: newScore, Rank: rank}
+	l.emit(ctx, Event{
+		Type:     EventRankChanged,
+		UserID:   userID,
+		OldRank:  before.Rank,
+		NewRank:  user.Rank,
+		OldScore: before.Score,
+		NewScore: user.Score,
+		Source:   source,
+	})
+
+	return user, nil
+}
+
+// ScoreBySource returns total points earned from every source given to
+// IncrementMemberScoreFrom.
+func (l *Leaderboard) ScoreBySource(ctx context.Context) (totals map[string]int64, err error) {
+	defer l.wrapError("ScoreBySource", "", &err)
+
+	values, err := l.client().HGetAll(ctx, l.sourcesKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	totals = make(map[string]int64, len(values))
+	for source, value := range values {
+		if totals[source], err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,49 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIncrementMemberScoreFrom(t *testing.T) {
+	l, _ := newTestBoard(t, WithEvents(0), WithEarningCap(12, PeriodDaily, nil))
+	ctx := context.Background()
+
+	increments := []struct {
+		userID, source string
+		points         int
+	}{{"a", "quests", 5}, {"b", "arena", 3}, {"a", "arena", 4}, {"a", "quests", 5}}
+	for _, inc := range increments {
+		if _, err := l.IncrementMemberScoreFrom(ctx, inc.userID, inc.points, inc.source); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Last quest of "a" is credited only 3 points left of the cap
+	totals, err := l.ScoreBySource(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int64{"quests": 8, "arena": 7}; !reflect.DeepEqual(totals, want) {
+		t.Fatalf("got totals %v, want %v", totals, want)
+	}
+
+	events, err := l.Events(ctx, "-", "+", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != len(increments) {
+		t.Fatalf("got %d events, want %d", len(events), len(increments))
+	}
+	for i, event := range events {
+		if event.Source != increments[i].source {
+			t.Fatalf("got source %q of event %d, want %q", event.Source, i, increments[i].source)
+		}
+	}
+
+	if _, err := l.IncrementMemberScoreFrom(ctx, "a", 1, ""); !errors.Is(err, ErrMissingSource) {
+		t.Fatalf("got %v, want ErrMissingSource", err)
+	}
+}
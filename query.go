@@ -0,0 +1,219 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+)
+
+// Order is order of members in a listing.
+type Order int
+
+const (
+	// Desc lists members from the best one, like GetLeaders
+	Desc Order = iota
+	// Asc lists members from the worst one
+	Asc
+)
+
+// Query is composable listing of the board built with Leaderboard.Query, e.g.
+//
+//	users, err := l.Query().Page(2).PageSize(50).WithInfo().Order(Asc).Exclude("bots").Run(ctx)
+//
+// Query is a value, every method returns a modified copy, so partially built
+// queries may be shared.
+type Query struct {
+	l        *Leaderboard
+	page     int
+	pageSize int
+	withInfo bool
+	withTags bool
+	order    Order
+	exclude  []string
+	opts     []ReadOption
+}
+
+// Query starts a listing of the board: first page of board's page size in
+// Desc order, without info.
+func (l *Leaderboard) Query() Query {
+	return Query{l: l, page: 1}
+}
+
+// Page sets 1-based page number.
+func (q Query) Page(page int) Query {
+	q.page = page
+	return q
+}
+
+// PageSize sets page size, which must be one of sizes accepted by
+// NewLeaderboard (10, 25, 50 and 100), otherwise Run returns ErrInvalidPageSize.
+func (q Query) PageSize(pageSize int) Query {
+	q.pageSize = pageSize
+	return q
+}
+
+// WithInfo fills in AdditionalInfo of listed members.
+func (q Query) WithInfo() Query {
+	q.withInfo = true
+	return q
+}
+
+// WithTags fills in Tags of listed members.
+func (q Query) WithTags() Query {
+	q.withTags = true
+	return q
+}
+
+// Order sets order of the listing.
+func (q Query) Order(order Order) Query {
+	q.order = order
+	return q
+}
+
+// Exclude lists the board as if members carrying any of tags weren't on it,
+// like GetLeadersExcluding.
+func (q Query) Exclude(tags ...string) Query {
+	q.exclude = append(append([]string(nil), q.exclude...), tags...)
+	return q
+}
+
+// Options applies read options (consistency, region, ...) to the listing.
+func (q Query) Options(opts ...ReadOption) Query {
+	q.opts = append(append([]ReadOption(nil), q.opts...), opts...)
+	return q
+}
+
+// Run executes the query. Ranks are board ranks (1 being the best member) in
+// both orders, except with Exclude, where they are positions within the
+// filtered listing counted in query's order.
+func (q Query) Run(ctx context.Context) (users []User, err error) {
+	l := q.l
+	ctx, done, err := l.startOp(ctx, "Query")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	o := newReadOptions(q.opts)
+	pageSize := l.pageSize(o)
+	if q.pageSize != 0 {
+		if !allowedPageSizes[q.pageSize] {
+			return nil, ErrInvalidPageSize
+		}
+		pageSize = q.pageSize
+	}
+
+	page := q.page
+	if page < 1 {
+		page = 1
+	}
+	_, startOffset, endOffset := l.pageOffsets(ctx, page, pageSize)
+
+	cli := l.readClient(o)
+	switch {
+	case len(q.exclude) > 0:
+		users, err = l.queryExcluding(ctx, cli, q.order, q.exclude, startOffset, pageSize)
+	case q.order == Asc:
+		users, err = l.queryAscending(ctx, cli, startOffset, endOffset)
+	default:
+		var values []redis.Z
+		values, err = cli.ZRevRangeWithScores(ctx, l.rangeKey(o, endOffset), int64(startOffset), int64(endOffset)).Result()
+		users = appendUsers(make([]User, 0, len(values)), values, startOffset, l.precision)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Positions within filtered listing don't count ghosts already
+	if len(q.exclude) == 0 {
+		if err := l.markGhosts(ctx, cli, users); err != nil {
+			return nil, err
+		}
+	}
+
+	if users, err = l.hideBanned(ctx, users); err != nil {
+		return nil, err
+	}
+
+	if q.withInfo {
+		if err := l.profiles.load(ctx, cli, users); err != nil {
+			return nil, err
+		}
+
+		if err := l.hydrateInfo(ctx, users); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.withTags {
+		if err := l.loadTags(ctx, cli, users); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.loadRecords(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// queryAscending reads members between 0-based offsets counted from the worst
+// member, ranking them with number of members read in the same transaction.
+func (l *Leaderboard) queryAscending(ctx context.Context, cli redis.UniversalClient, startOffset, endOffset int) ([]User, error) {
+	pipe := cli.TxPipeline()
+	rangeCmd := pipe.ZRangeWithScores(ctx, l.leaderboardName, int64(startOffset), int64(endOffset))
+	cardCmd := pipe.ZCard(ctx, l.leaderboardName)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	total := int(cardCmd.Val())
+	values := rangeCmd.Val()
+	users := make([]User, len(values))
+	for i, z := range values {
+		users[i] = l.scoredUser(z.Member.(string), z.Score, total-startOffset-i)
+	}
+
+	return users, nil
+}
+
+// queryExcluding reads count members starting at 0-based offset of the listing
+// filtered by tags.
+func (l *Leaderboard) queryExcluding(ctx context.Context, cli redis.UniversalClient, order Order, tags []string, startOffset, count int) ([]User, error) {
+	keys := []string{l.leaderboardName}
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return nil, ErrInvalidTag
+		}
+		keys = append(keys, l.tagKey(tag))
+	}
+
+	command := "ZREVRANGE"
+	if order == Asc {
+		command = "ZRANGE"
+	}
+
+	reply, err := rangeExcludingScript.Run(ctx, cli, keys, startOffset, count, command).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members, ok := reply.([]interface{})
+	if !ok {
+		return nil, errUnexpectedScriptReply
+	}
+
+	users := make([]User, 0, len(members)/2)
+	for i := 0; i+1 < len(members); i += 2 {
+		userID, _ := members[i].(string)
+		scoreText, _ := members[i+1].(string)
+		score, err := parseScore(scoreText)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, l.scoredUser(userID, score, startOffset+i/2+1))
+	}
+
+	return users, nil
+}
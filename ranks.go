@@ -0,0 +1,71 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// DefaultRankLookupChunkSize is number of ZREVRANK commands GetRanks sends per pipeline.
+const DefaultRankLookupChunkSize = 1000
+
+// GetRanks returns ranks of many users at once, e.g. for nightly jobs notifying
+// every participant about their standing. Lookups are pipelined in chunks of
+// DefaultRankLookupChunkSize. Users who aren't on the board get UnrankedMember.
+func (l *Leaderboard) GetRanks(ctx context.Context, userIDs []string, opts ...ReadOption) (ranks map[string]int, err error) {
+	ctx, done, err := l.startOp(ctx, "GetRanks")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	cli := l.readClient(newReadOptions(opts))
+	ranks = make(map[string]int, len(userIDs))
+
+	for start := 0; start < len(userIDs); start += DefaultRankLookupChunkSize {
+		end := start + DefaultRankLookupChunkSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		chunk := userIDs[start:end]
+
+		pipe := cli.Pipeline()
+		cmds := make([]*redis.IntCmd, len(chunk))
+		for i, userID := range chunk {
+			cmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+
+		for i, cmd := range cmds {
+			rank, err := cmd.Result()
+			switch {
+			case errors.Is(err, redis.Nil):
+				ranks[chunk[i]] = UnrankedMember
+			case err != nil:
+				return nil, err
+			default:
+				ranks[chunk[i]] = int(rank) + 1
+			}
+		}
+	}
+
+	if !l.ghosts {
+		return ranks, nil
+	}
+
+	users := make([]User, 0, len(ranks))
+	for userID, rank := range ranks {
+		users = append(users, User{UserID: userID, Rank: rank})
+	}
+	if err := l.markGhosts(ctx, cli, users); err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		ranks[user.UserID] = user.Rank
+	}
+
+	return ranks, nil
+}
@@ -0,0 +1,43 @@
+package go_redis_leaderboard
+
+// ReadOption customizes a single read call.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	consistency Consistency
+	// pageSize overrides board's PageSize when set
+	pageSize int
+	// region hints which regional replica should serve the read
+	region string
+	// withoutInfo skips reading members' info
+	withoutInfo bool
+}
+
+// WithPageSize makes GetLeaders use page size n instead of board's PageSize,
+// so different screens can page through the same board differently. Sizes not
+// accepted by NewLeaderboard (10, 25, 50 and 100) are ignored.
+func WithPageSize(n int) ReadOption {
+	return func(o *readOptions) {
+		if allowedPageSizes[n] {
+			o.pageSize = n
+		}
+	}
+}
+
+// WithoutInfo makes GetLeaders skip reading members' info, e.g. for clients
+// showing only ranks and scores. Boards with embedded info still return it, as
+// it's read together with the scores.
+func WithoutInfo() ReadOption {
+	return func(o *readOptions) {
+		o.withoutInfo = true
+	}
+}
+
+func newReadOptions(opts []ReadOption) readOptions {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
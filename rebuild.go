@@ -0,0 +1,130 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const (
+	rebuildBatchSize = 1000
+	// Temporary key expires if rebuild dies half-way, so it doesn't stay in Redis forever
+	rebuildKeyTTL = time.Hour
+)
+
+// Rebuild repopulates the board from external source of truth (e.g. DB rows).
+// Members are loaded into a temporary key which then atomically replaces the
+// board, so readers never see a half-populated board. Scores written to the
+// board while Rebuild runs are overwritten. User info isn't touched.
+//
+// source must call yield for every member and stop when yield returns false.
+func (l *Leaderboard) Rebuild(ctx context.Context, source func(yield func(userID string, score int) bool)) (err error) {
+	done, err := l.trackOp("Rebuild")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if l.migrationTarget() != nil {
+		return ErrMigrationInProgress
+	}
+
+	if l.eventSourced {
+		return ErrEventSourcingEnabled
+	}
+
+	if err := l.savepoint(ctx, "Rebuild"); err != nil {
+		return err
+	}
+
+	tmpKey, err := temporaryKey(l.leaderboardName + ":rebuild")
+	if err != nil {
+		return err
+	}
+
+	total, err := loadInto(ctx, l.client(), tmpKey, source)
+	if err != nil {
+		_ = l.client().Del(ctx, tmpKey).Err()
+		return err
+	}
+
+	if err := swapIn(ctx, l.client(), tmpKey, l.leaderboardName, total, l.queueBumpVersion(ctx)); err != nil {
+		return err
+	}
+	l.recordWrite(ctx)
+
+	return nil
+}
+
+// loadInto writes members yielded by source into key using pipelined ZADD batches.
+func loadInto(ctx context.Context, redisCli redis.UniversalClient, key string, source func(yield func(userID string, score int) bool)) (total int, err error) {
+	batch := make([]*redis.Z, 0, rebuildBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		pipe := redisCli.Pipeline()
+		pipe.ZAdd(ctx, key, batch...)
+		pipe.Expire(ctx, key, rebuildKeyTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+
+		total += len(batch)
+		batch = batch[:0]
+
+		return nil
+	}
+
+	source(func(userID string, score int) bool {
+		batch = append(batch, &redis.Z{Score: float64(score), Member: userID})
+		if len(batch) < rebuildBatchSize {
+			return true
+		}
+
+		err = flush()
+
+		return err == nil && ctx.Err() == nil
+	})
+
+	if err != nil {
+		return total, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return total, err
+	}
+
+	return total, flush()
+}
+
+// swapIn atomically replaces key with tmpKey. Empty source produces empty board.
+// Commands queued by also, when not nil, run in the same MULTI as the swap.
+func swapIn(ctx context.Context, redisCli redis.UniversalClient, tmpKey, key string, total int, also func(pipe redis.Pipeliner)) error {
+	pipe := redisCli.TxPipeline()
+	if total == 0 {
+		pipe.Del(ctx, key)
+	} else {
+		pipe.Rename(ctx, tmpKey, key)
+		pipe.Persist(ctx, key)
+	}
+	if also != nil {
+		also(pipe)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+func temporaryKey(prefix string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return prefix + ":" + hex.EncodeToString(suffix), nil
+}
@@ -0,0 +1,210 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// Outcomes of a match recorded by RecordResult.
+const (
+	OutcomeWin  = "w"
+	OutcomeLoss = "l"
+	OutcomeDraw = "d"
+)
+
+var (
+	ErrUnknownOutcome  = errors.New("leaderboard: outcome must be win, loss or draw")
+	ErrRecordsDisabled = errors.New("leaderboard: win/loss records aren't enabled for the board, see WithRecords")
+)
+
+// Record is member's win/loss/draw record.
+type Record struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+// Adds ARGV[3] points (at most what's left of earning cap) to member ARGV[1]
+// and increments its ARGV[2] outcome counter in KEYS[5] (and against opponent
+// ARGV[4] in KEYS[6] when given) unless score is locked or submissions are
+// closed, then returns {newScore, wins, losses, draws, headToHeadCount}.
+var recordResultScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+if redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], ARGV[1]) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+local credited = credit(ARGV[1], tonumber(ARGV[3]))
+local newScore = redis.call("ZINCRBY", KEYS[1], credited, ARGV[1])
+if credited > 0 then
+	logOp(KEYS[3], "incr", ARGV[1], credited)
+end
+redis.call("HINCRBY", KEYS[5], ARGV[1] .. ":" .. ARGV[2], 1)
+bumpVersion(KEYS[4])
+
+local headToHead = 0
+if ARGV[4] ~= "" then
+	headToHead = redis.call("HINCRBY", KEYS[6], ARGV[1] .. "|" .. ARGV[4] .. ":" .. ARGV[2], 1)
+end
+
+local record = redis.call("HMGET", KEYS[5], ARGV[1] .. ":w", ARGV[1] .. ":l", ARGV[1] .. ":d")
+
+return {newScore, tonumber(record[1] or "0"), tonumber(record[2] or "0"), tonumber(record[3] or "0"), headToHead}
+`)
+
+// WithRecords keeps win/loss/draw record of every member next to its score in
+// "<leaderboardName>:records" hash. Records are updated by RecordResult
+// atomically with the score and returned on User by member lookups and pages.
+func WithRecords() Option {
+	return func(l *Leaderboard) {
+		l.records = true
+	}
+}
+
+func (l *Leaderboard) recordsKey() string {
+	return l.leaderboardName + ":records"
+}
+
+// headToHeadKey is hash of members' records against particular opponents.
+func (l *Leaderboard) headToHeadKey() string {
+	return l.leaderboardName + ":h2h"
+}
+
+func recordField(userID, outcome string) string {
+	return userID + ":" + outcome
+}
+
+func headToHeadField(userID, opponentID, outcome string) string {
+	return userID + "|" + opponentID + ":" + outcome
+}
+
+// RecordResult adds points earned in a match to member's score and counts the
+// match's outcome (OutcomeWin, OutcomeLoss or OutcomeDraw) in member's record,
+// both in one script, so score and record never drift apart.
+func (l *Leaderboard) RecordResult(ctx context.Context, userID, outcome string, points int) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "RecordResult", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	return l.recordResult(ctx, userID, "", outcome, points)
+}
+
+// RecordResultAgainst is RecordResult which also counts the outcome in member's
+// head-to-head record against opponentID, see CompareMembers. Opponent's own
+// result is recorded by a separate call.
+func (l *Leaderboard) RecordResultAgainst(ctx context.Context, userID, opponentID, outcome string, points int) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "RecordResultAgainst", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	return l.recordResult(ctx, userID, opponentID, outcome, points)
+}
+
+func (l *Leaderboard) recordResult(ctx context.Context, userID, opponentID, outcome string, points int) (User, error) {
+	if !l.records {
+		return User{}, ErrRecordsDisabled
+	}
+
+	if outcome != OutcomeWin && outcome != OutcomeLoss && outcome != OutcomeDraw {
+		return User{}, ErrUnknownOutcome
+	}
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if points < 0 {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	before := l.memberBefore(ctx, userID)
+
+	keys, args, earnedKey := l.appendEarningCap(
+		[]string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey(), l.recordsKey(), l.headToHeadKey()},
+		[]interface{}{userID, outcome, points, opponentID},
+	)
+	reply, err := recordResultScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Result()
+	if err != nil {
+		return User{}, scriptError(err)
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 5 {
+		return User{}, errUnexpectedScriptReply
+	}
+
+	scoreText, _ := res[0].(string)
+	floatScore, err := strconv.ParseFloat(scoreText, 64)
+	if err != nil {
+		return User{}, err
+	}
+
+	wins, _ := res[1].(int64)
+	losses, _ := res[2].(int64)
+	draws, _ := res[3].(int64)
+	headToHead, _ := res[4].(int64)
+	record := &Record{Wins: int(wins), Losses: int(losses), Draws: int(draws)}
+
+	newScore := l.precision.whole(floatScore)
+	l.scoreWritten(ctx, userID, floatScore)
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.HSet(ctx, l.recordsKey(), recordField(userID, OutcomeWin), record.Wins, recordField(userID, OutcomeLoss), record.Losses, recordField(userID, OutcomeDraw), record.Draws).Err()
+	})
+	if opponentID != "" {
+		l.mirrorHashField(ctx, l.headToHeadKey(), headToHeadField(userID, opponentID, outcome), strconv.FormatInt(headToHead, 10))
+	}
+	l.mirrorEarned(ctx, earnedKey, userID)
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	user := User{UserID: userID, Score: newScore, Rank: rank, Record: record}
+	l.emitScoreChange(ctx, before, user)
+
+	return user, nil
+}
+
+// loadRecords fills Record of users in one HMGET when records are enabled.
+func (l *Leaderboard) loadRecords(ctx context.Context, cli redis.UniversalClient, users []User) error {
+	if !l.records || len(users) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(users)*3)
+	for _, u := range users {
+		fields = append(fields, recordField(u.UserID, OutcomeWin), recordField(u.UserID, OutcomeLoss), recordField(u.UserID, OutcomeDraw))
+	}
+
+	values, err := cli.HMGet(ctx, l.recordsKey(), fields...).Result()
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		users[i].Record = recordOf(values[i*3 : i*3+3])
+	}
+
+	return nil
+}
+
+// removeRecord deletes member's record within pipe of the removal.
+func (l *Leaderboard) removeRecord(ctx context.Context, pipe redis.Pipeliner, userID string) {
+	if l.records {
+		pipe.HDel(ctx, l.recordsKey(), recordField(userID, OutcomeWin), recordField(userID, OutcomeLoss), recordField(userID, OutcomeDraw))
+	}
+}
@@ -0,0 +1,56 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecordResult(t *testing.T) {
+	l, _ := newTestBoard(t, WithRecords())
+	ctx := context.Background()
+
+	var user User
+	for _, result := range []struct {
+		outcome string
+		points  int
+	}{{OutcomeWin, 3}, {OutcomeWin, 3}, {OutcomeLoss, 0}, {OutcomeDraw, 1}} {
+		var err error
+		if user, err = l.RecordResult(ctx, "a", result.outcome, result.points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := Record{Wins: 2, Losses: 1, Draws: 1}
+	if user.Score != 7 || user.Record == nil || *user.Record != want {
+		t.Fatalf("got %+v, want score 7 and record %+v", user, want)
+	}
+
+	member, err := l.GetMember(ctx, "a", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.Record == nil || *member.Record != want {
+		t.Fatalf("got record %+v from GetMember, want %+v", member.Record, want)
+	}
+
+	if _, err := l.RecordResult(ctx, "a", "win", 3); !errors.Is(err, ErrUnknownOutcome) {
+		t.Fatalf("got %v, want ErrUnknownOutcome", err)
+	}
+
+	// Record is removed together with member's score
+	if _, err := l.RemoveMember(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if n := l.client().HLen(ctx, l.recordsKey()).Val(); n != 0 {
+		t.Fatalf("got %d record fields left, want 0", n)
+	}
+}
+
+func TestRecordResultWithoutRecords(t *testing.T) {
+	l, _ := newTestBoard(t)
+
+	if _, err := l.RecordResult(context.Background(), "a", OutcomeWin, 3); !errors.Is(err, ErrRecordsDisabled) {
+		t.Fatalf("got %v, want ErrRecordsDisabled", err)
+	}
+}
@@ -9,12 +9,71 @@ type RedisSettings struct {
 	Host     string
 	Password string
 	DB       int
+	// RingAddrs maps shard names to addresses of standalone servers boards are
+	// spread over using client-side sharding (redis.Ring), Host is ignored then.
+	// Keys are placed by hash of their name, so a board's sorted set and its info
+	// hash may end up on different shards; wrap common part of board and hash
+	// names in braces (e.g. "{weekly}:scores" and "{weekly}:info") to keep them
+	// together, as multi-key operations require it.
+	RingAddrs map[string]string
+	// ClusterAddrs are seed addresses of Redis Cluster nodes (redis.ClusterClient),
+	// Host and DB are ignored then. As with RingAddrs, board and info hash names
+	// need a common hash tag (e.g. "{weekly}:scores" and "{weekly}:info"), since
+	// scripts and transactions touch several keys of the board.
+	ClusterAddrs []string
+	// SentinelAddrs are addresses of Sentinels monitoring master MasterName,
+	// client follows failovers of the master (redis.FailoverClient) and Host is
+	// ignored then. SentinelPassword authenticates to Sentinels.
+	SentinelAddrs    []string
+	MasterName       string
+	SentinelPassword string
 }
 
-func connectToRedis(host string, password string, DB int) *redis.Client {
+// singleNode reports whether settings connect to a single server at Host.
+func (s RedisSettings) singleNode() bool {
+	return len(s.RingAddrs) == 0 && len(s.ClusterAddrs) == 0 && len(s.SentinelAddrs) == 0
+}
+
+// clientHolder wraps client so clients of different types (*redis.Client,
+// *redis.Ring, *redis.ClusterClient) can be kept in the same atomic.Value.
+type clientHolder struct {
+	cli redis.UniversalClient
+}
+
+// connectToRedis creates client for settings, poolSize 0 leaves go-redis default.
+func connectToRedis(settings RedisSettings, poolSize int) redis.UniversalClient {
+	if len(settings.ClusterAddrs) > 0 {
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    settings.ClusterAddrs,
+			Password: settings.Password,
+			PoolSize: poolSize,
+		})
+	}
+
+	if len(settings.SentinelAddrs) > 0 {
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       settings.MasterName,
+			SentinelAddrs:    settings.SentinelAddrs,
+			SentinelPassword: settings.SentinelPassword,
+			Password:         settings.Password,
+			DB:               settings.DB,
+			PoolSize:         poolSize,
+		})
+	}
+
+	if len(settings.RingAddrs) > 0 {
+		return redis.NewRing(&redis.RingOptions{
+			Addrs:    settings.RingAddrs,
+			Password: settings.Password,
+			DB:       settings.DB,
+			PoolSize: poolSize,
+		})
+	}
+
 	return redis.NewClient(&redis.Options{
-		Addr:     host,
-		Password: password,
-		DB:       DB,
+		Addr:     settings.Host,
+		Password: settings.Password,
+		DB:       settings.DB,
+		PoolSize: poolSize,
 	})
 }
@@ -0,0 +1,212 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// Returns {rank, score, leaderScore, nextMember, nextScore} of ARGV[1] on board
+// KEYS[1], next member being the one ranked right above, or nil when member
+// isn't ranked. Ghosts (set KEYS[2]) are skipped and don't count in rank.
+var memberRelativeScript = redis.NewScript(ghostsLua + `
+local position = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+if not position then
+	return false
+end
+
+local ghosts = ghostPositions(KEYS[1], KEYS[2])
+local rank = rankAt(ghosts, position)
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+
+local leaderPosition = positionOf(ghosts, 0)
+local leader = redis.call("ZREVRANGE", KEYS[1], leaderPosition, leaderPosition, "WITHSCORES")
+local next = {ARGV[1], score}
+if rank > 0 then
+	local nextPosition = positionOf(ghosts, rank - 1)
+	next = redis.call("ZREVRANGE", KEYS[1], nextPosition, nextPosition, "WITHSCORES")
+end
+
+return {rank, score, leader[2], next[1], next[2]}
+`)
+
+// RelativeStanding is member's position relative to the leader and to the member ranked right above.
+type RelativeStanding struct {
+	User
+	LeaderScore int `json:"leader_score"`
+	// FractionOfLeader is member's score divided by leader's score, 1 for the
+	// leader and 0 when leader's score isn't positive
+	FractionOfLeader float64 `json:"fraction_of_leader"`
+	// NextUserID is member ranked right above, empty for the leader
+	NextUserID string `json:"next_user_id,omitempty"`
+	// GapToNext is number of points member is behind NextUserID
+	GapToNext int `json:"gap_to_next"`
+}
+
+// GetMemberRelative returns member's standing relative to the leader and to
+// the next rank, for "you're 82% of the way to #1" elements. Everything is
+// read in one round trip. ErrMemberNotFound is returned when member isn't ranked.
+func (l *Leaderboard) GetMemberRelative(ctx context.Context, userID string) (standing RelativeStanding, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GetMemberRelative", userID)
+	if err != nil {
+		return RelativeStanding{}, err
+	}
+	defer done(&err)
+
+	reply, err := memberRelativeScript.Run(ctx, l.client(), []string{l.leaderboardName, l.ghostsKey()}, userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return RelativeStanding{}, ErrMemberNotFound
+	}
+	if err != nil {
+		return RelativeStanding{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 5 {
+		return RelativeStanding{}, errUnexpectedScriptReply
+	}
+
+	rank, ok := values[0].(int64)
+	if !ok {
+		return RelativeStanding{}, errUnexpectedScriptReply
+	}
+
+	scores := make([]float64, 0, 3)
+	for _, i := range []int{1, 2, 4} {
+		text, ok := values[i].(string)
+		if !ok {
+			return RelativeStanding{}, errUnexpectedScriptReply
+		}
+
+		score, err := parseScore(text)
+		if err != nil {
+			return RelativeStanding{}, err
+		}
+		scores = append(scores, score)
+	}
+
+	standing = RelativeStanding{
+		User:        l.scoredUser(userID, scores[0], int(rank)+1),
+		LeaderScore: l.precision.whole(scores[1]),
+		GapToNext:   l.precision.whole(scores[2] - scores[0]),
+	}
+
+	if rank > 0 {
+		standing.NextUserID, _ = values[3].(string)
+	}
+
+	if scores[1] > 0 {
+		standing.FractionOfLeader = scores[0] / scores[1]
+	}
+
+	return standing, nil
+}
+
+// Returns {rank, score, nextMember, nextScore, targetMember, targetScore} of
+// ARGV[1] on board KEYS[1], next member being the one ranked right above and
+// target member the one at 0-based rank ARGV[2] (ARGV[2] < 0 for none), or nil
+// when member isn't ranked. Members missing above the member are returned as
+// the member itself. Ghosts (set KEYS[2]) are skipped and don't count in ranks.
+var gapToRankScript = redis.NewScript(ghostsLua + `
+local position = redis.call("ZREVRANK", KEYS[1], ARGV[1])
+if not position then
+	return false
+end
+
+local ghosts = ghostPositions(KEYS[1], KEYS[2])
+local rank = rankAt(ghosts, position)
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+local function above(r)
+	if r < 0 or r >= rank then
+		return {ARGV[1], score}
+	end
+
+	local p = positionOf(ghosts, r)
+	return redis.call("ZREVRANGE", KEYS[1], p, p, "WITHSCORES")
+end
+
+local next = above(rank - 1)
+local target = above(tonumber(ARGV[2]))
+
+return {rank, score, next[1], next[2], target[1], target[2]}
+`)
+
+// RankGap tells how many points member is behind the member ranked right above
+// and behind the member at a target rank, see GapToNextRank.
+type RankGap struct {
+	User
+	// NextUserID is member ranked right above, empty for the leader
+	NextUserID string `json:"next_user_id,omitempty"`
+	// GapToNext is number of points member is behind NextUserID
+	GapToNext int `json:"gap_to_next"`
+	// TargetRank is rank gap was computed for, 0 when no target was given
+	TargetRank int `json:"target_rank,omitempty"`
+	// TargetUserID is member at TargetRank, empty when member already is at or above it
+	TargetUserID string `json:"target_user_id,omitempty"`
+	// GapToTarget is number of points member is behind TargetUserID
+	GapToTarget int `json:"gap_to_target"`
+}
+
+// GapToNextRank returns how many points member is behind the member ranked
+// right above and, when targetRank > 0, behind the member at targetRank, for
+// "you need 120 more points to reach #10" hints. Gaps are differences of
+// scores, matching the score only ties it. Everything is read in one round
+// trip. ErrMemberNotFound is returned when member isn't ranked.
+func (l *Leaderboard) GapToNextRank(ctx context.Context, userID string, targetRank int) (gap RankGap, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "GapToNextRank", userID)
+	if err != nil {
+		return RankGap{}, err
+	}
+	defer done(&err)
+
+	reply, err := gapToRankScript.Run(ctx, l.client(), []string{l.leaderboardName, l.ghostsKey()}, userID, targetRank-1).Result()
+	if errors.Is(err, redis.Nil) {
+		return RankGap{}, ErrMemberNotFound
+	}
+	if err != nil {
+		return RankGap{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 6 {
+		return RankGap{}, errUnexpectedScriptReply
+	}
+
+	rank, ok := values[0].(int64)
+	if !ok {
+		return RankGap{}, errUnexpectedScriptReply
+	}
+
+	scores := make([]float64, 0, 3)
+	for _, i := range []int{1, 3, 5} {
+		text, ok := values[i].(string)
+		if !ok {
+			return RankGap{}, errUnexpectedScriptReply
+		}
+
+		score, err := parseScore(text)
+		if err != nil {
+			return RankGap{}, err
+		}
+		scores = append(scores, score)
+	}
+
+	gap = RankGap{
+		User:        l.scoredUser(userID, scores[0], int(rank)+1),
+		GapToNext:   l.precision.whole(scores[1] - scores[0]),
+		GapToTarget: l.precision.whole(scores[2] - scores[0]),
+	}
+
+	if rank > 0 {
+		gap.NextUserID, _ = values[2].(string)
+	}
+
+	if targetRank > 0 {
+		gap.TargetRank = targetRank
+		if int64(targetRank) <= rank {
+			gap.TargetUserID, _ = values[4].(string)
+		}
+	}
+
+	return gap, nil
+}
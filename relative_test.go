@@ -0,0 +1,88 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetMemberRelative(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 40, "b", 60, "c", 100)
+
+	tests := []struct {
+		userID   string
+		rank     int
+		fraction float64
+		next     string
+		gap      int
+	}{
+		{"c", 1, 1, "", 0},
+		{"b", 2, 0.6, "c", 40},
+		{"a", 3, 0.4, "b", 20},
+	}
+
+	for _, tt := range tests {
+		standing, err := l.GetMemberRelative(ctx, tt.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if standing.Rank != tt.rank || standing.LeaderScore != 100 || standing.FractionOfLeader != tt.fraction || standing.NextUserID != tt.next || standing.GapToNext != tt.gap {
+			t.Fatalf("got %+v, want rank %d, fraction %v and gap %d to %q", standing, tt.rank, tt.fraction, tt.gap, tt.next)
+		}
+	}
+
+	if _, err := l.GetMemberRelative(ctx, "x"); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("got %v, want ErrMemberNotFound", err)
+	}
+}
+
+func TestGetMemberRelativeOfZeroLeader(t *testing.T) {
+	l, _ := newTestBoard(t)
+	seed(t, l, "a", 0)
+
+	standing, err := l.GetMemberRelative(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standing.FractionOfLeader != 0 {
+		t.Fatalf("got fraction %v, want 0", standing.FractionOfLeader)
+	}
+}
+
+func TestGapToNextRank(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "d", 10, "a", 40, "b", 60, "c", 100)
+
+	tests := []struct {
+		userID      string
+		targetRank  int
+		rank        int
+		next        string
+		gapToNext   int
+		target      string
+		gapToTarget int
+	}{
+		{"d", 0, 4, "a", 30, "", 0},
+		{"d", 2, 4, "a", 30, "b", 50},
+		{"a", 1, 3, "b", 20, "c", 60},
+		{"b", 3, 2, "c", 40, "", 0},
+		{"c", 1, 1, "", 0, "", 0},
+	}
+
+	for _, tt := range tests {
+		gap, err := l.GapToNextRank(ctx, tt.userID, tt.targetRank)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gap.Rank != tt.rank || gap.NextUserID != tt.next || gap.GapToNext != tt.gapToNext || gap.TargetRank != tt.targetRank || gap.TargetUserID != tt.target || gap.GapToTarget != tt.gapToTarget {
+			t.Fatalf("got %+v, want rank %d, gap %d to %q and gap %d to %q", gap, tt.rank, tt.gapToNext, tt.next, tt.gapToTarget, tt.target)
+		}
+	}
+
+	if _, err := l.GapToNextRank(ctx, "x", 1); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("got %v, want ErrMemberNotFound", err)
+	}
+}
@@ -0,0 +1,88 @@
+package go_redis_leaderboard
+
+import (
+	"github.com/go-redis/redis/v8"
+)
+
+// Consistency tells where a read is served from when replica reads are enabled.
+type Consistency int
+
+const (
+	// Eventual reads may be served by a replica and miss the most recent writes
+	Eventual Consistency = iota
+	// Strong reads always go to the primary, e.g. to show member's rank right after they scored
+	Strong
+)
+
+// WithReadReplica serves reads (pages, member lookups, counts) from replica
+// instead of primary. Writes always go to primary. Use WithConsistency(Strong)
+// for reads which must observe caller's own writes.
+func WithReadReplica(replica RedisSettings) Option {
+	return func(l *Leaderboard) {
+		l.replicaCli.Store(clientHolder{connectToRedis(replica, l.modeProfile().PoolSize)})
+	}
+}
+
+// WithConsistency routes single read to primary (Strong) or allows it to be
+// served by a replica (Eventual, default).
+func WithConsistency(c Consistency) ReadOption {
+	return func(o *readOptions) {
+		o.consistency = c
+	}
+}
+
+// WithRegionalReplicas adds replicas keyed by region (e.g. "eu", "us-east"),
+// so reads carrying WithRegion hint are served by replica of caller's region
+// while writes still go to the primary. Reads without hint, or hinting region
+// without replica, fall back to WithReadReplica replica or primary.
+func WithRegionalReplicas(replicas map[string]RedisSettings) Option {
+	return func(l *Leaderboard) {
+		clients := make(map[string]redis.UniversalClient, len(replicas))
+		for region, settings := range replicas {
+			clients[region] = connectToRedis(settings, l.modeProfile().PoolSize)
+		}
+
+		l.regionalReplicas.Store(clients)
+	}
+}
+
+// WithRegion hints read should be served by replica of region, see WithRegionalReplicas.
+func WithRegion(region string) ReadOption {
+	return func(o *readOptions) {
+		o.region = region
+	}
+}
+
+// readClient returns client read should be sent to.
+func (l *Leaderboard) readClient(o readOptions) redis.UniversalClient {
+	if o.consistency == Strong {
+		return l.client()
+	}
+
+	if o.region != "" {
+		if regional, _ := l.regionalReplicas.Load().(map[string]redis.UniversalClient); regional[o.region] != nil {
+			return regional[o.region]
+		}
+	}
+
+	if replica := l.replicaCli.Load().(clientHolder).cli; replica != nil {
+		return replica
+	}
+
+	return l.client()
+}
+
+// dropReplica stops reading from replicas, e.g. once board moved to another instance.
+func (l *Leaderboard) dropReplica() {
+	if replica := l.replicaCli.Load().(clientHolder).cli; replica != nil {
+		l.replicaCli.Store(clientHolder{})
+		_ = replica.Close()
+	}
+
+	if regional, _ := l.regionalReplicas.Load().(map[string]redis.UniversalClient); len(regional) > 0 {
+		l.regionalReplicas.Store(map[string]redis.UniversalClient{})
+		for _, replica := range regional {
+			_ = replica.Close()
+		}
+	}
+}
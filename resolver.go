@@ -0,0 +1,74 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+)
+
+// InfoResolver provides info of members missing it in Redis from an external
+// system (SQL, another cache, ...), see WithInfoResolver.
+type InfoResolver interface {
+	// ResolveInfo returns info of given users, users it doesn't know are left out
+	ResolveInfo(ctx context.Context, userIDs []string) (map[string]AdditionalUserInfo, error)
+}
+
+// WithInfoResolver makes GetLeaders, GetLeadersInto, GetMember and queries
+// with info hydrate info missing in Redis from resolver, with one ResolveInfo
+// call per read. Resolved info is written back to board's profile store, so
+// profiles migrate gradually as they're read. Resolver errors fail the read
+// unless onError is set, then they're passed to it and members are returned
+// without info.
+func WithInfoResolver(resolver InfoResolver, onError func(error)) Option {
+	return func(l *Leaderboard) {
+		l.infoResolver = resolver
+		l.onResolveError = onError
+	}
+}
+
+// hydrateInfo resolves info of users whose info wasn't found in Redis.
+func (l *Leaderboard) hydrateInfo(ctx context.Context, users []User) error {
+	if l.infoResolver == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, u := range users {
+		if u.AdditionalInfo == nil && u.Rank != UnrankedMember {
+			missing = append(missing, u.UserID)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	resolved, err := l.infoResolver.ResolveInfo(ctx, missing)
+	if err != nil {
+		if l.onResolveError != nil {
+			l.onResolveError(err)
+			return nil
+		}
+
+		return err
+	}
+
+	for i, u := range users {
+		info, ok := resolved[u.UserID]
+		if !ok || u.AdditionalInfo != nil {
+			continue
+		}
+
+		value, err := l.profiles.upsert(ctx, u.UserID, info, l.queueBumpVersion(ctx))
+		if err != nil {
+			return err
+		}
+		l.mirrorInfo(ctx, u.UserID, value)
+		l.mirrorVersion(ctx)
+		l.embeddedInfoUpserted(ctx, u.UserID, info)
+
+		if users[i].AdditionalInfo, err = decodeMemberInfo(value, l.profiles.keyring); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
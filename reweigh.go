@@ -0,0 +1,64 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+)
+
+// Reweigh rewrites score of every member using f, e.g. when scoring formula
+// changes mid-season and historical scores must be converted. Members are read
+// in batches (ZSCAN) and written with pipelined ZADD into a shadow key which
+// then atomically replaces the board, so readers never see a half-converted
+// board. Like with Rebuild, scores written while Reweigh runs are overwritten.
+func (l *Leaderboard) Reweigh(ctx context.Context, f func(userID string, oldScore float64) float64) (err error) {
+	done, err := l.trackOp("Reweigh")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if l.migrationTarget() != nil {
+		return ErrMigrationInProgress
+	}
+
+	if l.eventSourced {
+		return ErrEventSourcingEnabled
+	}
+
+	if err := l.savepoint(ctx, "Reweigh"); err != nil {
+		return err
+	}
+
+	tmpKey, err := temporaryKey(l.leaderboardName + ":reweigh")
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	err = scanSortedSet(ctx, l.client(), l.leaderboardName, rebuildBatchSize, func(members []redis.Z) error {
+		for i := range members {
+			members[i].Score = l.precision.round(f(members[i].Member.(string), members[i].Score))
+		}
+
+		pipe := l.client().Pipeline()
+		pipe.ZAdd(ctx, tmpKey, zPointers(members)...)
+		pipe.Expire(ctx, tmpKey, rebuildKeyTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		total += len(members)
+
+		return nil
+	})
+	if err != nil {
+		_ = l.client().Del(ctx, tmpKey).Err()
+		return err
+	}
+
+	if err := swapIn(ctx, l.client(), tmpKey, l.leaderboardName, total, l.queueBumpVersion(ctx)); err != nil {
+		return err
+	}
+	l.recordWrite(ctx)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// DefaultSavepointTTL is how long savepoints are kept when WithSavepoints is given no TTL.
+const DefaultSavepointTTL = 24 * time.Hour
+
+type savepoints struct {
+	ttl    time.Duration
+	onSave func(op string, id SnapshotID)
+}
+
+// WithSavepoints makes bulk operations (BulkLoad and Import, Rebuild, Reweigh,
+// TrimBelowScore and TrimBelowRank) snapshot the board before they change it,
+// so a botched bulk job can be undone with RollbackToSavepoint. Savepoints are
+// snapshots kept for ttl (DefaultSavepointTTL when 0) and are reported to
+// onSave, if given, together with name of the operation. Bulk operation fails
+// if its savepoint can't be taken.
+func WithSavepoints(ttl time.Duration, onSave func(op string, id SnapshotID)) Option {
+	return func(l *Leaderboard) {
+		if ttl <= 0 {
+			ttl = DefaultSavepointTTL
+		}
+		l.savepoints = &savepoints{ttl: ttl, onSave: onSave}
+	}
+}
+
+// savepoint snapshots the board before bulk operation op when savepoints are
+// enabled and keeps rank baseline for NotifyAffected.
+func (l *Leaderboard) savepoint(ctx context.Context, op string) error {
+	if err := l.keepBaseline(ctx); err != nil {
+		return err
+	}
+
+	s := l.savepoints
+	if s == nil {
+		return nil
+	}
+
+	id, err := l.takeSnapshot(ctx, s.ttl)
+	if err != nil {
+		return err
+	}
+
+	if s.onSave != nil {
+		s.onSave(op, id)
+	}
+
+	return nil
+}
+
+// RollbackToSavepoint atomically replaces scores on the board with ones saved
+// in savepoint (or any other snapshot) id. Scores written after the savepoint
+// are lost, user info isn't touched. ErrSnapshotNotFound is returned when
+// savepoint expired, was deleted or was taken of an empty board.
+func (l *Leaderboard) RollbackToSavepoint(ctx context.Context, id SnapshotID) (err error) {
+	done, err := l.trackOp("RollbackToSavepoint")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if l.migrationTarget() != nil {
+		return ErrMigrationInProgress
+	}
+
+	if l.eventSourced {
+		return ErrEventSourcingEnabled
+	}
+
+	exists, err := l.client().Exists(ctx, l.snapshotKey(id)).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrSnapshotNotFound
+	}
+
+	pipe := l.client().TxPipeline()
+	pipe.ZUnionStore(ctx, l.leaderboardName, &redis.ZStore{Keys: []string{l.snapshotKey(id)}})
+	l.bumpVersion(ctx, pipe)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	l.recordWrite(ctx)
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	metaSchemaVersion = "schema_version"
+	schemaLockTTL     = 10 * time.Minute
+	// Lock is refreshed several times per TTL so a slow refresh doesn't let it expire
+	schemaLockRefresh = schemaLockTTL / 3
+)
+
+var (
+	ErrSchemaMigrationLocked   = errors.New("leaderboard: schema migration is already running")
+	ErrSchemaMigrationLockLost = errors.New("leaderboard: schema migration lock was lost")
+	ErrInvalidSchemaVersion    = errors.New("leaderboard: schema migration version must be positive and unique")
+)
+
+// Deletes lock KEYS[1] if it's still held with token ARGV[1].
+var releaseSchemaLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+
+return 0
+`)
+
+// Extends lock KEYS[1] to ARGV[2] milliseconds if it's still held with token
+// ARGV[1]. Returns 1 when extended.
+var refreshSchemaLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+
+return 0
+`)
+
+// SchemaMigration is one step changing board's data format, e.g. re-encoding
+// stored user info or renaming keys. Up must be safe to re-run if it fails half-way.
+type SchemaMigration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, l *Leaderboard) error
+}
+
+var (
+	schemaMigrationsMu sync.RWMutex
+	schemaMigrations   = map[int]SchemaMigration{}
+)
+
+// RegisterSchemaMigration registers migration step run by MigrateSchema.
+// It's meant to be called from init functions.
+func RegisterSchemaMigration(m SchemaMigration) error {
+	schemaMigrationsMu.Lock()
+	defer schemaMigrationsMu.Unlock()
+
+	if _, exists := schemaMigrations[m.Version]; exists || m.Version < 1 || m.Up == nil {
+		return ErrInvalidSchemaVersion
+	}
+
+	schemaMigrations[m.Version] = m
+
+	return nil
+}
+
+// metaKey is hash holding board's metadata (schema version, configuration shared by all instances...)
+func (l *Leaderboard) metaKey() string {
+	return l.leaderboardName + ":meta"
+}
+
+// SchemaVersion returns data format version board is at, 0 for boards never migrated.
+func (l *Leaderboard) SchemaVersion(ctx context.Context) (_ int, err error) {
+	defer l.wrapError("SchemaVersion", "", &err)
+
+	version, err := l.client().HGet(ctx, l.metaKey(), metaSchemaVersion).Int()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (l *Leaderboard) schemaLockKey() string {
+	return l.metaKey() + ":schema_lock"
+}
+
+// MigrateSchema runs all registered migrations newer than board's schema version
+// in ascending order and records version after every successful step. Only one
+// instance can migrate a board at a time, others get ErrSchemaMigrationLocked.
+// Lock is refreshed while migrations run; if it's lost anyway (e.g. Redis was
+// unreachable for longer than its TTL), context passed to running step is
+// cancelled and ErrSchemaMigrationLockLost is returned.
+func (l *Leaderboard) MigrateSchema(ctx context.Context) (applied []int, err error) {
+	done, err := l.trackOp("MigrateSchema")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	lockKey, lockToken := l.schemaLockKey(), hex.EncodeToString(token)
+
+	locked, err := l.client().SetNX(ctx, lockKey, lockToken, schemaLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, ErrSchemaMigrationLocked
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	var lost int32
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		l.holdSchemaLock(lockCtx, lockToken, func() {
+			atomic.StoreInt32(&lost, 1)
+			cancel()
+		})
+	}()
+	defer func() {
+		cancel()
+		<-refreshed
+		_ = releaseSchemaLockScript.Run(ctx, l.client(), []string{lockKey}, lockToken).Err()
+	}()
+
+	current, err := l.SchemaVersion(lockCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range pendingSchemaMigrations(current) {
+		err := m.Up(lockCtx, l)
+		if atomic.LoadInt32(&lost) == 1 {
+			return applied, ErrSchemaMigrationLockLost
+		}
+		if err != nil {
+			return applied, fmt.Errorf("leaderboard: schema migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		if err := l.client().HSet(ctx, l.metaKey(), metaSchemaVersion, m.Version).Err(); err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, m.Version)
+	}
+
+	return applied, nil
+}
+
+// holdSchemaLock refreshes schema lock held with token every schemaLockRefresh
+// until ctx is done and calls onLost when lock turns out to be taken over or
+// expired. Failed refreshes are retried on the next tick, lock's TTL covers them.
+func (l *Leaderboard) holdSchemaLock(ctx context.Context, token string, onLost func()) {
+	ticker := time.NewTicker(schemaLockRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := l.refreshSchemaLock(ctx, token)
+			if err == nil && !held {
+				onLost()
+				return
+			}
+		}
+	}
+}
+
+// refreshSchemaLock extends schema lock to schemaLockTTL if it's still held with token.
+func (l *Leaderboard) refreshSchemaLock(ctx context.Context, token string) (bool, error) {
+	keys := []string{l.schemaLockKey()}
+	held, err := refreshSchemaLockScript.Run(ctx, l.client(), keys, token, schemaLockTTL.Milliseconds()).Int()
+
+	return held == 1, err
+}
+
+func pendingSchemaMigrations(current int) []SchemaMigration {
+	schemaMigrationsMu.RLock()
+	defer schemaMigrationsMu.RUnlock()
+
+	pending := make([]SchemaMigration, 0, len(schemaMigrations))
+	for version, m := range schemaMigrations {
+		if version > current {
+			pending = append(pending, m)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+
+	return pending
+}
@@ -0,0 +1,90 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// registerTestSchemaMigration registers migration step up for the duration of the test.
+func registerTestSchemaMigration(t *testing.T, version int, up func(ctx context.Context, l *Leaderboard) error) {
+	t.Helper()
+
+	if err := RegisterSchemaMigration(SchemaMigration{Version: version, Description: t.Name(), Up: up}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		schemaMigrationsMu.Lock()
+		delete(schemaMigrations, version)
+		schemaMigrationsMu.Unlock()
+	})
+}
+
+func TestMigrateSchemaLock(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newTestBoard(t)
+
+	registerTestSchemaMigration(t, 1, func(ctx context.Context, l *Leaderboard) error {
+		if _, err := l.MigrateSchema(ctx); !errors.Is(err, ErrSchemaMigrationLocked) {
+			t.Errorf("got error %v from concurrent migration, want %v", err, ErrSchemaMigrationLocked)
+		}
+		if ttl := mr.TTL(l.schemaLockKey()); ttl != schemaLockTTL {
+			t.Errorf("got lock TTL %v, want %v", ttl, schemaLockTTL)
+		}
+
+		// Lock expired and was taken by another instance
+		return mr.Set(l.schemaLockKey(), "other")
+	})
+
+	applied, err := l.MigrateSchema(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0] != 1 {
+		t.Fatalf("got applied %v, want [1]", applied)
+	}
+
+	if lock, err := mr.Get(l.schemaLockKey()); err != nil || lock != "other" {
+		t.Fatalf("got lock %q (%v), want lock of the other instance kept", lock, err)
+	}
+}
+
+func TestMigrateSchemaReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newTestBoard(t)
+
+	registerTestSchemaMigration(t, 1, func(ctx context.Context, l *Leaderboard) error {
+		return errors.New("failed")
+	})
+
+	if _, err := l.MigrateSchema(ctx); err == nil {
+		t.Fatal("got no error from failed migration")
+	}
+	if mr.Exists(l.schemaLockKey()) {
+		t.Fatal("lock wasn't released")
+	}
+}
+
+func TestRefreshSchemaLock(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newTestBoard(t)
+
+	if err := mr.Set(l.schemaLockKey(), "token"); err != nil {
+		t.Fatal(err)
+	}
+	mr.SetTTL(l.schemaLockKey(), time.Second)
+
+	held, err := l.refreshSchemaLock(ctx, "token")
+	if err != nil || !held {
+		t.Fatalf("got held %v (%v), want true", held, err)
+	}
+	if ttl := mr.TTL(l.schemaLockKey()); ttl != schemaLockTTL {
+		t.Fatalf("got lock TTL %v, want %v", ttl, schemaLockTTL)
+	}
+
+	held, err = l.refreshSchemaLock(ctx, "other")
+	if err != nil || held {
+		t.Fatalf("got held %v (%v) with another token, want false", held, err)
+	}
+}
@@ -0,0 +1,166 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+const (
+	// ScoringCumulative adds every submission to member's score
+	ScoringCumulative = "cumulative"
+	// ScoringBest keeps member's highest submission
+	ScoringBest = "best"
+	// ScoringLatest keeps member's most recent submission
+	ScoringLatest = "latest"
+)
+
+var allowedScoringModes = map[string]bool{
+	ScoringCumulative: true,
+	ScoringBest:       true,
+	ScoringLatest:     true,
+}
+
+// Applies submission according to scoring mode and returns member's resulting score.
+// Submissions of members locked in KEYS[2] and submissions outside submission
+// window (KEYS[4]) are rejected. Applied submissions are logged to KEYS[3].
+// When deduplication window ARGV[4] is given and deduplication key (KEYS[5]) is
+// already set, submission is ignored. Cumulative submissions are credited at
+// most what's left of earning cap.
+var submitScoreScript = redis.NewScript(scoreLogLua + submissionWindowLua + memberCapLua + earningCapLua + versionLua + `
+local mode = ARGV[1]
+local score = tonumber(ARGV[2])
+local member = ARGV[3]
+
+if redis.call("HEXISTS", KEYS[2], member) == 1 then
+	return redis.error_reply("LOCKED member's score is locked")
+end
+
+if not submissionsOpen(KEYS[4]) then
+	return redis.error_reply("CLOSED submissions are closed")
+end
+
+if not admit(KEYS[1], KEYS[4], KEYS[3], member) then
+	return redis.error_reply("FULL board reached its member cap")
+end
+
+if ARGV[4] ~= "" and not redis.call("SET", KEYS[5], 1, "NX", "PX", ARGV[4]) then
+	local current = redis.call("ZSCORE", KEYS[1], member)
+	if current then
+		return current
+	end
+end
+
+if mode == "best" then
+	local current = redis.call("ZSCORE", KEYS[1], member)
+	if current == false or score > tonumber(current) then
+		redis.call("ZADD", KEYS[1], score, member)
+	end
+	logOp(KEYS[3], "best", member, ARGV[2])
+elseif mode == "latest" then
+	redis.call("ZADD", KEYS[1], score, member)
+	logOp(KEYS[3], "set", member, ARGV[2])
+else
+	local credited = credit(member, score)
+	redis.call("ZINCRBY", KEYS[1], credited, member)
+	if credited ~= 0 then
+		logOp(KEYS[3], "incr", member, credited)
+	end
+end
+bumpVersion(KEYS[4])
+
+return redis.call("ZSCORE", KEYS[1], member)
+`)
+
+// WithScoringMode selects how SubmitScore applies submissions.
+// Unknown modes fall back to ScoringCumulative.
+func WithScoringMode(scoringMode string) Option {
+	return func(l *Leaderboard) {
+		if _, ok := allowedScoringModes[scoringMode]; !ok {
+			scoringMode = ScoringCumulative
+		}
+
+		l.scoringMode = scoringMode
+	}
+}
+
+// ScoringMode returns scoring mode board was constructed with.
+func (l *Leaderboard) ScoringMode() string {
+	return l.scoringMode
+}
+
+// SubmitScore applies score submission according to board's scoring mode:
+// added to current score (ScoringCumulative), kept only if higher than current
+// score (ScoringBest) or replacing current score (ScoringLatest).
+//
+// Unlike IncrementMemberScore, negative scores are accepted for ScoringBest and ScoringLatest.
+// Suspicious scores wait for verification, see WithPendingVerification.
+func (l *Leaderboard) SubmitScore(ctx context.Context, userID string, score int) (user User, err error) {
+	return l.SubmitScoreFrom(ctx, userID, score, "")
+}
+
+// SubmitScoreFrom is SubmitScore with submission source (e.g. game mode or service)
+// used to tell apart otherwise identical submissions when deduplication is enabled.
+func (l *Leaderboard) SubmitScoreFrom(ctx context.Context, userID string, score int, source string) (user User, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "SubmitScore", userID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if l.scoringMode == ScoringCumulative && score < 0 {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	if l.suspicious(userID, score) {
+		return l.holdPending(ctx, userID, score)
+	}
+
+	return l.submitScore(ctx, userID, score, source)
+}
+
+// submitScore applies submission to the board.
+func (l *Leaderboard) submitScore(ctx context.Context, userID string, score int, source string) (User, error) {
+	before := l.memberBefore(ctx, userID)
+
+	// Board itself stands in for deduplication key when it's disabled
+	keys := []string{l.leaderboardName, l.locksKey(), l.scoreLogKey(), l.metaKey(), l.leaderboardName}
+	args := []interface{}{l.scoringMode, score, userID, ""}
+	if l.dedupWindow > 0 {
+		keys[4] = l.dedupKey(userID, score, source)
+		args[3] = l.dedupWindow.Milliseconds()
+	}
+
+	var earnedKey string
+	if l.scoringMode == ScoringCumulative {
+		keys, args, earnedKey = l.appendEarningCap(keys, args)
+	} else {
+		args = append(args, "", "")
+	}
+
+	res, err := submitScoreScript.Run(ctx, l.client(), keys, append(args, l.scoreLogFlag())...).Text()
+	if err != nil {
+		return User{}, scriptError(err)
+	}
+	l.mirrorEarned(ctx, earnedKey, userID)
+
+	newScore, err := strconv.ParseFloat(res, 64)
+	if err != nil {
+		return User{}, err
+	}
+	l.scoreWritten(ctx, userID, newScore)
+
+	rank, err := l.memberRank(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	user := l.scoredUser(userID, newScore, rank)
+	l.emitScoreChange(ctx, before, user)
+
+	return user, nil
+}
@@ -0,0 +1,57 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubmitScoreAppliesScoringMode(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		mode string
+		want int
+	}{
+		{ScoringCumulative, 17},
+		{ScoringBest, 7},
+		{ScoringLatest, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			l, _ := newTestBoard(t, WithScoringMode(tt.mode))
+
+			var user User
+			for _, score := range []int{5, 3, 7, 2} {
+				var err error
+				if user, err = l.SubmitScore(ctx, "a", score); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if user.Score != tt.want {
+				t.Fatalf("got score %d, want %d", user.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmitScoreIgnoresDuplicates(t *testing.T) {
+	l, _ := newTestBoard(t, WithSubmissionDedup(time.Minute))
+	ctx := context.Background()
+
+	for _, source := range []string{"quests", "quests", "arena"} {
+		if _, err := l.SubmitScoreFrom(ctx, "a", 5, source); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user, err := l.GetMember(ctx, "a", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Score != 10 {
+		t.Fatalf("got score %d, want 10", user.Score)
+	}
+}
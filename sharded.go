@@ -0,0 +1,358 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"sync"
+)
+
+var (
+	ErrInvalidShardCount = errors.New("leaderboard: shard count must be positive integer")
+)
+
+// ShardedLeaderboard splits one board across multiple sorted sets
+// ("<leaderboardName>:shard:<n>") by hash of userID, so a single huge board
+// doesn't end up in one huge zset. Ranks and pages are computed by querying
+// all shards in parallel and merging the results.
+//
+// Members with equal scores are ordered by shard first (members of shard 0
+// first) and then reverse lexicographically by userID as in a single sorted
+// set, so a rank is counted with one ZCOUNT per shard.
+type ShardedLeaderboard struct {
+	PageSize int
+	board    *Leaderboard
+	shards   []string
+}
+
+// NewShardedLeaderboard is constructor for ShardedLeaderboard. Number of shards
+// must not be changed once the board holds data.
+func NewShardedLeaderboard(redisSettings RedisSettings, mode, leaderboardName, userInfoStorageHash string, pageSize, shardCount int, opts ...Option) (*ShardedLeaderboard, error) {
+	if shardCount < 1 {
+		return nil, ErrInvalidShardCount
+	}
+
+	l, err := NewLeaderboard(redisSettings, mode, leaderboardName, userInfoStorageHash, pageSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, shardCount)
+	for i := range shards {
+		shards[i] = leaderboardName + ":shard:" + strconv.Itoa(i)
+	}
+
+	return &ShardedLeaderboard{PageSize: l.PageSize, board: l, shards: shards}, nil
+}
+
+// ShardCount returns number of sorted sets the board is split across.
+func (s *ShardedLeaderboard) ShardCount() int {
+	return len(s.shards)
+}
+
+func (s *ShardedLeaderboard) shardIndex(userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(userID))
+
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+func (s *ShardedLeaderboard) shardFor(userID string) string {
+	return s.shards[s.shardIndex(userID)]
+}
+
+// FirstOrInsertMember inserts member into its shard unless it's on the board
+// already, with the same checks as Leaderboard.FirstOrInsertMember: banned
+// members are rejected, member cap, when set, limits number of members of
+// every shard and rank_changed event is emitted for inserted member.
+func (s *ShardedLeaderboard) FirstOrInsertMember(ctx context.Context, userID string, score int) (User, error) {
+	l := s.board
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	// Sharded boards aren't event sourced, so nothing is logged
+	_, inserted, err := l.insertMember(ctx, l.client(), s.shardFor(userID), userID, score, "0")
+	if err != nil {
+		return User{}, err
+	}
+
+	user, err := s.GetMember(ctx, userID, false)
+	if err != nil || !inserted {
+		return user, err
+	}
+	l.recordWrite(ctx)
+	l.emitScoreChange(ctx, User{UserID: userID, Rank: UnrankedMember}, user)
+
+	return user, nil
+}
+
+// IncrementMemberScore increments member's score in its shard with the same
+// checks as Leaderboard.IncrementMemberScore: banned members, locked scores and
+// closed submissions are rejected, earning cap is applied and rank_changed
+// event is emitted. Member cap, when set, limits number of members of every shard.
+func (s *ShardedLeaderboard) IncrementMemberScore(ctx context.Context, userID string, incrementBy int) (User, error) {
+	l := s.board
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if incrementBy < 0 {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	before := User{UserID: userID, Rank: UnrankedMember}
+	if l.eventsEnabled() {
+		if user, err := s.GetMember(ctx, userID, false); err == nil {
+			before = user
+		}
+	}
+
+	// Sharded boards aren't event sourced, so nothing is logged
+	newScore, _, err := l.runIncrement(ctx, s.shardFor(userID), userID, incrementBy, "0")
+	if err != nil {
+		return User{}, err
+	}
+	l.recordWrite(ctx)
+
+	rank, err := s.rankOf(ctx, userID, newScore)
+	if err != nil {
+		return User{}, err
+	}
+
+	user := l.scoredUser(userID, newScore, rank)
+	l.emitScoreChange(ctx, before, user)
+
+	return user, nil
+}
+
+func (s *ShardedLeaderboard) GetMember(ctx context.Context, userID string, withInfo bool) (User, error) {
+	floatScore, err := s.board.client().ZScore(ctx, s.shardFor(userID), userID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return User{UserID: userID, Rank: UnrankedMember}, nil
+		}
+
+		return User{}, err
+	}
+
+	rank, err := s.rankOf(ctx, userID, floatScore)
+	if err != nil {
+		return User{}, err
+	}
+
+	user := s.board.scoredUser(userID, floatScore, rank)
+	if withInfo {
+		info, err := s.board.GetMemberInfo(ctx, userID)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return User{}, err
+		}
+
+		user.AdditionalInfo = info
+	}
+
+	return user, nil
+}
+
+// RemoveMember removes member's score and info (unless profiles are shared
+// with other boards) in one MULTI/EXEC and reports whether member was on the board.
+func (s *ShardedLeaderboard) RemoveMember(ctx context.Context, userID string) (bool, error) {
+	profiles := s.board.profiles
+
+	var locales []string
+	if !profiles.shared {
+		var err error
+		if locales, err = profiles.Locales(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	pipe := s.board.client().TxPipeline()
+	remCmd := pipe.ZRem(ctx, s.shardFor(userID), userID)
+	if !profiles.shared {
+		pipe.HDel(ctx, profiles.hashName, userID)
+		for _, locale := range locales {
+			pipe.HDel(ctx, profiles.localeHash(locale), userID)
+		}
+	}
+	s.board.bumpVersion(ctx, pipe)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return remCmd.Val() > 0, nil
+}
+
+func (s *ShardedLeaderboard) TotalMembers(ctx context.Context) (int, error) {
+	counts := make([]int64, len(s.shards))
+	err := s.eachShard(func(i int, shard string) error {
+		n, err := s.board.client().ZCard(ctx, shard).Result()
+		counts[i] = n
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+
+	return int(total), nil
+}
+
+func (s *ShardedLeaderboard) TotalPages(ctx context.Context) int {
+	total, err := s.TotalMembers(ctx)
+	if err != nil {
+		return 0
+	}
+
+	return int(math.Ceil(float64(total) / float64(s.PageSize)))
+}
+
+// GetLeaders returns page of members ordered as described on
+// ShardedLeaderboard. Every shard returns its top page*PageSize members which
+// are then merged, so cost of a page grows with its depth and deep pages of
+// big boards are expensive; they should be read sparingly or from an export.
+func (s *ShardedLeaderboard) GetLeaders(ctx context.Context, page int) ([]User, error) {
+	totalPages := s.TotalPages(ctx)
+	if page > totalPages {
+		page = totalPages
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	startOffset := (page - 1) * s.PageSize
+	endOffset := startOffset + s.PageSize - 1
+
+	// Members of a shard which are among the global top (endOffset+1) are
+	// among top (endOffset+1) members of the shard, so merging these is exact.
+	perShard := make([][]redis.Z, len(s.shards))
+	err := s.eachShard(func(i int, shard string) error {
+		values, err := s.board.client().ZRevRangeWithScores(ctx, shard, 0, int64(endOffset)).Result()
+		perShard[i] = values
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeShardPages(perShard, endOffset+1)
+	if startOffset >= len(merged) {
+		return []User{}, nil
+	}
+
+	users := make([]User, 0, len(merged)-startOffset)
+	for i := startOffset; i < len(merged); i++ {
+		users = append(users, s.board.scoredUser(merged[i].Member.(string), merged[i].Score, i+1))
+	}
+
+	// Info of the whole page is read in one round trip
+	if err := s.board.profiles.load(ctx, s.board.client(), users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (s *ShardedLeaderboard) GetMemberInfo(ctx context.Context, userID string) ([]byte, error) {
+	return s.board.GetMemberInfo(ctx, userID)
+}
+
+func (s *ShardedLeaderboard) UpsertMemberInfo(ctx context.Context, userID string, additionalData AdditionalUserInfo) error {
+	return s.board.UpsertMemberInfo(ctx, userID, additionalData)
+}
+
+// rankOf computes 1-based rank of member with given score across all shards:
+// members ordered before it within its shard (ZREVRANK), members with higher
+// scores in other shards and members with equal scores in shards ordered
+// before its shard, each counted with one ZCOUNT.
+func (s *ShardedLeaderboard) rankOf(ctx context.Context, userID string, score float64) (int, error) {
+	scoreStr := strconv.FormatFloat(score, 'f', -1, 64)
+	own := s.shardIndex(userID)
+	ahead := make([]int64, len(s.shards))
+
+	err := s.eachShard(func(i int, shard string) error {
+		var err error
+		switch {
+		case i == own:
+			ahead[i], err = s.board.client().ZRevRank(ctx, shard, userID).Result()
+		case i < own:
+			ahead[i], err = s.board.client().ZCount(ctx, shard, scoreStr, "+inf").Result()
+		default:
+			ahead[i], err = s.board.client().ZCount(ctx, shard, "("+scoreStr, "+inf").Result()
+		}
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rank := 1
+	for _, n := range ahead {
+		rank += int(n)
+	}
+
+	return rank, nil
+}
+
+func (s *ShardedLeaderboard) eachShard(fn func(i int, shard string) error) error {
+	errs := make([]error, len(s.shards))
+
+	var wg sync.WaitGroup
+	for i, shard := range s.shards {
+		wg.Add(1)
+		go func(i int, shard string) {
+			defer wg.Done()
+			errs[i] = fn(i, shard)
+		}(i, shard)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeShardPages merges per-shard descending slices into one descending
+// slice of at most limit members, equal scores ordered by shard.
+func mergeShardPages(perShard [][]redis.Z, limit int) []redis.Z {
+	merged := make([]redis.Z, 0, limit)
+	heads := make([]int, len(perShard))
+
+	for len(merged) < limit {
+		best := -1
+		for i, values := range perShard {
+			if heads[i] >= len(values) {
+				continue
+			}
+
+			// Shard ordered first wins ties
+			if best == -1 || values[heads[i]].Score > perShard[best][heads[best]].Score {
+				best = i
+			}
+		}
+
+		if best == -1 {
+			break
+		}
+
+		merged = append(merged, perShard[best][heads[best]])
+		heads[best]++
+	}
+
+	return merged
+}
@@ -0,0 +1,130 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"strconv"
+	"testing"
+)
+
+func newTestShardedBoard(t *testing.T, shards int) *ShardedLeaderboard {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	s, err := NewShardedLeaderboard(RedisSettings{Host: mr.Addr()}, StagingMode, "test", "test_info", 10, shards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = s.board.Shutdown(context.Background())
+	})
+
+	return s
+}
+
+func TestShardedRanksMatchLeaders(t *testing.T) {
+	s := newTestShardedBoard(t, 4)
+	ctx := context.Background()
+
+	// Many ties spread over shards
+	for i := 0; i < 30; i++ {
+		if _, err := s.IncrementMemberScore(ctx, "user"+strconv.Itoa(i), i%3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for page := 1; page <= 3; page++ {
+		users, err := s.GetLeaders(ctx, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, u := range users {
+			if want := (page-1)*10 + i + 1; u.Rank != want {
+				t.Fatalf("got rank %d of %s on page %d, want %d", u.Rank, u.UserID, page, want)
+			}
+			if i > 0 && users[i-1].Score < u.Score {
+				t.Fatalf("page %d isn't ordered by score", page)
+			}
+
+			member, err := s.GetMember(ctx, u.UserID, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if member.Rank != u.Rank {
+				t.Fatalf("got rank %d of %s from GetMember, %d from GetLeaders", member.Rank, u.UserID, u.Rank)
+			}
+		}
+	}
+}
+
+func TestShardedFirstOrInsertMember(t *testing.T) {
+	s := newTestShardedBoard(t, 1)
+	ctx := context.Background()
+
+	user, err := s.FirstOrInsertMember(ctx, "a", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Score != 5 || user.Rank != 1 {
+		t.Fatalf("got %+v, want score 5 and rank 1", user)
+	}
+	if version, err := s.board.Version(ctx); err != nil || version != 1 {
+		t.Fatalf("got version %d (%v), want 1", version, err)
+	}
+
+	// Existing member keeps its score
+	if user, err = s.FirstOrInsertMember(ctx, "a", 1); err != nil || user.Score != 5 {
+		t.Fatalf("got %+v (%v), want score 5", user, err)
+	}
+
+	if err := s.board.SetMemberCap(ctx, 1, CapReject); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FirstOrInsertMember(ctx, "b", 1); !errors.Is(err, ErrBoardFull) {
+		t.Fatalf("got %v, want ErrBoardFull", err)
+	}
+}
+
+func TestShardedIncrementHonorsLocks(t *testing.T) {
+	s := newTestShardedBoard(t, 2)
+	ctx := context.Background()
+
+	if _, err := s.IncrementMemberScore(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.board.LockMemberScore(ctx, "a", "review"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.IncrementMemberScore(ctx, "a", 1); !errors.Is(err, ErrMemberScoreLocked) {
+		t.Fatalf("got %v, want ErrMemberScoreLocked", err)
+	}
+}
+
+func TestShardedRemoveMember(t *testing.T) {
+	s := newTestShardedBoard(t, 2)
+	ctx := context.Background()
+
+	if _, err := s.IncrementMemberScore(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"A"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []bool{true, false} {
+		removed, err := s.RemoveMember(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if removed != want {
+			t.Fatalf("got removed %v, want %v", removed, want)
+		}
+	}
+
+	if s.board.client().HExists(ctx, "test_info", "a").Val() {
+		t.Fatal("info of removed member was kept")
+	}
+}
@@ -0,0 +1,160 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrShuttingDown = errors.New("leaderboard: board is shutting down")
+
+// opTracker counts operations in flight so Shutdown can wait for them.
+type opTracker struct {
+	mu      sync.Mutex
+	active  int
+	closed  bool
+	drained chan struct{}
+}
+
+// begin counts operation in unless tracker is closed.
+func (t *opTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+	t.active++
+
+	return true
+}
+
+// close makes begin refuse operations from now on.
+func (t *opTracker) close() {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+}
+
+func (t *opTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.active--
+	if t.active == 0 && t.drained != nil {
+		close(t.drained)
+		t.drained = nil
+	}
+}
+
+// wait blocks until no operation is in flight or ctx is done.
+func (t *opTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.active == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+
+	if t.drained == nil {
+		t.drained = make(chan struct{})
+	}
+	drained := t.drained
+	t.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown waits until operations in flight finish (or ctx is done), stops
+// background work of the board (client side cache invalidations, freshness
+// sweeps, materialized top refreshes, pending score verification) and closes its connections. Connection
+// of a board created through a Manager is shared and stays open, see
+// Manager.Shutdown. Board must not be used afterwards: operations started
+// once Shutdown was called fail with ErrShuttingDown.
+//
+// Post-write work (mirroring, events, write stats) is done synchronously within
+// each operation, so nothing is left buffered once in-flight operations finish.
+func (l *Leaderboard) Shutdown(ctx context.Context) error {
+	l.inflight.close()
+	err := l.inflight.wait(ctx)
+
+	l.stopSweeper()
+	l.stopMaterializer()
+	l.stopPendingVerifier()
+	if l.clientCache != nil {
+		_ = l.clientCache.close()
+	}
+	l.dropReplica()
+
+	if !l.ownsClient {
+		return err
+	}
+
+	if closeErr := l.client().Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Shutdown shuts down all boards of the manager and then closes the shared
+// connection. Background work of periodic boards must be stopped first, see
+// PeriodicLeaderboard.Shutdown.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	var err error
+	for _, l := range m.Boards() {
+		if boardErr := l.Shutdown(ctx); err == nil {
+			err = boardErr
+		}
+	}
+
+	if closeErr := m.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Shutdown stops background preparation of next periods, waiting for running
+// preparation to finish or ctx to be done. Boards themselves are shut down
+// with the Manager they were created by.
+func (p *PeriodicLeaderboard) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown shuts down underlying board, see Leaderboard.Shutdown.
+func (s *ShardedLeaderboard) Shutdown(ctx context.Context) error {
+	return s.board.Shutdown(ctx)
+}
+
+// Shutdown stops background refreshing, waiting for running refresh to finish
+// or ctx to be done. Target board is shut down separately.
+func (c *ComputedBoard) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		c.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
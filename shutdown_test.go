@@ -0,0 +1,101 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdownWaitsForOperationsInFlight(t *testing.T) {
+	l, _ := newTestBoard(t)
+
+	l.inflight.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.inflight.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	ended := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(ended)
+		l.inflight.end()
+	}()
+
+	if err := l.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ended:
+	default:
+		t.Fatal("Shutdown returned before operation in flight ended")
+	}
+}
+
+func TestShutdownWaitsForMaintenance(t *testing.T) {
+	l, _ := newTestBoard(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	rebuilt := make(chan error, 1)
+	go func() {
+		rebuilt <- l.Rebuild(context.Background(), func(yield func(userID string, score int) bool) {
+			close(started)
+			<-release
+			yield("a", 1)
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v while Rebuild runs, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	if err := <-rebuilt; err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShutdownRejectsNewOperations(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t)
+	seed(t, l, "a", 1)
+
+	if err := l.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := map[string]func() error{
+		"GetMember": func() error {
+			_, err := l.GetMember(ctx, "a", false)
+			return err
+		},
+		"IncrementMemberScore": func() error {
+			_, err := l.IncrementMemberScore(ctx, "a", 1)
+			return err
+		},
+		"BulkLoad": func() error {
+			_, err := l.BulkLoad(ctx, func(yield func(member MemberScore) bool) {}, BulkLoadOptions{})
+			return err
+		},
+		"NotifyAffected": func() error {
+			_, err := l.NotifyAffected(ctx, 1)
+			return err
+		},
+	}
+
+	for name, op := range ops {
+		if err := op(); !errors.Is(err, ErrShuttingDown) {
+			t.Errorf("got %v from %s, want %v", err, name, ErrShuttingDown)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	SignatureHMACSHA256 = "hmac-sha256"
+	SignatureEd25519    = "ed25519"
+)
+
+var (
+	ErrInvalidSignature  = errors.New("leaderboard: standings signature is invalid")
+	ErrInvalidSigningKey = errors.New("leaderboard: signing key is invalid")
+)
+
+// Standing is one entry of signed standings.
+type Standing struct {
+	Rank   int    `json:"rank"`
+	UserID string `json:"user_id"`
+	Score  int    `json:"score"`
+}
+
+// Standings is content of signed standings blob.
+type Standings struct {
+	Board     string     `json:"board"`
+	SignedAt  time.Time  `json:"signed_at"`
+	Standings []Standing `json:"standings"`
+}
+
+// SignedStandings is canonical serialized standings (compact JSON with fixed
+// field order) with signature of exactly those bytes.
+type SignedStandings struct {
+	Blob      []byte `json:"blob"`
+	Signature []byte `json:"signature"`
+	Algorithm string `json:"algorithm"`
+}
+
+// SignStandings serializes top topN members of finished board and signs the
+// blob with HMAC-SHA256 using key, so prize fulfillment systems can verify
+// results weren't tampered with after finalization, see VerifyStandings.
+func (l *Leaderboard) SignStandings(ctx context.Context, topN int, key []byte) (signed SignedStandings, err error) {
+	ctx, done, err := l.startOp(ctx, "SignStandings")
+	if err != nil {
+		return SignedStandings{}, err
+	}
+	defer done(&err)
+
+	if len(key) == 0 {
+		return SignedStandings{}, ErrInvalidSigningKey
+	}
+
+	blob, err := l.standingsBlob(ctx, topN)
+	if err != nil {
+		return SignedStandings{}, err
+	}
+
+	return SignedStandings{Blob: blob, Signature: hmacSHA256(key, blob), Algorithm: SignatureHMACSHA256}, nil
+}
+
+// SignStandingsEd25519 is SignStandings signing with Ed25519 private key, so
+// verifiers only need the public key, see VerifyStandingsEd25519.
+func (l *Leaderboard) SignStandingsEd25519(ctx context.Context, topN int, key ed25519.PrivateKey) (signed SignedStandings, err error) {
+	ctx, done, err := l.startOp(ctx, "SignStandings")
+	if err != nil {
+		return SignedStandings{}, err
+	}
+	defer done(&err)
+
+	if len(key) != ed25519.PrivateKeySize {
+		return SignedStandings{}, ErrInvalidSigningKey
+	}
+
+	blob, err := l.standingsBlob(ctx, topN)
+	if err != nil {
+		return SignedStandings{}, err
+	}
+
+	return SignedStandings{Blob: blob, Signature: ed25519.Sign(key, blob), Algorithm: SignatureEd25519}, nil
+}
+
+func (l *Leaderboard) standingsBlob(ctx context.Context, topN int) ([]byte, error) {
+	if topN <= 0 {
+		return json.Marshal(Standings{Board: l.leaderboardName, SignedAt: time.Now().UTC(), Standings: []Standing{}})
+	}
+
+	// Ghosts don't win prizes, enough members are read to replace them
+	ghosts, err := l.ghostRanks(ctx, l.client())
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := l.client().ZRevRangeWithScores(ctx, l.leaderboardName, 0, int64(topN+len(ghosts)-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	standings := Standings{Board: l.leaderboardName, SignedAt: time.Now().UTC(), Standings: make([]Standing, 0, topN)}
+	for _, z := range values {
+		userID := z.Member.(string)
+		if _, ok := ghosts[userID]; ok {
+			continue
+		}
+
+		standings.Standings = append(standings.Standings, Standing{Rank: len(standings.Standings) + 1, UserID: userID, Score: l.precision.whole(z.Score)})
+		if len(standings.Standings) == topN {
+			break
+		}
+	}
+
+	return json.Marshal(standings)
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// VerifyStandings checks HMAC-SHA256 signature of standings and returns their content.
+func VerifyStandings(signed SignedStandings, key []byte) (Standings, error) {
+	if signed.Algorithm != SignatureHMACSHA256 || !hmac.Equal(signed.Signature, hmacSHA256(key, signed.Blob)) {
+		return Standings{}, ErrInvalidSignature
+	}
+
+	return parseStandings(signed.Blob)
+}
+
+// VerifyStandingsEd25519 checks Ed25519 signature of standings and returns their content.
+func VerifyStandingsEd25519(signed SignedStandings, key ed25519.PublicKey) (Standings, error) {
+	if signed.Algorithm != SignatureEd25519 || len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, signed.Blob, signed.Signature) {
+		return Standings{}, ErrInvalidSignature
+	}
+
+	return parseStandings(signed.Blob)
+}
+
+func parseStandings(blob []byte) (Standings, error) {
+	var standings Standings
+	if err := json.Unmarshal(blob, &standings); err != nil {
+		return Standings{}, err
+	}
+
+	return standings, nil
+}
@@ -0,0 +1,238 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSnapshotNotFound = errors.New("leaderboard: snapshot not found")
+)
+
+// SnapshotID identifies snapshot taken by TakeSnapshot.
+type SnapshotID string
+
+// Mover is member whose rank changed between a snapshot and now.
+type Mover struct {
+	UserID   string `json:"user_id"`
+	OldRank  int    `json:"old_rank"`
+	NewRank  int    `json:"new_rank"`
+	OldScore int    `json:"old_score"`
+	NewScore int    `json:"new_score"`
+}
+
+// PlacesMoved returns number of places member climbed (positive) or dropped (negative).
+func (m Mover) PlacesMoved() int {
+	return m.OldRank - m.NewRank
+}
+
+// MoversReport is result of Movers.
+type MoversReport struct {
+	Since SnapshotID `json:"since"`
+	// Gainers are members who climbed the most places, biggest climb first
+	Gainers []Mover `json:"gainers"`
+	// Losers are members who dropped the most places, biggest drop first
+	Losers []Mover `json:"losers"`
+}
+
+// snapshotsKey is sorted set of snapshot IDs scored by time they were taken at.
+func (l *Leaderboard) snapshotsKey() string {
+	return l.leaderboardName + ":snapshots"
+}
+
+func (l *Leaderboard) snapshotKey(id SnapshotID) string {
+	return l.leaderboardName + ":snapshot:" + string(id)
+}
+
+// TakeSnapshot copies current scores of the board server-side into a snapshot
+// which expires after ttl (0 keeps it until DeleteSnapshot), see Movers.
+func (l *Leaderboard) TakeSnapshot(ctx context.Context, ttl time.Duration) (id SnapshotID, err error) {
+	ctx, done, err := l.startOp(ctx, "TakeSnapshot")
+	if err != nil {
+		return "", err
+	}
+	defer done(&err)
+
+	return l.takeSnapshot(ctx, ttl)
+}
+
+func (l *Leaderboard) takeSnapshot(ctx context.Context, ttl time.Duration) (SnapshotID, error) {
+	now := time.Now()
+	id := SnapshotID(strconv.FormatInt(now.UnixNano(), 10))
+
+	pipe := l.client().TxPipeline()
+	pipe.ZUnionStore(ctx, l.snapshotKey(id), &redis.ZStore{Keys: []string{l.leaderboardName}})
+	if ttl > 0 {
+		pipe.Expire(ctx, l.snapshotKey(id), ttl)
+	}
+	pipe.ZAdd(ctx, l.snapshotsKey(), &redis.Z{Score: float64(now.Unix()), Member: string(id)})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Snapshots returns IDs of snapshots which haven't expired nor been deleted,
+// oldest first.
+func (l *Leaderboard) Snapshots(ctx context.Context) (_ []SnapshotID, err error) {
+	defer l.wrapError("Snapshots", "", &err)
+
+	ids, err := l.client().ZRange(ctx, l.snapshotsKey(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := l.client().Pipeline()
+	cmds := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.Exists(ctx, l.snapshotKey(SnapshotID(id)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && len(ids) > 0 {
+		return nil, err
+	}
+
+	snapshots := make([]SnapshotID, 0, len(ids))
+	var expired []interface{}
+	for i, id := range ids {
+		if cmds[i].Val() == 0 {
+			expired = append(expired, id)
+			continue
+		}
+		snapshots = append(snapshots, SnapshotID(id))
+	}
+
+	if len(expired) > 0 {
+		if err := l.client().ZRem(ctx, l.snapshotsKey(), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshots, nil
+}
+
+// DeleteSnapshot deletes snapshot.
+func (l *Leaderboard) DeleteSnapshot(ctx context.Context, id SnapshotID) (err error) {
+	done, err := l.trackOp("DeleteSnapshot")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	pipe := l.client().TxPipeline()
+	pipe.Del(ctx, l.snapshotKey(id))
+	pipe.ZRem(ctx, l.snapshotsKey(), string(id))
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// Movers returns up to topK members who climbed and dropped the most places
+// between snapshot since and now, for weekly recap content. The board is read
+// in batches with ranks of every batch looked up in the snapshot, so memory use
+// is bounded by topK and the batch size. Members who weren't in the snapshot
+// aren't movers.
+func (l *Leaderboard) Movers(ctx context.Context, since SnapshotID, topK int) (report MoversReport, err error) {
+	ctx, done, err := l.startOp(ctx, "Movers")
+	if err != nil {
+		return MoversReport{}, err
+	}
+	defer done(&err)
+
+	report = MoversReport{Since: since, Gainers: []Mover{}, Losers: []Mover{}}
+
+	cli := l.client()
+	snapshot := l.snapshotKey(since)
+	exists, err := cli.Exists(ctx, snapshot).Result()
+	if err != nil {
+		return MoversReport{}, err
+	}
+	if exists == 0 {
+		return MoversReport{}, ErrSnapshotNotFound
+	}
+
+	if topK <= 0 {
+		return report, nil
+	}
+
+	gainers := &boundedMovers{size: topK, better: func(a, b Mover) bool { return a.PlacesMoved() > b.PlacesMoved() }}
+	losers := &boundedMovers{size: topK, better: func(a, b Mover) bool { return a.PlacesMoved() < b.PlacesMoved() }}
+
+	for offset := 0; ; offset += rebuildBatchSize {
+		values, err := cli.ZRevRangeWithScores(ctx, l.leaderboardName, int64(offset), int64(offset+rebuildBatchSize-1)).Result()
+		if err != nil {
+			return MoversReport{}, err
+		}
+		if len(values) == 0 {
+			break
+		}
+
+		pipe := cli.Pipeline()
+		rankCmds := make([]*redis.IntCmd, len(values))
+		scoreCmds := make([]*redis.FloatCmd, len(values))
+		for i, z := range values {
+			rankCmds[i] = pipe.ZRevRank(ctx, snapshot, z.Member.(string))
+			scoreCmds[i] = pipe.ZScore(ctx, snapshot, z.Member.(string))
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return MoversReport{}, err
+		}
+
+		for i, z := range values {
+			oldRank, err := rankCmds[i].Result()
+			if err != nil {
+				continue
+			}
+
+			mover := Mover{
+				UserID:   z.Member.(string),
+				OldRank:  int(oldRank) + 1,
+				NewRank:  offset + i + 1,
+				OldScore: l.precision.whole(scoreCmds[i].Val()),
+				NewScore: l.precision.whole(z.Score),
+			}
+
+			switch {
+			case mover.PlacesMoved() > 0:
+				gainers.add(mover)
+			case mover.PlacesMoved() < 0:
+				losers.add(mover)
+			}
+		}
+
+		if len(values) < rebuildBatchSize {
+			break
+		}
+	}
+
+	report.Gainers = append(report.Gainers, gainers.movers...)
+	report.Losers = append(report.Losers, losers.movers...)
+
+	return report, nil
+}
+
+// boundedMovers keeps size best movers, best first.
+type boundedMovers struct {
+	size   int
+	better func(a, b Mover) bool
+	movers []Mover
+}
+
+func (b *boundedMovers) add(m Mover) {
+	i := sort.Search(len(b.movers), func(i int) bool {
+		return b.better(m, b.movers[i])
+	})
+	if i >= b.size {
+		return
+	}
+
+	if len(b.movers) < b.size {
+		b.movers = append(b.movers, Mover{})
+	}
+	copy(b.movers[i+1:], b.movers[i:])
+	b.movers[i] = m
+}
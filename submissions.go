@@ -0,0 +1,204 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	submissionsGroup = "leaderboard"
+	submissionMeta   = "meta."
+)
+
+var (
+	ErrNilVerifier = errors.New("leaderboard: submission verifier must not be nil")
+)
+
+// Submission is raw score submission stored in submission stream before it's
+// verified and applied to the board.
+type Submission struct {
+	ID          string
+	UserID      string
+	Score       int
+	Metadata    map[string]string
+	SubmittedAt time.Time
+	// RejectReason is set only on quarantined submissions
+	RejectReason string
+}
+
+// SubmissionVerifier decides whether submission may be applied to the board.
+// Rejected submissions are moved to quarantine stream together with reason.
+// Returning an error leaves submission pending so it's retried later.
+type SubmissionVerifier func(ctx context.Context, submission Submission) (approved bool, reason string, err error)
+
+// SubmissionResult summarizes one ProcessSubmissions run.
+type SubmissionResult struct {
+	Approved int
+	Rejected int
+	Failed   int
+}
+
+func (l *Leaderboard) submissionsStream() string {
+	return l.leaderboardName + ":submissions"
+}
+
+func (l *Leaderboard) quarantineStream() string {
+	return l.leaderboardName + ":submissions:quarantine"
+}
+
+// Submit stores raw submission in submission stream. It's applied to the board
+// only after it passes verification in ProcessSubmissions.
+func (l *Leaderboard) Submit(ctx context.Context, userID string, score int, metadata map[string]string) (id string, err error) {
+	ctx, done, err := l.startMemberOp(ctx, "Submit", userID)
+	if err != nil {
+		return "", err
+	}
+	defer done(&err)
+
+	if err := l.checkBanned(ctx, userID); err != nil {
+		return "", err
+	}
+
+	values := map[string]interface{}{
+		"user_id":      userID,
+		"score":        score,
+		"submitted_at": time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	for k, v := range metadata {
+		values[submissionMeta+k] = v
+	}
+
+	return l.client().XAdd(ctx, &redis.XAddArgs{Stream: l.submissionsStream(), Values: values}).Result()
+}
+
+// ProcessSubmissions reads up to count pending submissions as given consumer,
+// verifies them and applies approved ones using SubmitScore, so board's scoring
+// mode is respected. Multiple consumers may process the same board concurrently.
+func (l *Leaderboard) ProcessSubmissions(ctx context.Context, consumer string, count int64, verify SubmissionVerifier) (_ SubmissionResult, err error) {
+	done, err := l.trackOp("ProcessSubmissions")
+	if err != nil {
+		return SubmissionResult{}, err
+	}
+	defer done(&err)
+
+	var result SubmissionResult
+	if verify == nil {
+		return result, ErrNilVerifier
+	}
+
+	err = l.client().XGroupCreateMkStream(ctx, l.submissionsStream(), submissionsGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return result, err
+	}
+
+	// Entries delivered earlier but never acknowledged (failed verification or crashed consumer) go first.
+	for _, start := range []string{"0", ">"} {
+		streams, err := l.client().XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    submissionsGroup,
+			Consumer: consumer,
+			Streams:  []string{l.submissionsStream(), start},
+			Count:    count,
+			Block:    -1,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return result, err
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				if err := l.processSubmission(ctx, msg, verify, &result); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *Leaderboard) processSubmission(ctx context.Context, msg redis.XMessage, verify SubmissionVerifier, result *SubmissionResult) error {
+	submission := submissionFromMessage(msg)
+
+	approved, reason, err := verify(ctx, submission)
+	if err != nil {
+		result.Failed++
+		return nil
+	}
+
+	if approved {
+		if _, err := l.SubmitScore(ctx, submission.UserID, submission.Score); err != nil {
+			return err
+		}
+		result.Approved++
+	} else {
+		values := make(map[string]interface{}, len(msg.Values)+2)
+		for k, v := range msg.Values {
+			values[k] = v
+		}
+		values["submission_id"] = msg.ID
+		values["reject_reason"] = reason
+
+		if err := l.client().XAdd(ctx, &redis.XAddArgs{Stream: l.quarantineStream(), Values: values}).Err(); err != nil {
+			return err
+		}
+		result.Rejected++
+	}
+
+	pipe := l.client().TxPipeline()
+	pipe.XAck(ctx, l.submissionsStream(), submissionsGroup, msg.ID)
+	pipe.XDel(ctx, l.submissionsStream(), msg.ID)
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// QuarantinedSubmissions returns up to count rejected submissions, oldest first.
+func (l *Leaderboard) QuarantinedSubmissions(ctx context.Context, count int64) (_ []Submission, err error) {
+	defer l.wrapError("QuarantinedSubmissions", "", &err)
+
+	messages, err := l.client().XRangeN(ctx, l.quarantineStream(), "-", "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := make([]Submission, 0, len(messages))
+	for _, msg := range messages {
+		submissions = append(submissions, submissionFromMessage(msg))
+	}
+
+	return submissions, nil
+}
+
+func submissionFromMessage(msg redis.XMessage) Submission {
+	submission := Submission{ID: msg.ID, Metadata: map[string]string{}}
+
+	for k, v := range msg.Values {
+		value, _ := v.(string)
+
+		switch {
+		case k == "user_id":
+			submission.UserID = value
+		case k == "score":
+			submission.Score, _ = strconv.Atoi(value)
+		case k == "submitted_at":
+			ms, _ := strconv.ParseInt(value, 10, 64)
+			submission.SubmittedAt = time.Unix(0, ms*int64(time.Millisecond))
+		case k == "submission_id":
+			submission.ID = value
+		case k == "reject_reason":
+			submission.RejectReason = value
+		case strings.HasPrefix(k, submissionMeta):
+			submission.Metadata[strings.TrimPrefix(k, submissionMeta)] = value
+		}
+	}
+
+	return submission
+}
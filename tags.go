@@ -0,0 +1,271 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidTag = errors.New("leaderboard: tag must be non-empty and must not contain ':'")
+)
+
+// tagsKey is set of all tags used on the board.
+func (l *Leaderboard) tagsKey() string {
+	return l.leaderboardName + ":tags"
+}
+
+// tagKey is set of members carrying tag.
+func (l *Leaderboard) tagKey(tag string) string {
+	return l.leaderboardName + ":tag:" + tag
+}
+
+func validTag(tag string) bool {
+	return tag != "" && !strings.Contains(tag, ":")
+}
+
+// TagMember marks member with tag (e.g. "verified", "staff" or "streamer"), so UIs
+// can badge them without storing flags in AdditionalInfo. Tags are kept in
+// companion sets, one per tag, and are returned by GetLeadersWithTags.
+func (l *Leaderboard) TagMember(ctx context.Context, userID, tag string) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "TagMember", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !validTag(tag) {
+		return ErrInvalidTag
+	}
+
+	write := func(cli redis.UniversalClient) error {
+		pipe := cli.TxPipeline()
+		pipe.SAdd(ctx, l.tagKey(tag), userID)
+		pipe.SAdd(ctx, l.tagsKey(), tag)
+		_, err := pipe.Exec(ctx)
+
+		return err
+	}
+
+	if err := write(l.client()); err != nil {
+		return err
+	}
+	l.mirrorWrite(write)
+
+	return nil
+}
+
+// UntagMember removes tag from member.
+func (l *Leaderboard) UntagMember(ctx context.Context, userID, tag string) (err error) {
+	ctx, done, err := l.startMemberOp(ctx, "UntagMember", userID)
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !validTag(tag) {
+		return ErrInvalidTag
+	}
+
+	write := func(cli redis.UniversalClient) error {
+		return cli.SRem(ctx, l.tagKey(tag), userID).Err()
+	}
+
+	if err := write(l.client()); err != nil {
+		return err
+	}
+	l.mirrorWrite(write)
+
+	return nil
+}
+
+// Tags returns all tags used on the board.
+func (l *Leaderboard) Tags(ctx context.Context) (_ []string, err error) {
+	defer l.wrapError("Tags", "", &err)
+
+	tags, err := l.client().SMembers(ctx, l.tagsKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// MemberTags returns tags of member, sorted.
+func (l *Leaderboard) MemberTags(ctx context.Context, userID string) (_ []string, err error) {
+	defer l.wrapError("MemberTags", userID, &err)
+
+	users := []User{{UserID: userID}}
+	if err := l.loadTags(ctx, l.client(), users); err != nil {
+		return nil, err
+	}
+
+	return users[0].Tags, nil
+}
+
+// GetLeadersWithTags is GetLeaders with Tags of every user filled in.
+func (l *Leaderboard) GetLeadersWithTags(ctx context.Context, page int, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetLeadersWithTags")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	users, err = l.GetLeaders(ctx, page, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.loadTags(ctx, l.readClient(newReadOptions(opts)), users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// loadTags fills in Tags of users with one pipelined SISMEMBER per user and tag.
+func (l *Leaderboard) loadTags(ctx context.Context, cli redis.UniversalClient, users []User) error {
+	tags, err := l.Tags(ctx)
+	if err != nil || len(tags) == 0 || len(users) == 0 {
+		return err
+	}
+
+	pipe := cli.Pipeline()
+	cmds := make([][]*redis.BoolCmd, len(users))
+	for i, u := range users {
+		cmds[i] = make([]*redis.BoolCmd, len(tags))
+		for j, tag := range tags {
+			cmds[i][j] = pipe.SIsMember(ctx, l.tagKey(tag), u.UserID)
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	for i := range users {
+		users[i].Tags = nil
+		for j, tag := range tags {
+			if cmds[i][j].Val() {
+				users[i].Tags = append(users[i].Tags, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeTags removes all tags of member.
+func (l *Leaderboard) removeTags(ctx context.Context, cli redis.UniversalClient, userID string) error {
+	tags, err := cli.SMembers(ctx, l.tagsKey()).Result()
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+
+	pipe := cli.Pipeline()
+	for _, tag := range tags {
+		pipe.SRem(ctx, l.tagKey(tag), userID)
+	}
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// Returns ARGV[2] members (with scores) of KEYS[1] starting at ARGV[1] (0-based)
+// in ARGV[3] order (ZREVRANGE or ZRANGE), counted as if members present in any
+// of KEYS[2..] weren't on the board.
+var rangeExcludingScript = redis.NewScript(`
+local start, count, command = tonumber(ARGV[1]), tonumber(ARGV[2]), ARGV[3]
+local chunk = 200
+local offset, seen = 0, 0
+local page = {}
+
+while #page < count * 2 do
+	local members = redis.call(command, KEYS[1], offset, offset + chunk - 1, "WITHSCORES")
+	if #members == 0 then
+		break
+	end
+
+	for i = 1, #members, 2 do
+		local excluded = false
+		for k = 2, #KEYS do
+			if redis.call("SISMEMBER", KEYS[k], members[i]) == 1 then
+				excluded = true
+				break
+			end
+		end
+
+		if not excluded then
+			if seen >= start and #page < count * 2 then
+				page[#page + 1] = members[i]
+				page[#page + 1] = members[i + 1]
+			end
+			seen = seen + 1
+		end
+	end
+
+	offset = offset + chunk
+end
+
+return page
+`)
+
+// GetLeadersExcluding returns page of the board as if members carrying any of
+// tags (e.g. "staff" or "bot") weren't on it, so pages stay full and ranks stay
+// consecutive. Ranks are positions within the filtered listing. Filtering is
+// done server-side at query time, cost grows with page number and number of
+// excluded members ranked above the page.
+func (l *Leaderboard) GetLeadersExcluding(ctx context.Context, page int, tags ...string) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetLeadersExcluding")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if page < 1 {
+		page = 1
+	}
+
+	keys := []string{l.leaderboardName}
+	for _, tag := range tags {
+		if !validTag(tag) {
+			return nil, ErrInvalidTag
+		}
+		keys = append(keys, l.tagKey(tag))
+	}
+
+	startOffset := (page - 1) * l.PageSize
+	reply, err := rangeExcludingScript.Run(ctx, l.client(), keys, startOffset, l.PageSize, "ZREVRANGE").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members, ok := reply.([]interface{})
+	if !ok {
+		return nil, errUnexpectedScriptReply
+	}
+
+	users = make([]User, 0, len(members)/2)
+	for i := 0; i+1 < len(members); i += 2 {
+		score, err := strconv.ParseFloat(members[i+1].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, l.scoredUser(members[i].(string), score, startOffset+i/2+1))
+	}
+
+	if users, err = l.hideBanned(ctx, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.profiles.load(ctx, l.client(), users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,73 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestGetLeadersExcluding(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+
+	for i := 0; i < 15; i++ {
+		seed(t, l, "u"+strconv.Itoa(i), i)
+	}
+	for _, userID := range []string{"u14", "u10", "u3"} {
+		if err := l.TagMember(ctx, userID, "staff"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.TagMember(ctx, "u13", "verified"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		page int
+		tags []string
+		want []string
+	}{
+		{1, []string{"staff"}, []string{"u13", "u12", "u11", "u9", "u8", "u7", "u6", "u5", "u4", "u2"}},
+		{2, []string{"staff"}, []string{"u1", "u0"}},
+		{3, []string{"staff"}, []string{}},
+		{2, []string{"staff", "verified"}, []string{"u0"}},
+		{2, nil, []string{"u4", "u3", "u2", "u1", "u0"}},
+	}
+
+	for _, tt := range tests {
+		users, err := l.GetLeadersExcluding(ctx, tt.page, tt.tags...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalStrings(userIDs(users), tt.want) {
+			t.Fatalf("page %d excluding %v: got %v, want %v", tt.page, tt.tags, userIDs(users), tt.want)
+		}
+
+		// Ranks are consecutive positions within the filtered listing
+		for i, user := range users {
+			if want := (tt.page-1)*l.PageSize + i + 1; user.Rank != want {
+				t.Fatalf("page %d excluding %v: got rank %d of %s, want %d", tt.page, tt.tags, user.Rank, user.UserID, want)
+			}
+		}
+	}
+}
+
+func TestGetLeadersWithTags(t *testing.T) {
+	l, _ := newTestBoard(t)
+	ctx := context.Background()
+	seed(t, l, "a", 1, "b", 2)
+
+	for _, tag := range []string{"verified", "staff"} {
+		if err := l.TagMember(ctx, "a", tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	users, err := l.GetLeadersWithTags(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || len(users[0].Tags) != 0 || !equalStrings(users[1].Tags, []string{"staff", "verified"}) {
+		t.Fatalf("got %+v, want tags of a only", users)
+	}
+}
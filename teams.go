@@ -0,0 +1,139 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"sort"
+	"strconv"
+)
+
+// Contribution is share of a member in team's score.
+type Contribution struct {
+	UserID string `json:"user_id"`
+	Points int    `json:"points"`
+	// Share is fraction of team's total contributed by the member, between 0 and 1
+	Share float64 `json:"share"`
+}
+
+// TeamLeaderboard ranks teams by sum of points contributed by their members.
+// Team totals are regular members of the underlying board (team ID is used as
+// user ID), so all read APIs of Leaderboard work for teams, while each team's
+// per-member contributions are kept in "<leaderboardName>:team:<teamID>" hash.
+type TeamLeaderboard struct {
+	board *Leaderboard
+}
+
+// NewTeamLeaderboard is constructor for TeamLeaderboard keeping team totals on board.
+func NewTeamLeaderboard(board *Leaderboard) *TeamLeaderboard {
+	return &TeamLeaderboard{board: board}
+}
+
+// Board returns board holding team totals.
+func (t *TeamLeaderboard) Board() *Leaderboard {
+	return t.board
+}
+
+func (t *TeamLeaderboard) contributionsKey(teamID string) string {
+	return t.board.leaderboardName + ":team:" + teamID
+}
+
+// AddContribution credits points earned by member to their team. Team total
+// and member's contribution are updated together in one MULTI/EXEC, so
+// contributions always add up to team's score. Team with its new rank is returned.
+func (t *TeamLeaderboard) AddContribution(ctx context.Context, teamID, userID string, points int) (team User, err error) {
+	l := t.board
+	ctx, done, err := l.startMemberOp(ctx, "AddContribution", teamID)
+	if err != nil {
+		return User{}, err
+	}
+	defer done(&err)
+
+	if points < 0 {
+		return User{}, ErrIncrementByMustBePositiveInteger
+	}
+
+	before := l.memberBefore(ctx, teamID)
+
+	pipe := l.client().TxPipeline()
+	scoreCmd := pipe.ZIncrBy(ctx, l.leaderboardName, float64(points), teamID)
+	contributionCmd := pipe.HIncrBy(ctx, t.contributionsKey(teamID), userID, int64(points))
+	rankCmd := pipe.ZRevRank(ctx, l.leaderboardName, teamID)
+	l.logOps(ctx, pipe, ScoreOpIncrement, []MemberScore{{UserID: teamID, Score: points}})
+	l.bumpVersion(ctx, pipe)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return User{}, err
+	}
+
+	team = t.board.scoredUser(teamID, scoreCmd.Val(), int(rankCmd.Val())+1)
+
+	l.scoreWritten(ctx, teamID, scoreCmd.Val())
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.HSet(ctx, t.contributionsKey(teamID), userID, contributionCmd.Val()).Err()
+	})
+	l.emitScoreChange(ctx, before, team)
+
+	return team, nil
+}
+
+// GetTeamContributions returns contributions of team's members, biggest first,
+// so rewards can be split proportionally.
+func (t *TeamLeaderboard) GetTeamContributions(ctx context.Context, teamID string) (contributions []Contribution, err error) {
+	l := t.board
+	ctx, done, err := l.startMemberOp(ctx, "GetTeamContributions", teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	values, err := l.client().HGetAll(ctx, t.contributionsKey(teamID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	contributions = make([]Contribution, 0, len(values))
+	for userID, value := range values {
+		points, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+
+		total += points
+		contributions = append(contributions, Contribution{UserID: userID, Points: points})
+	}
+
+	for i := range contributions {
+		if total > 0 {
+			contributions[i].Share = float64(contributions[i].Points) / float64(total)
+		}
+	}
+
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].Points != contributions[j].Points {
+			return contributions[i].Points > contributions[j].Points
+		}
+
+		return contributions[i].UserID < contributions[j].UserID
+	})
+
+	return contributions, nil
+}
+
+// RemoveTeam removes team from the board together with its contributions.
+func (t *TeamLeaderboard) RemoveTeam(ctx context.Context, teamID string) error {
+	if _, err := t.board.RemoveMember(ctx, teamID); err != nil {
+		return err
+	}
+
+	key := t.contributionsKey(teamID)
+	write := func(cli redis.UniversalClient) error {
+		return cli.Del(ctx, key).Err()
+	}
+
+	if err := write(t.board.client()); err != nil {
+		return err
+	}
+	t.board.mirrorWrite(write)
+
+	return nil
+}
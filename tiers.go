@@ -0,0 +1,179 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTierRefreshInterval is how long ComputeTierCutoffs reuses computed cutoffs.
+const DefaultTierRefreshInterval = time.Minute
+
+var (
+	ErrInvalidPercentile = errors.New("leaderboard: percentile must be greater than 0 and at most 100")
+)
+
+// TierCutoffs holds minimum scores needed to be in top percentiles of the board.
+type TierCutoffs struct {
+	// Percentiles in ascending order, e.g. [1, 5, 10] for top 1%, 5% and 10%
+	Percentiles []float64 `json:"percentiles"`
+	// Scores[i] is the lowest score still within top Percentiles[i]
+	Scores       []int     `json:"scores"`
+	TotalMembers int       `json:"total_members"`
+	ComputedAt   time.Time `json:"computed_at"`
+}
+
+// Tier returns the smallest percentile score falls into, or 0 if it's in none
+// of them. Ties at cutoff are within the tier.
+func (t TierCutoffs) Tier(score int) float64 {
+	for i, p := range t.Percentiles {
+		if score >= t.Scores[i] {
+			return p
+		}
+	}
+
+	return 0
+}
+
+type tierCache struct {
+	mu      sync.Mutex
+	refresh time.Duration
+	entries map[string]TierCutoffs
+}
+
+// WithTierRefreshInterval sets how long ComputeTierCutoffs reuses computed
+// cutoffs, DefaultTierRefreshInterval by default.
+func WithTierRefreshInterval(interval time.Duration) Option {
+	return func(l *Leaderboard) {
+		l.tiers.refresh = interval
+	}
+}
+
+// ComputeTierCutoffs returns score cutoffs for given top percentiles (1 = top 1%),
+// so tier badges can be rendered for every user by comparing scores instead of
+// looking up each user's rank. Cutoffs are read at the percentile positions in
+// one round trip and cached for the refresh interval, see WithTierRefreshInterval.
+func (l *Leaderboard) ComputeTierCutoffs(ctx context.Context, percentiles []float64) (cutoffs TierCutoffs, err error) {
+	ctx, done, err := l.startOp(ctx, "ComputeTierCutoffs")
+	if err != nil {
+		return TierCutoffs{}, err
+	}
+	defer done(&err)
+
+	sorted := append([]float64(nil), percentiles...)
+	sort.Float64s(sorted)
+
+	keyParts := make([]string, len(sorted))
+	for i, p := range sorted {
+		if p <= 0 || p > 100 {
+			return TierCutoffs{}, ErrInvalidPercentile
+		}
+		keyParts[i] = strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	key := strings.Join(keyParts, ",")
+
+	l.tiers.mu.Lock()
+	defer l.tiers.mu.Unlock()
+
+	refresh := l.tiers.refresh
+	if refresh == 0 {
+		refresh = DefaultTierRefreshInterval
+	}
+
+	if cached, ok := l.tiers.entries[key]; ok && time.Since(cached.ComputedAt) < refresh {
+		return cached, nil
+	}
+
+	total, err := l.countMembers(ctx)
+	if err != nil {
+		return TierCutoffs{}, err
+	}
+
+	cutoffs = TierCutoffs{Percentiles: sorted, Scores: make([]int, len(sorted)), TotalMembers: total, ComputedAt: time.Now()}
+
+	if total > 0 {
+		pipe := l.readClient(readOptions{}).Pipeline()
+		cmds := make([]*redis.ZSliceCmd, len(sorted))
+		for i, p := range sorted {
+			index := int64(math.Ceil(p/100*float64(total))) - 1
+			if index < 0 {
+				index = 0
+			}
+			cmds[i] = pipe.ZRevRangeWithScores(ctx, l.leaderboardName, index, index)
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return TierCutoffs{}, err
+		}
+
+		for i, cmd := range cmds {
+			if z := cmd.Val(); len(z) > 0 {
+				cutoffs.Scores[i] = int(z[0].Score)
+			}
+		}
+	}
+
+	if l.tiers.entries == nil {
+		l.tiers.entries = map[string]TierCutoffs{}
+	}
+	l.tiers.entries[key] = cutoffs
+
+	return cutoffs, nil
+}
+
+// ScoreTier is named tier of members scoring at least MinScore, up to
+// MinScore of the next higher tier.
+type ScoreTier struct {
+	Name     string `json:"name"`
+	MinScore int    `json:"min_score"`
+}
+
+// WithTiers configures score tiers of the board, see TierPopulation.
+func WithTiers(tiers ...ScoreTier) Option {
+	return func(l *Leaderboard) {
+		l.scoreTiers = append([]ScoreTier(nil), tiers...)
+		sort.Slice(l.scoreTiers, func(i, j int) bool {
+			return l.scoreTiers[i].MinScore > l.scoreTiers[j].MinScore
+		})
+	}
+}
+
+// TierPopulation returns number of members in each tier configured with
+// WithTiers, for balancing tier thresholds. Members scoring below the lowest
+// tier aren't counted. Tiers are counted with one pipelined ZCOUNT per tier.
+func (l *Leaderboard) TierPopulation(ctx context.Context) (population map[string]int64, err error) {
+	ctx, done, err := l.startOp(ctx, "TierPopulation")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	pipe := l.readClient(readOptions{}).Pipeline()
+	cmds := make([]*redis.IntCmd, len(l.scoreTiers))
+	for i, tier := range l.scoreTiers {
+		max := "+inf"
+		if i > 0 {
+			max = "(" + strconv.Itoa(l.scoreTiers[i-1].MinScore)
+		}
+		cmds[i] = pipe.ZCount(ctx, l.leaderboardName, strconv.Itoa(tier.MinScore), max)
+	}
+
+	if len(cmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	population = make(map[string]int64, len(l.scoreTiers))
+	for i, tier := range l.scoreTiers {
+		population[tier.Name] += cmds[i].Val()
+	}
+
+	return population, nil
+}
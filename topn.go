@@ -0,0 +1,61 @@
+package go_redis_leaderboard
+
+import "context"
+
+// GetTopN returns n best members (with info), best first, without the caller
+// reasoning about pages and page sizes.
+func (l *Leaderboard) GetTopN(ctx context.Context, n int, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetTopN")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if n <= 0 {
+		return []User{}, nil
+	}
+
+	users, err = l.readRange(ctx, newReadOptions(opts), 0, n-1, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.hideBanned(ctx, users)
+}
+
+// GetBottomN returns n worst members (with info), worst first. Ranks are board
+// ranks, so the last member of the board has rank equal to number of members.
+func (l *Leaderboard) GetBottomN(ctx context.Context, n int, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetBottomN")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if n <= 0 {
+		return []User{}, nil
+	}
+
+	cli := l.readClient(newReadOptions(opts))
+	if users, err = l.queryAscending(ctx, cli, 0, n-1); err != nil {
+		return nil, err
+	}
+
+	if err := l.profiles.load(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.loadRecords(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.hydrateInfo(ctx, users); err != nil {
+		return nil, err
+	}
+
+	if err := l.markGhosts(ctx, cli, users); err != nil {
+		return nil, err
+	}
+
+	return l.hideBanned(ctx, users)
+}
@@ -0,0 +1,74 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetTopAndBottomN(t *testing.T) {
+	ctx := context.Background()
+
+	// "a" and "b" are tied, ZREVRANGE orders them "b" first
+	tests := []struct {
+		name      string
+		bottom    bool
+		n         int
+		wantIDs   []string
+		wantRanks []int
+	}{
+		{"top", false, 2, []string{"d", "c"}, []int{1, 2}},
+		{"top with ties", false, 4, []string{"d", "c", "b", "a"}, []int{1, 2, 3, 4}},
+		{"top past the end", false, 10, []string{"d", "c", "b", "a"}, []int{1, 2, 3, 4}},
+		{"top of none", false, 0, []string{}, []int{}},
+		{"bottom", true, 1, []string{"a"}, []int{4}},
+		{"bottom with ties", true, 3, []string{"a", "b", "c"}, []int{4, 3, 2}},
+		{"bottom past the end", true, 10, []string{"a", "b", "c", "d"}, []int{4, 3, 2, 1}},
+		{"bottom of negative", true, -1, []string{}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 1, "b", 1, "c", 2, "d", 5)
+
+			get := l.GetTopN
+			if tt.bottom {
+				get = l.GetBottomN
+			}
+
+			users, err := get(ctx, tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(userIDs(users), tt.wantIDs) {
+				t.Fatalf("got %v, want %v", userIDs(users), tt.wantIDs)
+			}
+
+			for i, user := range users {
+				if user.Rank != tt.wantRanks[i] {
+					t.Fatalf("got rank %d of %s, want %d", user.Rank, user.UserID, tt.wantRanks[i])
+				}
+
+				member, err := l.GetMember(ctx, user.UserID, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if member.Rank != user.Rank {
+					t.Fatalf("got rank %d of %s, GetMember says %d", user.Rank, user.UserID, member.Rank)
+				}
+			}
+		})
+	}
+}
+
+func TestGetBottomNOfEmptyBoard(t *testing.T) {
+	l, _ := newTestBoard(t)
+
+	users, err := l.GetBottomN(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("got %v, want no members", userIDs(users))
+	}
+}
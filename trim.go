@@ -0,0 +1,113 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+)
+
+// Removes members scored below ARGV[1] and returns {firstRank, members} where
+// members are removed members with scores, best first, and firstRank is 1-based
+// rank the first of them had. Trim is logged to KEYS[2] and bumps version in
+// metadata KEYS[3] when anything was removed.
+var trimBelowScoreScript = redis.NewScript(scoreLogLua + versionLua + `
+local removed = redis.call("ZREVRANGEBYSCORE", KEYS[1], "(" .. ARGV[1], "-inf", "WITHSCORES")
+local firstRank = redis.call("ZCARD", KEYS[1]) - #removed / 2 + 1
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[1])
+logOp(KEYS[2], "trim_score", "", ARGV[1])
+if #removed > 0 then
+	bumpVersion(KEYS[3])
+end
+
+return {firstRank, removed}
+`)
+
+// Removes members ranked below ARGV[1] (1-based), reply, logging and versioning
+// are the same as of trimBelowScoreScript.
+var trimBelowRankScript = redis.NewScript(scoreLogLua + versionLua + `
+local keep = tonumber(ARGV[1])
+local removed = redis.call("ZREVRANGE", KEYS[1], keep, -1, "WITHSCORES")
+redis.call("ZREMRANGEBYRANK", KEYS[1], 0, -keep - 1)
+logOp(KEYS[2], "trim_rank", "", ARGV[1])
+if #removed > 0 then
+	bumpVersion(KEYS[3])
+end
+
+return {keep + 1, removed}
+`)
+
+// TrimBelowScore removes all members with score lower than minScore, e.g. to
+// enforce qualification cutoff mid-event. Members are removed server-side in
+// one atomic step and member_removed event is emitted for each of them.
+// Users' info is kept, they may still be shown elsewhere.
+func (l *Leaderboard) TrimBelowScore(ctx context.Context, minScore float64) (removed []User, err error) {
+	ctx, done, err := l.startOp(ctx, "TrimBelowScore")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if err := l.savepoint(ctx, "TrimBelowScore"); err != nil {
+		return nil, err
+	}
+
+	return l.trim(ctx, trimBelowScoreScript, strconv.FormatFloat(minScore, 'f', -1, 64))
+}
+
+// TrimBelowRank keeps top maxRank members and removes the rest, see TrimBelowScore.
+// Members tied with the last kept member may be removed as well.
+func (l *Leaderboard) TrimBelowRank(ctx context.Context, maxRank int) (removed []User, err error) {
+	ctx, done, err := l.startOp(ctx, "TrimBelowRank")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	if maxRank < 0 {
+		maxRank = 0
+	}
+
+	if err := l.savepoint(ctx, "TrimBelowRank"); err != nil {
+		return nil, err
+	}
+
+	return l.trim(ctx, trimBelowRankScript, maxRank)
+}
+
+func (l *Leaderboard) trim(ctx context.Context, script *redis.Script, arg interface{}) ([]User, error) {
+	reply, err := script.Run(ctx, l.client(), []string{l.leaderboardName, l.scoreLogKey(), l.metaKey()}, arg, l.scoreLogFlag()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := reply.([]interface{})
+	if !ok || len(res) != 2 {
+		return nil, errUnexpectedScriptReply
+	}
+
+	firstRank, _ := res[0].(int64)
+	members, _ := res[1].([]interface{})
+
+	removed := make([]User, 0, len(members)/2)
+	events := make([]Event, 0, len(members)/2)
+	for i := 0; i+1 < len(members); i += 2 {
+		score, err := strconv.ParseFloat(members[i+1].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		user := l.scoredUser(members[i].(string), score, int(firstRank)+i/2)
+		removed = append(removed, user)
+		events = append(events, Event{Type: EventMemberRemoved, UserID: user.UserID, OldRank: user.Rank, OldScore: user.Score, NewRank: UnrankedMember})
+	}
+
+	if len(removed) == 0 {
+		return removed, nil
+	}
+
+	l.mirrorTrim(ctx, removed)
+	l.recordWrite(ctx)
+	l.emitAll(ctx, events)
+
+	return removed, nil
+}
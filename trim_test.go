@@ -0,0 +1,67 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTrim(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		trim      func(l *Leaderboard) ([]User, error)
+		wantIDs   []string
+		wantRanks []int
+	}{
+		{"below score", func(l *Leaderboard) ([]User, error) {
+			return l.TrimBelowScore(ctx, 3)
+		}, []string{"b", "a"}, []int{3, 4}},
+		{"below lowest score", func(l *Leaderboard) ([]User, error) {
+			return l.TrimBelowScore(ctx, 1)
+		}, []string{}, []int{}},
+		{"below rank", func(l *Leaderboard) ([]User, error) {
+			return l.TrimBelowRank(ctx, 1)
+		}, []string{"c", "b", "a"}, []int{2, 3, 4}},
+		{"below rank past the end", func(l *Leaderboard) ([]User, error) {
+			return l.TrimBelowRank(ctx, 10)
+		}, []string{}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t, WithEvents(0))
+			seed(t, l, "a", 1, "b", 2, "c", 3, "d", 4)
+
+			removed, err := tt.trim(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrings(userIDs(removed), tt.wantIDs) {
+				t.Fatalf("got removed %v, want %v", userIDs(removed), tt.wantIDs)
+			}
+			for i, user := range removed {
+				if user.Rank != tt.wantRanks[i] {
+					t.Fatalf("got rank %d of %s, want %d", user.Rank, user.UserID, tt.wantRanks[i])
+				}
+			}
+
+			if n := l.client().ZCard(ctx, l.leaderboardName).Val(); int(n) != 4-len(tt.wantIDs) {
+				t.Fatalf("got %d members left, want %d", n, 4-len(tt.wantIDs))
+			}
+
+			events, err := l.Events(ctx, "-", "+", 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != len(tt.wantIDs) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.wantIDs))
+			}
+			for i, event := range events {
+				if event.Type != EventMemberRemoved || event.UserID != tt.wantIDs[i] || event.OldRank != tt.wantRanks[i] {
+					t.Fatalf("got event %+v, want removal of %s from rank %d", event, tt.wantIDs[i], tt.wantRanks[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"sync"
+)
+
+const (
+	// ScoreUnitPoints are plain integer points, e.g. "1250"
+	ScoreUnitPoints = "points"
+	// ScoreUnitMilliseconds are durations, e.g. 83456 is rendered as "1:23.456"
+	ScoreUnitMilliseconds = "milliseconds"
+	// ScoreUnitPercentage are hundredths of percent, e.g. 8234 is rendered as "82.34%"
+	ScoreUnitPercentage = "percentage"
+
+	metaScoreUnit = "score_unit"
+)
+
+var (
+	ErrUnknownScoreUnit = errors.New("leaderboard: unknown score unit")
+)
+
+var allowedScoreUnits = map[string]bool{
+	ScoreUnitPoints:       true,
+	ScoreUnitMilliseconds: true,
+	ScoreUnitPercentage:   true,
+}
+
+// scoreUnitCache keeps board's score unit once it's read from metadata.
+type scoreUnitCache struct {
+	mu   sync.Mutex
+	unit string
+}
+
+// FormatScore renders score in given unit. Unknown units are rendered as points.
+func FormatScore(unit string, score int) string {
+	switch unit {
+	case ScoreUnitMilliseconds:
+		return formatMilliseconds(score)
+	case ScoreUnitPercentage:
+		sign := ""
+		if score < 0 {
+			sign, score = "-", -score
+		}
+
+		return fmt.Sprintf("%s%d.%02d%%", sign, score/100, score%100)
+	default:
+		return strconv.Itoa(score)
+	}
+}
+
+func formatMilliseconds(ms int) string {
+	sign := ""
+	if ms < 0 {
+		sign, ms = "-", -ms
+	}
+
+	hours, ms := ms/3600000, ms%3600000
+	minutes, ms := ms/60000, ms%60000
+	seconds, ms := ms/1000, ms%1000
+
+	if hours > 0 {
+		return fmt.Sprintf("%s%d:%02d:%02d.%03d", sign, hours, minutes, seconds, ms)
+	}
+
+	return fmt.Sprintf("%s%d:%02d.%03d", sign, minutes, seconds, ms)
+}
+
+// SetScoreUnit stores board's score unit in board metadata, so all instances
+// and clients render scores the same way.
+func (l *Leaderboard) SetScoreUnit(ctx context.Context, unit string) (err error) {
+	done, err := l.trackOp("SetScoreUnit")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if _, ok := allowedScoreUnits[unit]; !ok {
+		return ErrUnknownScoreUnit
+	}
+
+	if err := l.client().HSet(ctx, l.metaKey(), metaScoreUnit, unit).Err(); err != nil {
+		return err
+	}
+
+	l.scoreUnit.mu.Lock()
+	l.scoreUnit.unit = unit
+	l.scoreUnit.mu.Unlock()
+
+	return nil
+}
+
+// ScoreUnit returns board's score unit, ScoreUnitPoints if it was never set.
+// Unit is read from metadata once and then kept in memory.
+func (l *Leaderboard) ScoreUnit(ctx context.Context) (_ string, err error) {
+	defer l.wrapError("ScoreUnit", "", &err)
+
+	l.scoreUnit.mu.Lock()
+	defer l.scoreUnit.mu.Unlock()
+
+	if l.scoreUnit.unit != "" {
+		return l.scoreUnit.unit, nil
+	}
+
+	unit, err := l.client().HGet(ctx, l.metaKey(), metaScoreUnit).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			return "", err
+		}
+
+		unit = ScoreUnitPoints
+	}
+
+	l.scoreUnit.unit = unit
+
+	return unit, nil
+}
+
+// FormatScore renders score in board's score unit.
+func (l *Leaderboard) FormatScore(ctx context.Context, score int) (_ string, err error) {
+	defer l.wrapError("FormatScore", "", &err)
+
+	unit, err := l.ScoreUnit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatScore(unit, score), nil
+}
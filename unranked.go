@@ -0,0 +1,212 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// UnrankedPolicy tells how member lookups present users who aren't on the board.
+type UnrankedPolicy int
+
+const (
+	// UnrankedSentinel returns unranked users with UnrankedMember rank, the default
+	UnrankedSentinel UnrankedPolicy = iota
+	// UnrankedExclude leaves unranked users out of GetMembers and makes
+	// GetMember return ErrMemberNotFound
+	UnrankedExclude
+	// UnrankedPlaceholder returns unranked users with placeholder rank, after
+	// all ranked users in GetMembers
+	UnrankedPlaceholder
+	// UnrankedFlag returns unranked users with rank 0 and Ranked set to false
+	UnrankedFlag
+)
+
+// UnrankedOptions configures WithUnranked.
+type UnrankedOptions struct {
+	Policy UnrankedPolicy
+	// PlaceholderRank is rank used by UnrankedPlaceholder, 0 means one below
+	// the last member at the time of lookup
+	PlaceholderRank int
+}
+
+// WithUnranked sets how GetMember, GetMemberFull and GetMembers present users
+// who aren't on the board, so UnrankedMember (-1) doesn't leak into APIs.
+func WithUnranked(opts UnrankedOptions) Option {
+	return func(l *Leaderboard) {
+		l.unranked = opts
+	}
+}
+
+// GetMembers looks up many users at once in one pipelined round trip. Users
+// are returned in order of userIDs, unranked users are presented according to
+// board's UnrankedPolicy.
+func (l *Leaderboard) GetMembers(ctx context.Context, userIDs []string, withInfo bool, opts ...ReadOption) (users []User, err error) {
+	ctx, done, err := l.startOp(ctx, "GetMembers")
+	if err != nil {
+		return nil, err
+	}
+	defer done(&err)
+
+	cli := l.readClient(newReadOptions(opts))
+
+	pipe := cli.Pipeline()
+	rankCmds := make([]*redis.IntCmd, len(userIDs))
+	scoreCmds := make([]*redis.FloatCmd, len(userIDs))
+	for i, userID := range userIDs {
+		rankCmds[i] = pipe.ZRevRank(ctx, l.leaderboardName, userID)
+		scoreCmds[i] = pipe.ZScore(ctx, l.leaderboardName, userID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	users = make([]User, len(userIDs))
+	for i, userID := range userIDs {
+		rank, err := rankCmds[i].Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+			users[i] = User{UserID: userID, Rank: UnrankedMember}
+		case err != nil:
+			return nil, err
+		default:
+			users[i] = l.scoredUser(userID, scoreCmds[i].Val(), int(rank)+1)
+		}
+	}
+
+	if withInfo {
+		if err := l.profiles.load(ctx, cli, users); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.presentMembers(ctx, users)
+}
+
+// presentMember applies UnrankedPolicy to result of a single member lookup.
+func (l *Leaderboard) presentMember(ctx context.Context, user User) (User, error) {
+	users, err := l.presentMembers(ctx, []User{user})
+	if err != nil {
+		return User{}, err
+	}
+
+	if len(users) == 0 {
+		return User{}, ErrMemberNotFound
+	}
+
+	return users[0], nil
+}
+
+// presentMembers applies UnrankedPolicy to users returned by a member lookup.
+func (l *Leaderboard) presentMembers(ctx context.Context, users []User) ([]User, error) {
+	switch l.unranked.Policy {
+	case UnrankedExclude:
+		ranked := users[:0]
+		for _, u := range users {
+			if u.Rank != UnrankedMember {
+				ranked = append(ranked, u)
+			}
+		}
+
+		return ranked, nil
+	case UnrankedPlaceholder:
+		ranked := make([]User, 0, len(users))
+		var unranked []User
+		for _, u := range users {
+			if u.Rank == UnrankedMember {
+				unranked = append(unranked, u)
+			} else {
+				ranked = append(ranked, u)
+			}
+		}
+
+		if len(unranked) == 0 {
+			return users, nil
+		}
+
+		placeholder := l.unranked.PlaceholderRank
+		if placeholder == 0 {
+			total, err := l.client().ZCard(ctx, l.leaderboardName).Result()
+			if err != nil {
+				return nil, err
+			}
+			placeholder = int(total) + 1
+		}
+
+		for i := range unranked {
+			unranked[i].Rank = placeholder
+		}
+
+		return append(ranked, unranked...), nil
+	case UnrankedFlag:
+		for i := range users {
+			ranked := users[i].Rank != UnrankedMember
+			users[i].Ranked = &ranked
+			if !ranked {
+				users[i].Rank = 0
+			}
+		}
+
+		return users, nil
+	default:
+		return users, nil
+	}
+}
+
+// GetUnrankedMembers lists members who have info in board's profile store but
+// no score on the board (registered, but never scored), e.g. for onboarding
+// campaigns. Members are returned with UnrankedMember rank and their info,
+// regardless of board's UnrankedPolicy.
+//
+// Listing starts at cursor 0 and continues from returned next cursor until it's
+// 0. The info hash is scanned in whole batches, so a call may return a few more
+// than limit members.
+func (l *Leaderboard) GetUnrankedMembers(ctx context.Context, limit int, cursor uint64) (users []User, next uint64, err error) {
+	ctx, done, err := l.startOp(ctx, "GetUnrankedMembers")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer done(&err)
+
+	if limit < 1 {
+		limit = l.PageSize
+	}
+
+	cli := l.client()
+	users = []User{}
+	for {
+		fields, next, err := l.profiles.client().HScan(ctx, l.profiles.hashName, cursor, "", int64(limit)).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		pipe := cli.Pipeline()
+		scoreCmds := make([]*redis.FloatCmd, 0, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			scoreCmds = append(scoreCmds, pipe.ZScore(ctx, l.leaderboardName, fields[i]))
+		}
+		if len(scoreCmds) > 0 {
+			if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+				return nil, 0, err
+			}
+		}
+
+		for i, cmd := range scoreCmds {
+			if !errors.Is(cmd.Err(), redis.Nil) {
+				continue
+			}
+
+			info, err := decodeMemberInfo(fields[2*i+1], l.profiles.keyring)
+			if err != nil {
+				return nil, 0, err
+			}
+			users = append(users, User{UserID: fields[2*i], Rank: UnrankedMember, AdditionalInfo: info})
+		}
+
+		cursor = next
+		if cursor == 0 || len(users) >= limit {
+			return users, cursor, nil
+		}
+	}
+}
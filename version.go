@@ -0,0 +1,51 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+)
+
+const metaVersion = "version"
+
+// versionLua defines bumpVersion used by write scripts to increment board's
+// version in metadata atomically with the write.
+const versionLua = `
+local function bumpVersion(metaKey)
+	redis.call("HINCRBY", metaKey, "version", 1)
+end
+`
+
+// bumpVersion increments board's version within pipe (MULTI/EXEC) of the write.
+func (l *Leaderboard) bumpVersion(ctx context.Context, pipe redis.Pipeliner) {
+	pipe.HIncrBy(ctx, l.metaKey(), metaVersion, 1)
+}
+
+// queueBumpVersion returns bumpVersion for writes queueing their own MULTI.
+func (l *Leaderboard) queueBumpVersion(ctx context.Context) func(pipe redis.Pipeliner) {
+	return func(pipe redis.Pipeliner) {
+		l.bumpVersion(ctx, pipe)
+	}
+}
+
+// mirrorVersion increments version of migration's destination after a write.
+func (l *Leaderboard) mirrorVersion(ctx context.Context) {
+	l.mirrorWrite(func(cli redis.UniversalClient) error {
+		return cli.HIncrBy(ctx, l.metaKey(), metaVersion, 1).Err()
+	})
+}
+
+// Version returns board's change counter, incremented atomically with every
+// score write, removal and info update, so it can serve as ETag or cache key:
+// while it's unchanged, so are the board's standings and infos.
+// Boards never written return 0.
+func (l *Leaderboard) Version(ctx context.Context) (_ int64, err error) {
+	defer l.wrapError("Version", "", &err)
+
+	version, err := l.client().HGet(ctx, l.metaKey(), metaVersion).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return version, err
+}
@@ -0,0 +1,80 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVersionBumpedOncePerWrite(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		write func(l *Leaderboard) error
+		bumps int64
+	}{
+		{"IncrementMemberScore", func(l *Leaderboard) error {
+			_, err := l.IncrementMemberScore(ctx, "a", 1)
+			return err
+		}, 1},
+		{"UpsertMembers", func(l *Leaderboard) error {
+			return l.UpsertMembers(ctx, []MemberScore{{UserID: "a", Score: 3}, {UserID: "c", Score: 4}}, 0)
+		}, 1},
+		{"FirstOrInsertMembers", func(l *Leaderboard) error {
+			_, err := l.FirstOrInsertMembers(ctx, []MemberScore{{UserID: "c", Score: 3}, {UserID: "d", Score: 4}})
+			return err
+		}, 1},
+		{"RemoveMembers", func(l *Leaderboard) error {
+			_, err := l.RemoveMembers(ctx, "a", "b")
+			return err
+		}, 1},
+		{"TrimBelowScore", func(l *Leaderboard) error {
+			_, err := l.TrimBelowScore(ctx, 2)
+			return err
+		}, 1},
+		{"UpsertMemberInfo", func(l *Leaderboard) error {
+			return l.UpsertMemberInfo(ctx, "a", AdditionalUserInfo(`{"name":"a"}`))
+		}, 1},
+		{"Rebuild", func(l *Leaderboard) error {
+			return l.Rebuild(ctx, func(yield func(userID string, score int) bool) {
+				yield("c", 5)
+			})
+		}, 1},
+		{"RollbackToSavepoint", func(l *Leaderboard) error {
+			id, err := l.TakeSnapshot(ctx, time.Minute)
+			if err != nil {
+				return err
+			}
+			if _, err := l.RemoveMembers(ctx, "a"); err != nil {
+				return err
+			}
+			return l.RollbackToSavepoint(ctx, id)
+		}, 2}, // RemoveMembers before the rollback bumps as well
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 1, "b", 2)
+
+			before, err := l.Version(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.write(l); err != nil {
+				t.Fatal(err)
+			}
+
+			after, err := l.Version(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if after != before+tt.bumps {
+				t.Fatalf("got version %d, want %d", after, before+tt.bumps)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package go_redis_leaderboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webhooksGroup = "webhooks"
+
+	DefaultWebhookMaxAttempts = 5
+	DefaultWebhookBackoff     = 500 * time.Millisecond
+	DefaultWebhookTimeout     = 10 * time.Second
+
+	// Headers of webhook requests
+	WebhookSignatureHeader = "X-Leaderboard-Signature"
+	WebhookTimestampHeader = "X-Leaderboard-Timestamp"
+	WebhookEventHeader     = "X-Leaderboard-Event"
+	WebhookDeliveryHeader  = "X-Leaderboard-Delivery"
+)
+
+var (
+	ErrMissingWebhookURL = errors.New("leaderboard: webhook URL must not be empty")
+)
+
+// WebhookOptions configures DeliverEvents.
+type WebhookOptions struct {
+	// URL every event is POSTed to as JSON
+	URL string
+	// Secret signs requests, see DeliverEvents. Requests aren't signed when empty.
+	Secret []byte
+	// Client sends requests, client with DefaultWebhookTimeout by default
+	Client *http.Client
+	// MaxAttempts is number of deliveries tried before event is dead-lettered
+	MaxAttempts int
+	// Backoff is delay before the first retry, doubled for every next one
+	Backoff time.Duration
+}
+
+func (o *WebhookOptions) normalize() {
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultWebhookBackoff
+	}
+}
+
+// WebhookResult summarizes one DeliverEvents run.
+type WebhookResult struct {
+	Delivered    int
+	DeadLettered int
+}
+
+// DeadLetter is event which couldn't be delivered, see DeadLetteredEvents.
+type DeadLetter struct {
+	Event
+	Attempts int
+	// Error is the last delivery error
+	Error string
+}
+
+func (l *Leaderboard) deadLetterStream() string {
+	return l.leaderboardName + ":events:dead"
+}
+
+// DeliverEvents reads up to count undelivered events of the board as given
+// consumer and POSTs each of them as JSON to opts.URL, so services without a
+// Redis client (chat bots, CRMs) can consume them. Delivery starts with events
+// emitted after the first call. Multiple consumers may deliver events of the
+// same board concurrently.
+//
+// Requests carry event type and ID in WebhookEventHeader and
+// WebhookDeliveryHeader. With opts.Secret set, WebhookSignatureHeader holds
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the secret, timestamp
+// (Unix seconds) being sent in WebhookTimestampHeader, so receivers can
+// verify requests and reject replays.
+//
+// Network errors, 408, 429 and 5xx responses are retried with exponential
+// backoff up to opts.MaxAttempts, other responses fail at once. Events which
+// couldn't be delivered are moved to "<leaderboardName>:events:dead" stream,
+// see DeadLetteredEvents.
+func (l *Leaderboard) DeliverEvents(ctx context.Context, consumer string, count int64, opts WebhookOptions) (_ WebhookResult, err error) {
+	done, err := l.trackOp("DeliverEvents")
+	if err != nil {
+		return WebhookResult{}, err
+	}
+	defer done(&err)
+
+	var result WebhookResult
+	if opts.URL == "" {
+		return result, ErrMissingWebhookURL
+	}
+
+	if !l.eventsEnabled() {
+		return result, ErrEventsDisabled
+	}
+	opts.normalize()
+
+	err = l.client().XGroupCreateMkStream(ctx, l.eventsStream(), webhooksGroup, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return result, err
+	}
+
+	// Events delivered to consumer earlier but never acknowledged (crashed consumer) go first.
+	for _, start := range []string{"0", ">"} {
+		streams, err := l.client().XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    webhooksGroup,
+			Consumer: consumer,
+			Streams:  []string{l.eventsStream(), start},
+			Count:    count,
+			Block:    -1,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+
+			return result, err
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				if err := l.deliverEvent(ctx, msg, opts, &result); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *Leaderboard) deliverEvent(ctx context.Context, msg redis.XMessage, opts WebhookOptions, result *WebhookResult) error {
+	body, err := json.Marshal(eventFromMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	attempts, deliveryErr := 0, error(nil)
+	backoff := opts.Backoff
+	for attempts < opts.MaxAttempts {
+		if attempts > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				// Event stays pending and is retried by next run
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		attempts++
+
+		var retry bool
+		if retry, deliveryErr = postEvent(ctx, msg, body, opts); deliveryErr == nil || !retry {
+			break
+		}
+	}
+
+	pipe := l.client().TxPipeline()
+	if deliveryErr != nil {
+		values := make(map[string]interface{}, len(msg.Values)+3)
+		for k, v := range msg.Values {
+			values[k] = v
+		}
+		values["event_id"] = msg.ID
+		values["attempts"] = attempts
+		values["error"] = deliveryErr.Error()
+
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: l.deadLetterStream(), MaxLenApprox: l.eventsMaxLen, Values: values})
+	}
+	pipe.XAck(ctx, l.eventsStream(), webhooksGroup, msg.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	if deliveryErr != nil {
+		result.DeadLettered++
+	} else {
+		result.Delivered++
+	}
+
+	return nil
+}
+
+// postEvent sends one delivery attempt and reports whether failed attempt may be retried.
+func postEvent(ctx context.Context, msg redis.XMessage, body []byte, opts WebhookOptions) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	eventType, _ := msg.Values["type"].(string)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookDeliveryHeader, msg.ID)
+
+	if len(opts.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(hmacSHA256(opts.Secret, append([]byte(timestamp+"."), body...))))
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	// Body is drained so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, errors.New("webhook responded " + resp.Status)
+	}
+
+	return false, errors.New("webhook responded " + resp.Status)
+}
+
+// DeadLetteredEvents returns up to count events which couldn't be delivered by
+// DeliverEvents, oldest first. Events can be redelivered by the caller and
+// removed with XDEL from "<leaderboardName>:events:dead".
+func (l *Leaderboard) DeadLetteredEvents(ctx context.Context, count int64) (_ []DeadLetter, err error) {
+	defer l.wrapError("DeadLetteredEvents", "", &err)
+
+	messages, err := l.client().XRangeN(ctx, l.deadLetterStream(), "-", "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0, len(messages))
+	for _, msg := range messages {
+		letter := DeadLetter{Event: eventFromMessage(msg)}
+		letter.ID, _ = msg.Values["event_id"].(string)
+		letter.Error, _ = msg.Values["error"].(string)
+		attempts, _ := msg.Values["attempts"].(string)
+		letter.Attempts, _ = strconv.Atoi(attempts)
+
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
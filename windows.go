@@ -0,0 +1,100 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	metaSubmissionsOpenAt  = "submissions_open_at"
+	metaSubmissionsCloseAt = "submissions_close_at"
+
+	// closedReply is prefix of error reply of write scripts outside submission window.
+	closedReply = "CLOSED"
+)
+
+var (
+	ErrSubmissionsClosed       = errors.New("leaderboard: board doesn't accept scores at this time")
+	ErrInvalidSubmissionWindow = errors.New("leaderboard: submission window must close after it opens")
+)
+
+// submissionWindowLua defines submissionsOpen used by write scripts to check
+// submission window stored in board metadata against Redis server time, so all
+// instances agree regardless of their clocks.
+const submissionWindowLua = `
+local function submissionsOpen(metaKey)
+	local window = redis.call("HMGET", metaKey, "submissions_open_at", "submissions_close_at")
+	if not window[1] and not window[2] then
+		return true
+	end
+
+	local t = redis.call("TIME")
+	local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+	if window[1] and now < tonumber(window[1]) then
+		return false
+	end
+
+	return not (window[2] and now >= tonumber(window[2]))
+end
+`
+
+// SetSubmissionWindow makes board accept score changes (increments,
+// submissions and CAS updates) only between openAt (inclusive) and closeAt
+// (exclusive), other writes fail with ErrSubmissionsClosed. Zero time leaves
+// that side of the window unbounded, both zero remove the window. Window is
+// stored in board metadata and checked against Redis server time within the
+// write itself.
+//
+// Inserting new members (FirstOrInsertMember) and maintenance operations
+// aren't restricted.
+func (l *Leaderboard) SetSubmissionWindow(ctx context.Context, openAt, closeAt time.Time) (err error) {
+	done, err := l.trackOp("SetSubmissionWindow")
+	if err != nil {
+		return err
+	}
+	defer done(&err)
+
+	if !openAt.IsZero() && !closeAt.IsZero() && !closeAt.After(openAt) {
+		return ErrInvalidSubmissionWindow
+	}
+
+	pipe := l.client().TxPipeline()
+	for field, t := range map[string]time.Time{metaSubmissionsOpenAt: openAt, metaSubmissionsCloseAt: closeAt} {
+		if t.IsZero() {
+			pipe.HDel(ctx, l.metaKey(), field)
+		} else {
+			pipe.HSet(ctx, l.metaKey(), field, t.UnixNano()/int64(time.Millisecond))
+		}
+	}
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// SubmissionWindow returns board's submission window, zero times for unbounded sides.
+func (l *Leaderboard) SubmissionWindow(ctx context.Context) (openAt, closeAt time.Time, err error) {
+	defer l.wrapError("SubmissionWindow", "", &err)
+
+	values, err := l.client().HMGet(ctx, l.metaKey(), metaSubmissionsOpenAt, metaSubmissionsCloseAt).Result()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	times := make([]time.Time, 2)
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+
+		ms, err := strconv.ParseInt(v.(string), 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		times[i] = time.Unix(0, ms*int64(time.Millisecond))
+	}
+
+	return times[0], times[1], nil
+}
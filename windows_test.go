@@ -0,0 +1,81 @@
+package go_redis_leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSubmissionWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		openAt    time.Time
+		closeAt   time.Time
+		wantErr   error
+		wantScore int
+	}{
+		{"open", now.Add(-time.Hour), now.Add(time.Hour), nil, 6},
+		{"not open yet", now.Add(time.Hour), time.Time{}, ErrSubmissionsClosed, 1},
+		{"closed", time.Time{}, now.Add(-time.Hour), ErrSubmissionsClosed, 1},
+		{"unbounded", time.Time{}, time.Time{}, nil, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestBoard(t)
+			seed(t, l, "a", 1)
+
+			if err := l.SetSubmissionWindow(ctx, tt.openAt, tt.closeAt); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := l.IncrementMemberScore(ctx, "a", 5); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if score := int(l.client().ZScore(ctx, l.leaderboardName, "a").Val()); score != tt.wantScore {
+				t.Fatalf("got score %d, want %d", score, tt.wantScore)
+			}
+
+			// Inserting new members isn't restricted
+			if _, err := l.FirstOrInsertMember(ctx, "b", 2); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSetSubmissionWindow(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newTestBoard(t)
+
+	openAt := time.Unix(1700000000, 0)
+	if err := l.SetSubmissionWindow(ctx, openAt, openAt); !errors.Is(err, ErrInvalidSubmissionWindow) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidSubmissionWindow)
+	}
+
+	closeAt := openAt.Add(time.Hour)
+	if err := l.SetSubmissionWindow(ctx, openAt, closeAt); err != nil {
+		t.Fatal(err)
+	}
+	gotOpen, gotClose, err := l.SubmissionWindow(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotOpen.Equal(openAt) || !gotClose.Equal(closeAt) {
+		t.Fatalf("got window %v - %v, want %v - %v", gotOpen, gotClose, openAt, closeAt)
+	}
+
+	if err := l.SetSubmissionWindow(ctx, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if gotOpen, gotClose, err = l.SubmissionWindow(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !gotOpen.IsZero() || !gotClose.IsZero() {
+		t.Fatalf("got window %v - %v after removal, want unbounded", gotOpen, gotClose)
+	}
+}